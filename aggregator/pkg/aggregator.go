@@ -42,12 +42,13 @@ type BatchData struct {
 
 type Aggregator struct {
 	AggregatorConfig      *config.AggregatorConfig
-	NewBatchChan          chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
+	NewBatchChan          chan *chainio.NewBatch
 	avsReader             *chainio.AvsReader
 	avsSubscriber         *chainio.AvsSubscriber
 	avsWriter             *chainio.AvsWriter
 	taskSubscriber        chan error
 	blsAggregationService blsagg.BlsAggregationService
+	responseValidator     *ResponseValidator
 
 	// BLS Signature Service returns an Index
 	// Since our ID is not an idx, we build this cache
@@ -67,6 +68,10 @@ type Aggregator struct {
 	// Stores the TaskResponse for each batch by batchIdentifierHash
 	batchDataByIdentifierHash map[[32]byte]BatchData
 
+	// Stores why the BLS aggregation of each finished batch ended by batch index,
+	// ErrQuorumReached or ErrTaskExpired, to reply to operators that respond late
+	batchFinishedByIdx map[uint32]error
+
 	// This task index is to communicate with the local BLS
 	// Service.
 	// Note: In case of a reboot it can start from 0 again
@@ -77,6 +82,7 @@ type Aggregator struct {
 	// - batchesIdxByIdentifierHash
 	// - batchCreatedBlockByIdx
 	// - batchDataByIdentifierHash
+	// - batchFinishedByIdx
 	// - nextBatchIndex
 	taskMutex *sync.Mutex
 
@@ -94,7 +100,7 @@ type Aggregator struct {
 }
 
 func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error) {
-	newBatchChan := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3)
+	newBatchChan := make(chan *chainio.NewBatch)
 
 	logger := aggregatorConfig.BaseConfig.Logger
 
@@ -124,6 +130,7 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 	batchesIdxByIdentifierHash := make(map[[32]byte]uint32)
 	batchDataByIdentifierHash := make(map[[32]byte]BatchData)
 	batchCreatedBlockByIdx := make(map[uint32]uint64)
+	batchFinishedByIdx := make(map[uint32]error)
 
 	chainioConfig := sdkclients.BuildAllConfig{
 		EthHttpUrl:                 aggregatorConfig.BaseConfig.EthRpcUrl,
@@ -172,11 +179,13 @@ func NewAggregator(aggregatorConfig config.AggregatorConfig) (*Aggregator, error
 		batchesIdxByIdentifierHash: batchesIdxByIdentifierHash,
 		batchDataByIdentifierHash:  batchDataByIdentifierHash,
 		batchCreatedBlockByIdx:     batchCreatedBlockByIdx,
+		batchFinishedByIdx:         batchFinishedByIdx,
 		nextBatchIndex:             nextBatchIndex,
 		taskMutex:                  &sync.Mutex{},
 		walletMutex:                &sync.Mutex{},
 
 		blsAggregationService: blsAggregationService,
+		responseValidator:     NewResponseValidator(avsRegistryService, eigentypes.QuorumNum(QUORUM_NUMBER)),
 		logger:                logger,
 		metricsReg:            reg,
 		metrics:               aggregatorMetrics,
@@ -196,6 +205,15 @@ func (agg *Aggregator) Start(ctx context.Context) error {
 		}
 	}()
 
+	if agg.AggregatorConfig.Aggregator.OperatorRpcServerIpPortAddress != "" {
+		go func() {
+			err := agg.ServeOperatorsRpc()
+			if err != nil {
+				agg.logger.Fatal("Error serving the operator RPC protocol", "err", err)
+			}
+		}()
+	}
+
 	var metricsErrChan <-chan error
 	if agg.AggregatorConfig.Aggregator.EnableMetrics {
 		metricsErrChan = agg.metrics.Start(ctx, agg.metricsReg)
@@ -233,6 +251,11 @@ func (agg *Aggregator) handleBlsAggServiceResponse(blsAggServiceResp blsagg.BlsA
 	batchIdentifierHash := agg.batchesIdentifierHashByIdx[blsAggServiceResp.TaskIndex]
 	batchData := agg.batchDataByIdentifierHash[batchIdentifierHash]
 	taskCreatedBlock := agg.batchCreatedBlockByIdx[blsAggServiceResp.TaskIndex]
+	if blsAggServiceResp.Err != nil {
+		agg.batchFinishedByIdx[blsAggServiceResp.TaskIndex] = fmt.Errorf("%w: %v", ErrTaskExpired, blsAggServiceResp.Err)
+	} else {
+		agg.batchFinishedByIdx[blsAggServiceResp.TaskIndex] = ErrQuorumReached
+	}
 	agg.taskMutex.Unlock()
 	agg.AggregatorConfig.BaseConfig.Logger.Info("- Unlocked Resources: Fetching task data")
 
@@ -447,6 +470,8 @@ func (agg *Aggregator) ClearTasksFromMaps() {
 				delete(agg.batchCreatedBlockByIdx, i)
 				delete(agg.batchesIdentifierHashByIdx, i)
 				delete(agg.batchDataByIdentifierHash, batchIdentifierHash)
+				delete(agg.batchFinishedByIdx, i)
+				agg.responseValidator.RemoveTask(i)
 			} else {
 				agg.logger.Warn("Task not found in maps", "taskIndex", i)
 			}
@@ -3,12 +3,15 @@ package pkg
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/rpc"
 	"time"
 
+	blsagg "github.com/Layr-Labs/eigensdk-go/services/bls_aggregation"
 	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/aggregatorrpc"
 	"github.com/yetanotherco/aligned_layer/core/types"
 )
 
@@ -34,15 +37,67 @@ func (agg *Aggregator) ServeOperators() error {
 	return err
 }
 
+// ServeOperatorsRpc serves the versioned JSON-RPC protocol of aggregatorrpc, next to the legacy
+// net/rpc server of ServeOperators, which is kept for operators that didn't migrate yet.
+func (agg *Aggregator) ServeOperatorsRpc() error {
+	server, err := aggregatorrpc.NewServer(operatorRpcService{agg}, aggregatorrpc.ServerConfig{
+		Address:        agg.AggregatorConfig.Aggregator.OperatorRpcServerIpPortAddress,
+		TLSCertFile:    agg.AggregatorConfig.Aggregator.OperatorRpcTlsCertFile,
+		TLSKeyFile:     agg.AggregatorConfig.Aggregator.OperatorRpcTlsKeyFile,
+		ClientCAFile:   agg.AggregatorConfig.Aggregator.OperatorRpcClientCaFile,
+		RequestTimeout: agg.AggregatorConfig.Aggregator.OperatorRpcRequestTimeout,
+	}, agg.logger)
+	if err != nil {
+		return err
+	}
+	return server.ListenAndServe()
+}
+
+// operatorRpcService processes the requests of the JSON-RPC protocol. It is not the Aggregator
+// itself so its methods are not registered in the legacy net/rpc server.
+type operatorRpcService struct {
+	agg *Aggregator
+}
+
+func (s operatorRpcService) ProcessSignedTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) types.SignedTaskResponseReply {
+	return TaskResponseReply(s.agg.processSignedTaskResponse(ctx, signedTaskResponse))
+}
+
 // Aggregator Methods
 // This is the list of methods that the Aggregator exposes to the Operator
 // The Operator can call these methods to interact with the Aggregator
 // This methods are automatically registered by the RPC server
 // This takes a response an adds it to the internal. If reaching the quorum, it sends the aggregated signatures to ethereum
+// Kept for operators that don't know ProcessOperatorSignedTaskResponseV3, which they retry on errors.
 // Returns:
-//   - 0: Success
-//   - 1: Error
+//   - 0: The response was accepted, or there is no use in sending it again
+//   - 1: The aggregator could not process the response yet
 func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *types.SignedTaskResponse, reply *uint8) error {
+	*reply = 0
+	switch TaskResponseReply(agg.processSignedTaskResponse(context.Background(), signedTaskResponse)).Outcome {
+	case types.TaskResponseUnknownTask, types.TaskResponseInternalError:
+		*reply = 1
+	}
+	return nil
+}
+
+// ProcessOperatorSignedTaskResponseV3 takes a response and adds it to the aggregation of its task.
+// The reply tells the operator whether it was accepted, or why not, so it knows whether to send it again.
+func (agg *Aggregator) ProcessOperatorSignedTaskResponseV3(signedTaskResponse *types.SignedTaskResponse, reply *types.SignedTaskResponseReply) error {
+	*reply = TaskResponseReply(agg.processSignedTaskResponse(context.Background(), signedTaskResponse))
+	return nil
+}
+
+// processSignedTaskResponse adds the response to the BLS aggregation of its task. It returns an error
+// if the task is unknown or finished, the response is rejected by the response validator or by the
+// BLS aggregation service. Every response is counted in the metrics of its operator, once it is
+// found registered.
+func (agg *Aggregator) processSignedTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) (err error) {
+	registered := false
+	defer func() {
+		agg.metrics.IncAggregatorOperatorResponses(operatorLabel(signedTaskResponse.OperatorId, registered), ResponseResult(err))
+	}()
+
 	agg.AggregatorConfig.BaseConfig.Logger.Info("New task response",
 		"BatchMerkleRoot", "0x"+hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
 		"SenderAddress", "0x"+hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
@@ -54,22 +109,39 @@ func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *t
 	// If that's the case, we won't know about the task at this point
 	// so we make GetTaskIndex retryable, waiting for some seconds,
 	// before trying to fetch the task again from the map.
-	taskIndex, err := agg.GetTaskIndexRetryable(signedTaskResponse.BatchIdentifierHash, retry.NetworkRetryParams())
+	taskIndex, err = agg.GetTaskIndexRetryable(signedTaskResponse.BatchIdentifierHash, retry.NetworkRetryParams())
 
 	if err != nil {
 		agg.logger.Warn("Task not found in the internal map, operator signature will be lost. Batch may not reach quorum")
-		*reply = 1
-		return nil
+		return ErrTaskNotFound
+	}
+
+	agg.taskMutex.Lock()
+	taskCreatedBlock := agg.batchCreatedBlockByIdx[taskIndex]
+	taskFinished := agg.batchFinishedByIdx[taskIndex]
+	agg.taskMutex.Unlock()
+	if taskFinished != nil {
+		agg.logger.Info("Task response received after the task finished", "taskIndex", taskIndex, "reason", taskFinished)
+		return taskFinished
+	}
+
+	// Rejected before the BLS aggregation service, which would count the stake of duplicates again
+	err = agg.responseValidator.Validate(ctx, taskIndex, uint32(taskCreatedBlock), signedTaskResponse)
+	registered = operatorRegistered(err)
+	if err != nil {
+		agg.logger.Warn("Task response rejected", "taskIndex", taskIndex,
+			"operatorId", hex.EncodeToString(signedTaskResponse.OperatorId[:]), "err", err)
+		return err
 	}
 	agg.telemetry.LogOperatorResponse(signedTaskResponse.BatchMerkleRoot, signedTaskResponse.OperatorId)
 
 	// Don't wait infinitely if it can't answer
 	// Create a context with a timeout of 5 seconds
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel() // Ensure the cancel function is called to release resources
 
-	// Create a channel to signal when the task is done
-	done := make(chan struct{})
+	// Create a channel to receive the result of the task
+	done := make(chan error, 1)
 
 	agg.logger.Info("Starting bls signature process")
 	go func() {
@@ -80,27 +152,44 @@ func (agg *Aggregator) ProcessOperatorSignedTaskResponseV2(signedTaskResponse *t
 
 		if err != nil {
 			agg.logger.Warnf("BLS aggregation service error: %s", err)
-			// todo shouldn't we here close the channel with a reply = 1?
+			// The response was not aggregated, so the operator can send it again
+			agg.responseValidator.Forget(taskIndex, signedTaskResponse.OperatorId)
+			err = agg.blsAggregationError(taskIndex, err)
 		} else {
 			agg.logger.Info("BLS process succeeded")
 		}
 
-		close(done)
+		done <- err
 	}()
 
-	*reply = 1
 	// Wait for either the context to be done or the task to complete
 	select {
 	case <-ctx.Done():
-		// The context's deadline was exceeded or it was canceled
-		agg.logger.Info("Bls process timed out, operator signature will be lost. Batch may not reach quorum")
-	case <-done:
-		// The task completed successfully
-		agg.logger.Info("Bls context finished correctly")
-		*reply = 0
+		// The response is still queued in the BLS aggregation service, which aggregates it unless the task
+		// finishes first. Sending it again would only be rejected as a duplicate, so it is taken as accepted.
+		agg.logger.Info("Bls process timed out, task response is still queued for aggregation")
+		return nil
+	case err := <-done:
+		if err == nil {
+			agg.logger.Info("Bls context finished correctly")
+		}
+		return err
 	}
+}
 
-	return nil
+// blsAggregationError returns why the BLS aggregation service didn't aggregate a response of the task.
+// It only rejects responses the validator accepted if the task finished, or the signature didn't verify.
+func (agg *Aggregator) blsAggregationError(taskIndex uint32, err error) error {
+	agg.taskMutex.Lock()
+	taskFinished := agg.batchFinishedByIdx[taskIndex]
+	agg.taskMutex.Unlock()
+	if taskFinished != nil {
+		return taskFinished
+	}
+	if errors.Is(err, blsagg.IncorrectSignatureError) {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return fmt.Errorf("bls aggregation service error: %w", err)
 }
 
 // Dummy method to check if the server is running
@@ -1,5 +1,11 @@
 package pkg
 
+import "github.com/yetanotherco/aligned_layer/core/chainio"
+
+// The aggregator only creates tasks for batches of this version of the NewBatch event,
+// the operator follows the rest of the versions too
+const aggregatedEventVersion = 3
+
 func (agg *Aggregator) SubscribeToNewTasks() error {
 	err := agg.subscribeToNewTasks()
 	if err != nil {
@@ -15,6 +21,10 @@ func (agg *Aggregator) SubscribeToNewTasks() error {
 				return err
 			}
 		case newBatch := <-agg.NewBatchChan:
+			if !isAggregatedBatch(newBatch) {
+				agg.AggregatorConfig.BaseConfig.Logger.Debugf("Ignoring NewBatchV%d batch %x", newBatch.EventVersion, newBatch.BatchMerkleRoot)
+				continue
+			}
 			agg.AggregatorConfig.BaseConfig.Logger.Info("Adding new task")
 			agg.AddNewTask(newBatch.BatchMerkleRoot, newBatch.SenderAddress, newBatch.TaskCreatedBlock)
 		}
@@ -24,7 +34,7 @@ func (agg *Aggregator) SubscribeToNewTasks() error {
 func (agg *Aggregator) subscribeToNewTasks() error {
 	var err error
 
-	agg.taskSubscriber, err = agg.avsSubscriber.SubscribeToNewTasksV3(agg.NewBatchChan)
+	agg.taskSubscriber, err = agg.avsSubscriber.SubscribeToNewBatches(agg.NewBatchChan)
 
 	if err != nil {
 		agg.AggregatorConfig.BaseConfig.Logger.Info("Failed to create task subscriber", "err", err)
@@ -32,3 +42,8 @@ func (agg *Aggregator) subscribeToNewTasks() error {
 
 	return err
 }
+
+// isAggregatedBatch reports whether the aggregator creates a task for the batch.
+func isAggregatedBatch(newBatch *chainio.NewBatch) bool {
+	return newBatch.EventVersion == aggregatedEventVersion
+}
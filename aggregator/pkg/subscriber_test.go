@@ -0,0 +1,16 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+func TestOnlyNewBatchV3IsAggregated(t *testing.T) {
+	if !isAggregatedBatch(&chainio.NewBatch{EventVersion: 3}) {
+		t.Errorf("expected NewBatchV3 batches to be aggregated")
+	}
+	if isAggregatedBatch(&chainio.NewBatch{EventVersion: 2}) {
+		t.Errorf("expected NewBatchV2 batches not to be aggregated")
+	}
+}
@@ -0,0 +1,212 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+var (
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrOperatorNotRegistered = errors.New("operator is not registered in the quorum at the task block")
+	ErrInvalidSignature      = errors.New("signature doesn't verify against the operator public key")
+	ErrDuplicateResponse     = errors.New("a response of the operator was already counted for the task")
+	ErrQuorumReached         = errors.New("task already reached quorum")
+	ErrTaskExpired           = errors.New("task expired")
+)
+
+// Results of the task responses received, as reported in the aggregator_operator_responses metric
+const (
+	ResponseAccepted              = "accepted"
+	ResponseUnknownTask           = "unknown_task"
+	ResponseOperatorNotRegistered = "operator_not_registered"
+	ResponseInvalidSignature      = "invalid_signature"
+	ResponseDuplicate             = "duplicate"
+	ResponseQuorumReached         = "quorum_reached"
+	ResponseTaskExpired           = "task_expired"
+	ResponseInternalError         = "internal_error"
+)
+
+// Responses whose operator was not found registered are counted under this operator ID,
+// as anyone can send them with any ID
+const unregisteredOperatorLabel = "unregistered"
+
+// ResponseResult returns how the result of processing a response with err is reported in metrics.
+func ResponseResult(err error) string {
+	switch {
+	case err == nil:
+		return ResponseAccepted
+	case errors.Is(err, ErrTaskNotFound):
+		return ResponseUnknownTask
+	case errors.Is(err, ErrOperatorNotRegistered):
+		return ResponseOperatorNotRegistered
+	case errors.Is(err, ErrInvalidSignature):
+		return ResponseInvalidSignature
+	case errors.Is(err, ErrDuplicateResponse):
+		return ResponseDuplicate
+	case errors.Is(err, ErrQuorumReached):
+		return ResponseQuorumReached
+	case errors.Is(err, ErrTaskExpired):
+		return ResponseTaskExpired
+	default:
+		return ResponseInternalError
+	}
+}
+
+// TaskResponseReply returns the reply to an operator whose response was processed with err.
+// Responses of unregistered operators are replied as invalid signatures, as their signature
+// can't be verified against any registered key.
+func TaskResponseReply(err error) types.SignedTaskResponseReply {
+	reply := types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted}
+	if err == nil {
+		return reply
+	}
+	reply.Message = err.Error()
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		reply.Outcome = types.TaskResponseUnknownTask
+	case errors.Is(err, ErrOperatorNotRegistered), errors.Is(err, ErrInvalidSignature):
+		reply.Outcome = types.TaskResponseInvalidSignature
+	case errors.Is(err, ErrDuplicateResponse):
+		reply.Outcome = types.TaskResponseDuplicate
+	case errors.Is(err, ErrQuorumReached):
+		reply.Outcome = types.TaskResponseQuorumReached
+	case errors.Is(err, ErrTaskExpired):
+		reply.Outcome = types.TaskResponseTaskExpired
+	default:
+		reply.Outcome = types.TaskResponseInternalError
+	}
+	return reply
+}
+
+// operatorsStateReader is the part of the AVS registry service the validator needs.
+type operatorsStateReader interface {
+	GetOperatorsAvsStateAtBlock(ctx context.Context, quorumNumbers eigentypes.QuorumNums, blockNumber eigentypes.BlockNum) (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error)
+}
+
+// taskResponses holds the operators of a task and which of them responded.
+type taskResponses struct {
+	mutex     sync.Mutex
+	operators map[eigentypes.OperatorId]eigentypes.OperatorAvsState
+	responded map[eigentypes.OperatorId]bool
+}
+
+/*
+ResponseValidator
+Rejects task responses before they reach the BLS aggregation service, which verifies them one at a time
+and would count the stake of an operator once per response it sends. A response is rejected if:
+- Its operator is not registered in the quorum at the block the task was created in.
+- Its signature doesn't verify against the public key the operator registered.
+- A response of its operator was already counted for the task.
+The operators of a task are read from the registry on its first response and kept until the task is removed.
+*/
+type ResponseValidator struct {
+	registry     operatorsStateReader
+	quorumNumber eigentypes.QuorumNum
+	mutex        sync.Mutex
+	tasks        map[uint32]*taskResponses
+}
+
+func NewResponseValidator(registry operatorsStateReader, quorumNumber eigentypes.QuorumNum) *ResponseValidator {
+	return &ResponseValidator{
+		registry:     registry,
+		quorumNumber: quorumNumber,
+		tasks:        make(map[uint32]*taskResponses),
+	}
+}
+
+// Validate checks the response of the task and counts it, so any other response of the same operator
+// is rejected as a duplicate. Call Forget if the response is not aggregated after all.
+func (v *ResponseValidator) Validate(ctx context.Context, taskIndex uint32, taskCreatedBlock uint32, response *types.SignedTaskResponse) error {
+	v.mutex.Lock()
+	task, ok := v.tasks[taskIndex]
+	if !ok {
+		task = &taskResponses{responded: make(map[eigentypes.OperatorId]bool)}
+		v.tasks[taskIndex] = task
+	}
+	v.mutex.Unlock()
+
+	// The operators are loaded holding the lock of the task only, so other tasks are not held up
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	if task.operators == nil {
+		operators, err := v.registry.GetOperatorsAvsStateAtBlock(ctx, eigentypes.QuorumNums{v.quorumNumber}, taskCreatedBlock)
+		if err != nil {
+			return fmt.Errorf("could not get operators at block %d: %w", taskCreatedBlock, err)
+		}
+		task.operators = operators
+	}
+
+	operator, ok := task.operators[response.OperatorId]
+	if !ok {
+		return fmt.Errorf("%w: block %d", ErrOperatorNotRegistered, taskCreatedBlock)
+	}
+	if err := verifyResponseSignature(response, operator.OperatorInfo.Pubkeys); err != nil {
+		return err
+	}
+	if task.responded[response.OperatorId] {
+		return ErrDuplicateResponse
+	}
+	task.responded[response.OperatorId] = true
+	return nil
+}
+
+// Forget uncounts the response of the operator, so it can send it again.
+func (v *ResponseValidator) Forget(taskIndex uint32, operatorId eigentypes.OperatorId) {
+	v.mutex.Lock()
+	task, ok := v.tasks[taskIndex]
+	v.mutex.Unlock()
+	if !ok {
+		return
+	}
+	task.mutex.Lock()
+	delete(task.responded, operatorId)
+	task.mutex.Unlock()
+}
+
+// RemoveTask drops what is kept of the task.
+func (v *ResponseValidator) RemoveTask(taskIndex uint32) {
+	v.mutex.Lock()
+	delete(v.tasks, taskIndex)
+	v.mutex.Unlock()
+}
+
+// operatorLabel returns the operator ID a response is counted under in metrics. Only operators
+// found registered are counted under their own ID, so the number of series is bounded.
+func operatorLabel(operatorId eigentypes.OperatorId, registered bool) string {
+	if !registered {
+		return unregisteredOperatorLabel
+	}
+	return "0x" + hex.EncodeToString(operatorId[:])
+}
+
+// operatorRegistered reports whether Validate found the operator of the response registered,
+// given the error it returned.
+func operatorRegistered(validationErr error) bool {
+	return validationErr == nil || errors.Is(validationErr, ErrInvalidSignature) || errors.Is(validationErr, ErrDuplicateResponse)
+}
+
+// verifyResponseSignature checks the signature is of the batch identifier hash by the key of pubkeys.
+// The operator ID the pubkeys were found by is the hash of the G1 key, so only the G2 key is left to check.
+func verifyResponseSignature(response *types.SignedTaskResponse, pubkeys eigentypes.OperatorPubkeys) error {
+	signature := response.BlsSignature
+	if signature.G1Point == nil || signature.G1Affine == nil || !signature.IsOnCurve() || !signature.IsInSubGroup() {
+		return fmt.Errorf("%w: not a point of the G1 curve", ErrInvalidSignature)
+	}
+	if pubkeys.G2Pubkey == nil {
+		return fmt.Errorf("%w: operator has no G2 public key", ErrInvalidSignature)
+	}
+	ok, err := signature.Verify(pubkeys.G2Pubkey, response.BatchIdentifierHash)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+type testOperatorsState struct {
+	operators map[eigentypes.OperatorId]eigentypes.OperatorAvsState
+	calls     int
+}
+
+func (s *testOperatorsState) GetOperatorsAvsStateAtBlock(_ context.Context, _ eigentypes.QuorumNums, _ eigentypes.BlockNum) (map[eigentypes.OperatorId]eigentypes.OperatorAvsState, error) {
+	s.calls++
+	return s.operators, nil
+}
+
+func newTestKeyPair(t *testing.T, secret string) *bls.KeyPair {
+	keyPair, err := bls.NewKeyPairFromString(secret)
+	if err != nil {
+		t.Fatalf("could not create key pair: %v", err)
+	}
+	return keyPair
+}
+
+func newTestResponse(keyPair *bls.KeyPair, operatorId eigentypes.OperatorId) *types.SignedTaskResponse {
+	batchIdentifierHash := [32]byte{3}
+	return &types.SignedTaskResponse{
+		BatchMerkleRoot:     [32]byte{1},
+		SenderAddress:       [20]byte{2},
+		BatchIdentifierHash: batchIdentifierHash,
+		BlsSignature:        *keyPair.SignMessage(batchIdentifierHash),
+		OperatorId:          operatorId,
+	}
+}
+
+func TestResponseValidator(t *testing.T) {
+	operatorKeyPair := newTestKeyPair(t, "12345")
+	otherKeyPair := newTestKeyPair(t, "67890")
+	operatorId := eigentypes.OperatorIdFromKeyPair(operatorKeyPair)
+	state := &testOperatorsState{operators: map[eigentypes.OperatorId]eigentypes.OperatorAvsState{
+		operatorId: {
+			OperatorId: operatorId,
+			OperatorInfo: eigentypes.OperatorInfo{Pubkeys: eigentypes.OperatorPubkeys{
+				G1Pubkey: operatorKeyPair.GetPubKeyG1(),
+				G2Pubkey: operatorKeyPair.GetPubKeyG2(),
+			}},
+		},
+	}}
+	validator := NewResponseValidator(state, 0)
+	ctx := context.Background()
+
+	unregistered := newTestResponse(otherKeyPair, eigentypes.OperatorIdFromKeyPair(otherKeyPair))
+	if err := validator.Validate(ctx, 1, 100, unregistered); !errors.Is(err, ErrOperatorNotRegistered) {
+		t.Errorf("expected unregistered operator to be rejected, got %v", err)
+	}
+
+	forged := newTestResponse(otherKeyPair, operatorId)
+	if err := validator.Validate(ctx, 1, 100, forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected signature of another key to be rejected, got %v", err)
+	}
+
+	response := newTestResponse(operatorKeyPair, operatorId)
+	if err := validator.Validate(ctx, 1, 100, response); err != nil {
+		t.Fatalf("expected response to be accepted, got %v", err)
+	}
+	if err := validator.Validate(ctx, 1, 100, response); !errors.Is(err, ErrDuplicateResponse) {
+		t.Errorf("expected second response to be rejected as a duplicate, got %v", err)
+	}
+	if state.calls != 1 {
+		t.Errorf("expected operators to be read once per task, read %d times", state.calls)
+	}
+
+	// A response that was not aggregated can be sent again
+	validator.Forget(1, operatorId)
+	if err := validator.Validate(ctx, 1, 100, response); err != nil {
+		t.Errorf("expected forgotten response to be accepted, got %v", err)
+	}
+
+	// Responses are counted per task
+	if err := validator.Validate(ctx, 2, 101, response); err != nil {
+		t.Errorf("expected response to another task to be accepted, got %v", err)
+	}
+	validator.RemoveTask(2)
+	if err := validator.Validate(ctx, 2, 101, response); err != nil {
+		t.Errorf("expected response to a removed task to be accepted, got %v", err)
+	}
+}
+
+func TestResponseResult(t *testing.T) {
+	operatorId := eigentypes.OperatorId{1}
+	cases := []struct {
+		err    error
+		result string
+	}{
+		{nil, ResponseAccepted},
+		{ErrTaskNotFound, ResponseUnknownTask},
+		{fmt.Errorf("%w: block 100", ErrOperatorNotRegistered), ResponseOperatorNotRegistered},
+		{ErrInvalidSignature, ResponseInvalidSignature},
+		{ErrDuplicateResponse, ResponseDuplicate},
+		{ErrQuorumReached, ResponseQuorumReached},
+		{ErrTaskExpired, ResponseTaskExpired},
+		{errors.New("could not get operators"), ResponseInternalError},
+	}
+	for _, c := range cases {
+		if result := ResponseResult(c.err); result != c.result {
+			t.Errorf("expected result of %v to be %s, got %s", c.err, c.result, result)
+		}
+		// Every result is replied with the outcome of the same name, but for unregistered operators
+		outcome := TaskResponseReply(c.err).Outcome
+		if c.result == ResponseOperatorNotRegistered {
+			if outcome != types.TaskResponseInvalidSignature {
+				t.Errorf("expected unregistered operator to be replied an invalid signature, got %s", outcome)
+			}
+		} else if outcome.String() != c.result {
+			t.Errorf("expected reply to %v to be %s, got %s", c.err, c.result, outcome)
+		}
+	}
+	// Operator IDs sent in responses are only labels once the operator is found registered
+	for _, err := range []error{ErrTaskNotFound, ErrOperatorNotRegistered, ErrQuorumReached, errors.New("could not get operators")} {
+		if operatorRegistered(err) {
+			t.Errorf("expected operator of a response rejected with %v not to be taken as registered", err)
+		}
+	}
+	for _, err := range []error{nil, ErrInvalidSignature, ErrDuplicateResponse} {
+		if !operatorRegistered(err) {
+			t.Errorf("expected operator of a response validated with %v to be taken as registered", err)
+		}
+	}
+	if label := operatorLabel(operatorId, false); label != unregisteredOperatorLabel {
+		t.Errorf("expected operators not found registered to share a label, got %s", label)
+	}
+	if label := operatorLabel(operatorId, true); label[:4] != "0x01" || len(label) != 66 {
+		t.Errorf("expected label to be the hex operator id, got %s", label)
+	}
+}
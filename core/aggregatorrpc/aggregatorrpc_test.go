@@ -0,0 +1,262 @@
+package aggregatorrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+type testService struct {
+	received chan *types.SignedTaskResponse
+	delay    time.Duration
+	reply    types.SignedTaskResponseReply
+}
+
+func (s *testService) ProcessSignedTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) types.SignedTaskResponseReply {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return types.SignedTaskResponseReply{Message: ctx.Err().Error()}
+	}
+	s.received <- signedTaskResponse
+	return s.reply
+}
+
+func newSignedTaskResponse(t *testing.T) *types.SignedTaskResponse {
+	keyPair, err := bls.NewKeyPairFromString("12345")
+	if err != nil {
+		t.Fatalf("could not create key pair: %v", err)
+	}
+	batchIdentifierHash := [32]byte{3}
+	return &types.SignedTaskResponse{
+		BatchMerkleRoot:     [32]byte{1},
+		SenderAddress:       [20]byte{2},
+		BatchIdentifierHash: batchIdentifierHash,
+		BlsSignature:        *keyPair.SignMessage(batchIdentifierHash),
+		OperatorId:          eigentypes.OperatorIdFromKeyPair(keyPair),
+	}
+}
+
+// startServer serves service on a random local port and returns its address.
+func startServer(t *testing.T, service Service, config ServerConfig) string {
+	server, err := NewServer(service, config, logging.NewTextSLogger(io.Discard, nil))
+	if err != nil {
+		t.Fatalf("could not create server: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().String()
+}
+
+// testCertificates are the PEM files of a CA and of a server and a client certificate it signed.
+type testCertificates struct {
+	caCert, serverCert, serverKey, clientCert, clientKey string
+}
+
+func newTestCertificates(t *testing.T) testCertificates {
+	dir := t.TempDir()
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("could not create CA certificate: %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDer)
+
+	writePem := func(name string, blockType string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0o600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+		return path
+	}
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("could not create %s certificate: %v", name, err)
+		}
+		keyDer, _ := x509.MarshalECPrivateKey(key)
+		return writePem(name+".pem", "CERTIFICATE", der), writePem(name+".key", "EC PRIVATE KEY", keyDer)
+	}
+
+	certificates := testCertificates{caCert: writePem("ca.pem", "CERTIFICATE", caDer)}
+	certificates.serverCert, certificates.serverKey = issue("server", 2, x509.ExtKeyUsageServerAuth)
+	certificates.clientCert, certificates.clientKey = issue("client", 3, x509.ExtKeyUsageClientAuth)
+	return certificates
+}
+
+func TestSubmitTaskResponseOverMutualTLS(t *testing.T) {
+	certificates := newTestCertificates(t)
+	service := &testService{
+		received: make(chan *types.SignedTaskResponse, 1),
+		reply:    types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted},
+	}
+	address := startServer(t, service, ServerConfig{
+		TLSCertFile:  certificates.serverCert,
+		TLSKeyFile:   certificates.serverKey,
+		ClientCAFile: certificates.caCert,
+	})
+
+	// Operators without a client certificate are refused
+	_, err := NewClient(context.Background(), ClientConfig{Url: "https://" + address, CaCertFile: certificates.caCert})
+	if err == nil {
+		t.Fatalf("expected client without certificate to be refused")
+	}
+
+	client, err := NewClient(context.Background(), ClientConfig{
+		Url:            "https://" + address,
+		CaCertFile:     certificates.caCert,
+		ClientCertFile: certificates.clientCert,
+		ClientKeyFile:  certificates.clientKey,
+	})
+	if err != nil {
+		t.Fatalf("could not connect to server: %v", err)
+	}
+	defer client.Close()
+
+	signedTaskResponse := newSignedTaskResponse(t)
+	result, err := client.SubmitTaskResponse(context.Background(), signedTaskResponse)
+	if err != nil {
+		t.Fatalf("could not submit task response: %v", err)
+	}
+	if result.Status != TaskResponseProcessed || result.TaskResponseOutcome() != types.TaskResponseAccepted {
+		t.Errorf("expected response to be processed, got %+v", result)
+	}
+	received := <-service.received
+	if received.BatchIdentifierHash != signedTaskResponse.BatchIdentifierHash || received.OperatorId != signedTaskResponse.OperatorId ||
+		!received.BlsSignature.Equal(signedTaskResponse.BlsSignature.G1Affine) {
+		t.Errorf("server received a different task response: %+v", received)
+	}
+}
+
+func TestSubmitTaskResponseNotProcessed(t *testing.T) {
+	service := &testService{
+		received: make(chan *types.SignedTaskResponse, 1),
+		reply:    types.SignedTaskResponseReply{Outcome: types.TaskResponseUnknownTask, Message: "task not found"},
+	}
+	client, err := NewClient(context.Background(), ClientConfig{Url: "http://" + startServer(t, service, ServerConfig{})})
+	if err != nil {
+		t.Fatalf("could not connect to server: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.SubmitTaskResponse(context.Background(), newSignedTaskResponse(t))
+	if err != nil {
+		t.Fatalf("could not submit task response: %v", err)
+	}
+	if result.Status != TaskResponseNotProcessed || result.Message != "task not found" ||
+		result.TaskResponseOutcome() != types.TaskResponseUnknownTask {
+		t.Errorf("expected response not to be processed, got %+v", result)
+	}
+}
+
+func TestTaskResponseOutcome(t *testing.T) {
+	// Servers that don't send the outcome
+	if outcome := (SubmitTaskResponseResultV1{Status: TaskResponseProcessed}).TaskResponseOutcome(); outcome != types.TaskResponseAccepted {
+		t.Errorf("expected processed response to be accepted, got %s", outcome)
+	}
+	if outcome := (SubmitTaskResponseResultV1{Status: TaskResponseNotProcessed}).TaskResponseOutcome(); outcome != types.TaskResponseInternalError {
+		t.Errorf("expected response not processed to be an internal error, got %s", outcome)
+	}
+	// Outcomes added by newer servers
+	if outcome := (SubmitTaskResponseResultV1{Status: "pending", Outcome: "pending"}).TaskResponseOutcome(); outcome != types.TaskResponseInternalError {
+		t.Errorf("expected unknown outcome to be an internal error, got %s", outcome)
+	}
+	for outcome := types.TaskResponseInternalError; outcome <= types.TaskResponseInvalidSignature; outcome++ {
+		if parsed := types.ParseTaskResponseOutcome(outcome.String()); parsed != outcome {
+			t.Errorf("expected %s to be parsed back, got %s", outcome, parsed)
+		}
+	}
+}
+
+func TestSubmitTaskResponseDeadlines(t *testing.T) {
+	service := &testService{received: make(chan *types.SignedTaskResponse, 1), delay: 10 * time.Second}
+	address := startServer(t, service, ServerConfig{RequestTimeout: time.Second})
+	client, err := NewClient(context.Background(), ClientConfig{Url: "http://" + address, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("could not connect to server: %v", err)
+	}
+	defer client.Close()
+
+	// The server gives up on the request before the client does
+	start := time.Now()
+	if _, err := client.SubmitTaskResponse(context.Background(), newSignedTaskResponse(t)); err == nil {
+		t.Errorf("expected request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected server deadline to end the request, took %s", elapsed)
+	}
+
+	// And the client gives up once the deadline of its context passes
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.SubmitTaskResponse(ctx, newSignedTaskResponse(t)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected client deadline to be exceeded, got %v", err)
+	}
+}
+
+func TestSubmitTaskResponseRejectsMalformedSignature(t *testing.T) {
+	service := &testService{received: make(chan *types.SignedTaskResponse, 1)}
+	client, err := NewClient(context.Background(), ClientConfig{Url: "http://" + startServer(t, service, ServerConfig{})})
+	if err != nil {
+		t.Fatalf("could not connect to server: %v", err)
+	}
+	defer client.Close()
+
+	response := NewTaskResponseV1(newSignedTaskResponse(t))
+	response.Signature = response.Signature[:32]
+	var result SubmitTaskResponseResultV1
+	err = client.client.CallContext(context.Background(), &result, MethodSubmitTaskResponseV1, response)
+	if !IsInvalidParams(err) {
+		t.Errorf("expected malformed signature to be rejected as invalid params, got %v", err)
+	}
+
+	// Fields added by newer clients are ignored
+	var extended struct {
+		TaskResponseV1
+		Extra string `json:"extra"`
+	}
+	extended.TaskResponseV1 = NewTaskResponseV1(newSignedTaskResponse(t))
+	extended.Extra = "ignored"
+	if err := client.client.CallContext(context.Background(), &result, MethodSubmitTaskResponseV1, extended); err != nil {
+		t.Errorf("expected unknown fields to be ignored, got %v", err)
+	}
+}
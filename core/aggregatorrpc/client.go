@@ -0,0 +1,102 @@
+package aggregatorrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// DefaultClientTimeout is longer than DefaultRequestTimeout, so the server answers before the client gives up.
+const DefaultClientTimeout = DefaultRequestTimeout + 5*time.Second
+
+// ClientConfig is how the operator reaches the aggregator.
+type ClientConfig struct {
+	// Url of the aggregator, https to use TLS
+	Url string
+	// PEM file with the certificates the aggregator certificate is checked against, the system ones if empty
+	CaCertFile string
+	// Certificate and key presented to aggregators that require mutual TLS
+	ClientCertFile string
+	ClientKeyFile  string
+	// Deadline of each request, unless the context of the call has an earlier one
+	Timeout time.Duration
+}
+
+// Client calls the methods of the protocol on the aggregator.
+type Client struct {
+	client  *rpc.Client
+	timeout time.Duration
+}
+
+// NewClient connects to the aggregator and checks it serves the methods of the client.
+func NewClient(ctx context.Context, config ClientConfig) (*Client, error) {
+	aggregatorUrl, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregator url: %w", err)
+	}
+	if aggregatorUrl.Scheme != "https" && aggregatorUrl.Scheme != "http" {
+		return nil, fmt.Errorf("aggregator url %s must be http or https", config.Url)
+	}
+	tlsConfig, err := utils.NewClientTLSConfig(config.CaCertFile, config.ClientCertFile, config.ClientKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregator TLS config: %w", err)
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultClientTimeout
+	}
+
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+	}
+	rpcClient, err := rpc.DialOptions(ctx, aggregatorUrl.String(), rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to aggregator: %w", err)
+	}
+	client := &Client{client: rpcClient, timeout: timeout}
+
+	info, err := client.ProtocolInfo(ctx)
+	if err != nil {
+		rpcClient.Close()
+		return nil, fmt.Errorf("could not get protocol info of aggregator: %w", err)
+	}
+	if !info.Supports(MethodSubmitTaskResponseV1) {
+		rpcClient.Close()
+		return nil, fmt.Errorf("%w: %s is not served, protocol version %d", ErrUnsupportedProtocol, MethodSubmitTaskResponseV1, info.Version)
+	}
+	return client, nil
+}
+
+func (c *Client) ProtocolInfo(ctx context.Context) (*ProtocolInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var info ProtocolInfo
+	if err := c.client.CallContext(ctx, &info, MethodProtocolInfo); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SubmitTaskResponse sends the signed task response to the aggregator, which answers once it processed it.
+func (c *Client) SubmitTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) (*SubmitTaskResponseResultV1, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var result SubmitTaskResponseResultV1
+	if err := c.client.CallContext(ctx, &result, MethodSubmitTaskResponseV1, NewTaskResponseV1(signedTaskResponse)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) Close() {
+	c.client.Close()
+}
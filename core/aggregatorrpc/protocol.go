@@ -0,0 +1,133 @@
+/*
+Package aggregatorrpc is the JSON-RPC 2.0 protocol operators send their signed task responses to the aggregator with.
+
+Methods are named aligned_<method> and evolve without breaking older peers:
+  - Fields are only ever added to messages, as optional fields. Unknown fields are ignored by both sides.
+  - A change that older peers can't ignore gets a new method with the next version suffix,
+    served alongside the previous ones until no operator uses them.
+  - aligned_protocolInfo lists the methods a server supports, so clients can pick the newest they know.
+*/
+package aggregatorrpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// Namespace the methods of the protocol are registered under.
+const Namespace = "aligned"
+
+// ProtocolVersion is increased with every method added to the protocol.
+const ProtocolVersion = 1
+
+const (
+	MethodProtocolInfo         = Namespace + "_protocolInfo"
+	MethodSubmitTaskResponseV1 = Namespace + "_submitTaskResponseV1"
+)
+
+// Length of a serialized G1 point, which BLS signatures are
+const signatureLength = 64
+
+// JSON-RPC 2.0 error code of requests with malformed parameters
+const errorCodeInvalidParams = -32602
+
+var ErrUnsupportedProtocol = errors.New("aggregator doesn't support the protocol")
+
+// IsInvalidParams reports whether the server refused the request because its parameters are malformed,
+// so sending it again would fail the same way.
+func IsInvalidParams(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errorCodeInvalidParams
+}
+
+// ProtocolInfo is the result of aligned_protocolInfo.
+type ProtocolInfo struct {
+	Version int      `json:"version"`
+	Methods []string `json:"methods"`
+}
+
+// Supports reports whether the server serves method.
+func (p ProtocolInfo) Supports(method string) bool {
+	for _, supported := range p.Methods {
+		if supported == method {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskResponseV1 is the signed task response sent with aligned_submitTaskResponseV1.
+// Hashes and the signature are 0x prefixed hex, the signature being the serialized G1 point.
+type TaskResponseV1 struct {
+	BatchMerkleRoot     common.Hash    `json:"batchMerkleRoot"`
+	SenderAddress       common.Address `json:"senderAddress"`
+	BatchIdentifierHash common.Hash    `json:"batchIdentifierHash"`
+	Signature           hexutil.Bytes  `json:"signature"`
+	OperatorId          common.Hash    `json:"operatorId"`
+}
+
+func NewTaskResponseV1(signedTaskResponse *types.SignedTaskResponse) TaskResponseV1 {
+	return TaskResponseV1{
+		BatchMerkleRoot:     signedTaskResponse.BatchMerkleRoot,
+		SenderAddress:       signedTaskResponse.SenderAddress,
+		BatchIdentifierHash: signedTaskResponse.BatchIdentifierHash,
+		Signature:           signedTaskResponse.BlsSignature.Serialize(),
+		OperatorId:          common.Hash(signedTaskResponse.OperatorId),
+	}
+}
+
+// ToSignedTaskResponse checks the response is well formed and converts it. The signature is only
+// checked to be a point of the G1 curve, verifying it is up to the aggregator.
+func (r TaskResponseV1) ToSignedTaskResponse() (*types.SignedTaskResponse, error) {
+	if len(r.Signature) != signatureLength {
+		return nil, fmt.Errorf("signature must be %d bytes, got %d", signatureLength, len(r.Signature))
+	}
+	point := new(bls.G1Point).Deserialize(r.Signature)
+	if !point.IsOnCurve() || !point.IsInSubGroup() {
+		return nil, errors.New("signature is not on the G1 curve")
+	}
+	if r.OperatorId == (common.Hash{}) {
+		return nil, errors.New("operator id is missing")
+	}
+	return &types.SignedTaskResponse{
+		BatchMerkleRoot:     r.BatchMerkleRoot,
+		SenderAddress:       r.SenderAddress,
+		BatchIdentifierHash: r.BatchIdentifierHash,
+		BlsSignature:        bls.Signature{G1Point: point},
+		OperatorId:          eigentypes.OperatorId(r.OperatorId),
+	}, nil
+}
+
+// TaskResponseStatus is what the aggregator did with a task response.
+// Clients must handle statuses they don't know as TaskResponseNotProcessed.
+type TaskResponseStatus string
+
+const (
+	TaskResponseProcessed    TaskResponseStatus = "processed"
+	TaskResponseNotProcessed TaskResponseStatus = "not_processed"
+)
+
+// SubmitTaskResponseResultV1 is the result of aligned_submitTaskResponseV1.
+type SubmitTaskResponseResultV1 struct {
+	Status TaskResponseStatus `json:"status"`
+	// What the aggregator did with the response, one of the names of types.TaskResponseOutcome
+	Outcome string `json:"outcome,omitempty"`
+	// Why the response was not processed
+	Message string `json:"message,omitempty"`
+}
+
+// TaskResponseOutcome returns what the aggregator did with the response. Servers that don't
+// send the outcome only tell whether it was processed.
+func (r SubmitTaskResponseResultV1) TaskResponseOutcome() types.TaskResponseOutcome {
+	if r.Outcome == "" && r.Status == TaskResponseProcessed {
+		return types.TaskResponseAccepted
+	}
+	return types.ParseTaskResponseOutcome(r.Outcome)
+}
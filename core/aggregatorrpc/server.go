@@ -0,0 +1,166 @@
+package aggregatorrpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+// DefaultRequestTimeout is how long the server takes at most to answer a request. It has to give the
+// aggregator time to wait for tasks it didn't see yet, and clients should wait longer than this.
+const DefaultRequestTimeout = 20 * time.Second
+
+// Requests are a single task response, anything bigger is not from an operator
+const maxRequestSize = 1 << 14
+
+// Service processes the requests received by the server.
+type Service interface {
+	// ProcessSignedTaskResponse returns what was done with the response. It must return
+	// once ctx is done, which happens when the request deadline passes.
+	ProcessSignedTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) types.SignedTaskResponseReply
+}
+
+// ServerConfig is how the server is served. It is served over TLS if TLSCertFile and TLSKeyFile are set.
+type ServerConfig struct {
+	Address     string
+	TLSCertFile string
+	TLSKeyFile  string
+	// Only accept operators with a certificate signed by the CAs in this PEM file
+	ClientCAFile   string
+	RequestTimeout time.Duration
+}
+
+// Server serves the protocol over HTTP.
+type Server struct {
+	config     ServerConfig
+	rpcServer  *rpc.Server
+	httpServer *http.Server
+	logger     logging.Logger
+}
+
+func NewServer(service Service, config ServerConfig, logger logging.Logger) (*Server, error) {
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = DefaultRequestTimeout
+	}
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return nil, errors.New("both the TLS certificate and key are needed to serve over TLS")
+	}
+	if config.ClientCAFile != "" && config.TLSCertFile == "" {
+		return nil, errors.New("client certificates can only be required when serving over TLS")
+	}
+
+	rpcServer := rpc.NewServer()
+	rpcServer.SetHTTPBodyLimit(maxRequestSize)
+	// Operators send one response per request
+	rpcServer.SetBatchLimits(1, maxRequestSize)
+	if err := rpcServer.RegisterName(Namespace, &api{service: service}); err != nil {
+		return nil, err
+	}
+
+	httpServer := &http.Server{
+		Addr:              config.Address,
+		Handler:           rpcServer,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		// Requests are answered with a timeout error shortly before the write timeout
+		WriteTimeout: config.RequestTimeout,
+		IdleTimeout:  2 * time.Minute,
+	}
+	if config.TLSCertFile != "" {
+		tlsConfig, err := utils.NewServerTLSConfig(config.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		httpServer.TLSConfig = tlsConfig
+	}
+
+	return &Server{
+		config:     config,
+		rpcServer:  rpcServer,
+		httpServer: httpServer,
+		logger:     logger,
+	}, nil
+}
+
+// ListenAndServe serves the protocol on the configured address until the server is closed.
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve serves the protocol on listener until the server is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	var err error
+	if s.config.TLSCertFile == "" {
+		s.logger.Warn("Serving the operator RPC protocol over plain http", "address", listener.Addr().String())
+		err = s.httpServer.Serve(listener)
+	} else {
+		if s.config.ClientCAFile == "" {
+			s.logger.Warn("No client CA configured, operator certificates are not checked")
+		}
+		s.logger.Info("Serving the operator RPC protocol over TLS", "address", listener.Addr().String())
+		err = s.httpServer.ServeTLS(listener, s.config.TLSCertFile, s.config.TLSKeyFile)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) Close() error {
+	s.rpcServer.Stop()
+	return s.httpServer.Close()
+}
+
+// api holds the methods of the protocol. Methods are registered by name, so renaming
+// one is a breaking change, add a new version instead.
+type api struct {
+	service Service
+}
+
+func (a *api) ProtocolInfo() ProtocolInfo {
+	return ProtocolInfo{
+		Version: ProtocolVersion,
+		Methods: []string{MethodProtocolInfo, MethodSubmitTaskResponseV1},
+	}
+}
+
+func (a *api) SubmitTaskResponseV1(ctx context.Context, response TaskResponseV1) (*SubmitTaskResponseResultV1, error) {
+	signedTaskResponse, err := response.ToSignedTaskResponse()
+	if err != nil {
+		return nil, &invalidParamsError{err.Error()}
+	}
+	reply := a.service.ProcessSignedTaskResponse(ctx, signedTaskResponse)
+	result := &SubmitTaskResponseResultV1{
+		Status:  TaskResponseNotProcessed,
+		Outcome: reply.Outcome.String(),
+		Message: reply.Message,
+	}
+	if reply.Outcome == types.TaskResponseAccepted {
+		result.Status = TaskResponseProcessed
+	}
+	return result, nil
+}
+
+// invalidParamsError is the JSON-RPC 2.0 error of requests with malformed parameters.
+type invalidParamsError struct {
+	message string
+}
+
+func (e *invalidParamsError) Error() string {
+	return e.message
+}
+
+func (e *invalidParamsError) ErrorCode() int {
+	return errorCodeInvalidParams
+}
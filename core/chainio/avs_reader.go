@@ -8,7 +8,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
 	contractERC20Mock "github.com/yetanotherco/aligned_layer/contracts/bindings/ERC20Mock"
 	"github.com/yetanotherco/aligned_layer/core/config"
 
@@ -75,40 +74,52 @@ func (r *AvsReader) DisabledVerifiers() (*big.Int, error) {
 	return r.AvsContractBindings.ServiceManager.ContractAlignedLayerServiceManagerCaller.DisabledVerifiers(&bind.CallOpts{})
 }
 
-// Returns all the "NewBatchV3" logs that have not been responded starting from the given block number
-func (r *AvsReader) GetNotRespondedTasksFrom(fromBlock uint64) ([]servicemanager.ContractAlignedLayerServiceManagerNewBatchV3, error) {
-	logs, err := r.AvsContractBindings.ServiceManager.FilterNewBatchV3(&bind.FilterOpts{Start: fromBlock, End: nil, Context: context.Background()}, nil)
-
+// Returns all the batches, of any NewBatch event version, that have not been responded starting from the given block number
+func (r *AvsReader) GetNotRespondedTasksFrom(fromBlock uint64) ([]*NewBatch, error) {
+	newBatches, err := filterNewBatches(context.Background(), &r.AvsContractBindings.ethClient, r.AvsContractBindings.ServiceManager, r.AlignedLayerServiceManagerAddr, fromBlock, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var tasks []servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
-
-	for logs.Next() {
-		task, err := r.AvsContractBindings.ServiceManager.ParseNewBatchV3(logs.Event.Raw)
-		if err != nil {
-			return nil, err
-		}
+	var tasks []*NewBatch
 
+	for _, task := range newBatches {
 		// now check if its finalized or not before appending
-		batchIdentifier := append(task.BatchMerkleRoot[:], task.SenderAddress[:]...)
-		batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-		state, err := r.AvsContractBindings.ServiceManager.ContractAlignedLayerServiceManagerCaller.BatchesState(nil, batchIdentifierHash)
-
+		responded, err := r.IsBatchResponded(task.BatchIdentifierHash())
 		if err != nil {
 			return nil, err
 		}
 
 		// append the task if not responded yet
-		if !state.Responded {
-			tasks = append(tasks, *task)
+		if !responded {
+			tasks = append(tasks, task)
 		}
 	}
 
 	return tasks, nil
 }
 
+// BlockNumber returns the latest block number, asking the fallback client if the main one fails.
+func (r *AvsReader) BlockNumber(ctx context.Context) (uint64, error) {
+	latestBlock, err := r.AvsContractBindings.ethClient.BlockNumber(ctx)
+	if err != nil {
+		latestBlock, err = r.AvsContractBindings.ethClientFallback.BlockNumber(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get latest block number: %w", err)
+		}
+	}
+	return latestBlock, nil
+}
+
+// IsBatchResponded returns whether the task of the batch was already responded on chain.
+func (r *AvsReader) IsBatchResponded(batchIdentifierHash [32]byte) (bool, error) {
+	state, err := r.AvsContractBindings.ServiceManager.ContractAlignedLayerServiceManagerCaller.BatchesState(nil, batchIdentifierHash)
+	if err != nil {
+		return false, err
+	}
+	return state.Responded, nil
+}
+
 // This function is a helper to get a task hash of aproximately nBlocksOld blocks ago
 func (r *AvsReader) GetOldTaskHash(nBlocksOld uint64, interval uint64) (*[32]byte, error) {
 	latestBlock, err := r.AvsContractBindings.ethClient.BlockNumber(context.Background())
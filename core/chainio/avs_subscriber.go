@@ -6,16 +6,13 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 
 	"github.com/ethereum/go-ethereum/core/types"
-	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
 	retry "github.com/yetanotherco/aligned_layer/core"
 	"github.com/yetanotherco/aligned_layer/core/config"
 
 	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -41,6 +38,40 @@ type AvsSubscriber struct {
 	AvsContractBindings            *AvsServiceBindings
 	AlignedLayerServiceManagerAddr ethcommon.Address
 	logger                         sdklogging.Logger
+	// subscription is shared by the copies of the subscriber
+	subscription *subscriptionTracker
+}
+
+// SubscriptionStatus is the state of the NewBatch subscriptions of the subscriber.
+type SubscriptionStatus struct {
+	PrimarySubscribed  bool
+	FallbackSubscribed bool
+	// LastEventAt is when the last NewBatch log was received, zero if none was
+	LastEventAt    time.Time
+	LastEventBlock uint64
+	// LastHead is the latest block number seen, either polled or from a NewBatch log
+	LastHead   uint64
+	LastHeadAt time.Time
+}
+
+type subscriptionTracker struct {
+	mutex  sync.Mutex
+	status SubscriptionStatus
+}
+
+func (t *subscriptionTracker) update(update func(status *SubscriptionStatus)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	update(&t.status)
+}
+
+func (t *subscriptionTracker) recordHead(blockNumber uint64) {
+	t.update(func(status *SubscriptionStatus) {
+		if blockNumber >= status.LastHead {
+			status.LastHead = blockNumber
+			status.LastHeadAt = time.Now()
+		}
+	})
 }
 
 func NewAvsSubscriberFromConfig(baseConfig *config.BaseConfig) (*AvsSubscriber, error) {
@@ -58,120 +89,78 @@ func NewAvsSubscriberFromConfig(baseConfig *config.BaseConfig) (*AvsSubscriber,
 		AvsContractBindings:            avsContractBindings,
 		AlignedLayerServiceManagerAddr: baseConfig.AlignedLayerDeploymentConfig.AlignedLayerServiceManagerAddr,
 		logger:                         baseConfig.Logger,
+		subscription:                   &subscriptionTracker{},
 	}, nil
 }
 
-func (s *AvsSubscriber) SubscribeToNewTasksV2(newTaskCreatedChan chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2) (chan error, error) {
-	// Create a new channel to receive new tasks
-	internalChannel := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2)
+// SubscriptionStatus returns the state of the NewBatch subscriptions.
+func (s *AvsSubscriber) SubscriptionStatus() SubscriptionStatus {
+	s.subscription.mutex.Lock()
+	defer s.subscription.mutex.Unlock()
+	return s.subscription.status
+}
+
+// SubscribeToNewBatches subscribes to every supported version of the NewBatch event, through
+// both the main and the fallback connections, and sends each new batch to newBatchChan once.
+// The latest batch not responded yet is also polled periodically, in case the subscription misses it.
+func (s *AvsSubscriber) SubscribeToNewBatches(newBatchChan chan *NewBatch) (chan error, error) {
+	// Create a new channel to receive new batch logs
+	internalChannel := make(chan types.Log)
 
-	// Subscribe to new tasks
-	sub, err := SubscribeToNewTasksV2Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManager, internalChannel, nil, retry.NetworkRetryParams())
+	// Subscribe to new batches
+	sub, err := SubscribeToNewBatchesRetryable(context.Background(), &s.AvsContractBindings.ethClient, s.AlignedLayerServiceManagerAddr, internalChannel, retry.NetworkRetryParams())
 	if err != nil {
-		s.logger.Error("Primary failed to subscribe to new AlignedLayer V2 tasks after %d retries", retry.NetworkNumRetries, "err", err)
+		s.logger.Error("Primary failed to subscribe to new AlignedLayer batches after %d retries", retry.NetworkNumRetries, "err", err)
 		return nil, err
 	}
 
-	subFallback, err := SubscribeToNewTasksV2Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManagerFallback, internalChannel, nil, retry.NetworkRetryParams())
+	subFallback, err := SubscribeToNewBatchesRetryable(context.Background(), &s.AvsContractBindings.ethClientFallback, s.AlignedLayerServiceManagerAddr, internalChannel, retry.NetworkRetryParams())
 	if err != nil {
-		s.logger.Error("Fallback failed to subscribe to new AlignedLayer V2 tasks after %d retries", retry.NetworkNumRetries, "err", err)
+		s.logger.Error("Fallback failed to subscribe to new AlignedLayer batches after %d retries", retry.NetworkNumRetries, "err", err)
 		return nil, err
 	}
-	s.logger.Info("Subscribed to new AlignedLayer V2 tasks")
+	s.logger.Info("Subscribed to new AlignedLayer batches")
+	s.subscription.update(func(status *SubscriptionStatus) {
+		status.PrimarySubscribed = true
+		status.FallbackSubscribed = true
+	})
 
 	// create a new channel to foward errors
 	errorChannel := make(chan error)
 
 	pollLatestBatchTicker := time.NewTicker(PollLatestBatchInterval)
 
-	// Forward the new tasks to the provided channel
+	// Forward the new batches to the provided channel
 	go func() {
 		defer pollLatestBatchTicker.Stop()
 		newBatchMutex := &sync.Mutex{}
 		batchesSet := make(map[[32]byte]struct{})
 		for {
 			select {
-			case newBatch := <-internalChannel:
-				s.processNewBatchV2(newBatch, batchesSet, newBatchMutex, newTaskCreatedChan)
-			case <-pollLatestBatchTicker.C:
-				latestBatch, err := s.getLatestNotRespondedTaskFromEthereumV2()
-				if err != nil {
-					s.logger.Debug("Failed to get latest task from blockchain", "err", err)
+			case newBatchLog := <-internalChannel:
+				if newBatchLog.Removed {
+					// The log was removed by a chain reorganization
 					continue
 				}
-				if latestBatch != nil {
-					s.processNewBatchV2(latestBatch, batchesSet, newBatchMutex, newTaskCreatedChan)
-				}
-			}
-		}
-
-	}()
-
-	// Handle errors and resubscribe
-	go func() {
-		for {
-			select {
-			case err := <-sub.Err():
-				s.logger.Warn("Error in new task subscription", "err", err)
-				sub.Unsubscribe()
-				sub, err = SubscribeToNewTasksV2Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManager, internalChannel, nil, retry.NetworkRetryParams())
+				s.subscription.update(func(status *SubscriptionStatus) {
+					status.LastEventAt = time.Now()
+					status.LastEventBlock = newBatchLog.BlockNumber
+				})
+				s.subscription.recordHead(newBatchLog.BlockNumber)
+				newBatch, err := decodeNewBatch(s.AvsContractBindings.ServiceManager, newBatchLog)
 				if err != nil {
-					errorChannel <- err
-				}
-			case err := <-subFallback.Err():
-				s.logger.Warn("Error in fallback new task subscription", "err", err)
-				subFallback.Unsubscribe()
-				subFallback, err = SubscribeToNewTasksV2Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManagerFallback, internalChannel, nil, retry.NetworkRetryParams())
-				if err != nil {
-					errorChannel <- err
+					s.logger.Warn("Failed to decode new batch log", "err", err)
+					continue
 				}
-			}
-		}
-	}()
-
-	return errorChannel, nil
-}
-
-func (s *AvsSubscriber) SubscribeToNewTasksV3(newTaskCreatedChan chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3) (chan error, error) {
-	// Create a new channel to receive new tasks
-	internalChannel := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3)
-
-	// Subscribe to new tasks
-	sub, err := SubscribeToNewTasksV3Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManager, internalChannel, nil, retry.NetworkRetryParams())
-	if err != nil {
-		s.logger.Error("Primary failed to subscribe to new AlignedLayer V3 tasks after %d retries", MaxRetries, "err", err)
-		return nil, err
-	}
-
-	subFallback, err := SubscribeToNewTasksV3Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManagerFallback, internalChannel, nil, retry.NetworkRetryParams())
-	if err != nil {
-		s.logger.Error("Fallback failed to subscribe to new AlignedLayer V3 tasks after %d retries", MaxRetries, "err", err)
-		return nil, err
-	}
-	s.logger.Info("Subscribed to new AlignedLayer V3 tasks")
-
-	// create a new channel to foward errors
-	errorChannel := make(chan error)
-
-	pollLatestBatchTicker := time.NewTicker(PollLatestBatchInterval)
-
-	// Forward the new tasks to the provided channel
-	go func() {
-		defer pollLatestBatchTicker.Stop()
-		newBatchMutex := &sync.Mutex{}
-		batchesSet := make(map[[32]byte]struct{})
-		for {
-			select {
-			case newBatch := <-internalChannel:
-				s.processNewBatchV3(newBatch, batchesSet, newBatchMutex, newTaskCreatedChan)
+				s.processNewBatch(newBatch, batchesSet, newBatchMutex, newBatchChan)
 			case <-pollLatestBatchTicker.C:
-				latestBatch, err := s.getLatestNotRespondedTaskFromEthereumV3()
+				latestBatch, err := s.getLatestNotRespondedBatchFromEthereum()
 				if err != nil {
 					s.logger.Debug("Failed to get latest task from blockchain", "err", err)
 					continue
 				}
 				if latestBatch != nil {
-					s.processNewBatchV3(latestBatch, batchesSet, newBatchMutex, newTaskCreatedChan)
+					s.processNewBatch(latestBatch, batchesSet, newBatchMutex, newBatchChan)
 				}
 			}
 		}
@@ -184,18 +173,24 @@ func (s *AvsSubscriber) SubscribeToNewTasksV3(newTaskCreatedChan chan *servicema
 			select {
 			case err := <-sub.Err():
 				s.logger.Warn("Error in new task subscription", "err", err)
+				s.subscription.update(func(status *SubscriptionStatus) { status.PrimarySubscribed = false })
 				sub.Unsubscribe()
-				sub, err = SubscribeToNewTasksV3Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManager, internalChannel, nil, retry.NetworkRetryParams())
+				sub, err = SubscribeToNewBatchesRetryable(context.Background(), &s.AvsContractBindings.ethClient, s.AlignedLayerServiceManagerAddr, internalChannel, retry.NetworkRetryParams())
 				if err != nil {
 					errorChannel <- err
+					continue
 				}
+				s.subscription.update(func(status *SubscriptionStatus) { status.PrimarySubscribed = true })
 			case err := <-subFallback.Err():
 				s.logger.Warn("Error in fallback new task subscription", "err", err)
+				s.subscription.update(func(status *SubscriptionStatus) { status.FallbackSubscribed = false })
 				subFallback.Unsubscribe()
-				subFallback, err = SubscribeToNewTasksV3Retryable(&bind.WatchOpts{}, s.AvsContractBindings.ServiceManagerFallback, internalChannel, nil, retry.NetworkRetryParams())
+				subFallback, err = SubscribeToNewBatchesRetryable(context.Background(), &s.AvsContractBindings.ethClientFallback, s.AlignedLayerServiceManagerAddr, internalChannel, retry.NetworkRetryParams())
 				if err != nil {
 					errorChannel <- err
+					continue
 				}
+				s.subscription.update(func(status *SubscriptionStatus) { status.FallbackSubscribed = true })
 			}
 		}
 	}()
@@ -203,21 +198,21 @@ func (s *AvsSubscriber) SubscribeToNewTasksV3(newTaskCreatedChan chan *servicema
 	return errorChannel, nil
 }
 
-func (s *AvsSubscriber) processNewBatchV2(batch *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2, batchesSet map[[32]byte]struct{}, newBatchMutex *sync.Mutex, newTaskCreatedChan chan<- *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2) {
+func (s *AvsSubscriber) processNewBatch(batch *NewBatch, batchesSet map[[32]byte]struct{}, newBatchMutex *sync.Mutex, newBatchChan chan<- *NewBatch) {
 	newBatchMutex.Lock()
 	defer newBatchMutex.Unlock()
 
-	batchIdentifier := append(batch.BatchMerkleRoot[:], batch.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+	batchIdentifierHash := batch.BatchIdentifierHash()
 
 	if _, ok := batchesSet[batchIdentifierHash]; !ok {
 		s.logger.Info("Received new task",
 			"batchMerkleRoot", hex.EncodeToString(batch.BatchMerkleRoot[:]),
 			"senderAddress", hex.EncodeToString(batch.SenderAddress[:]),
-			"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
+			"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]),
+			"eventVersion", batch.EventVersion)
 
 		batchesSet[batchIdentifierHash] = struct{}{}
-		newTaskCreatedChan <- batch
+		newBatchChan <- batch
 
 		// Remove the batch from the set after RemoveBatchFromSetInterval time
 		go func() {
@@ -229,88 +224,13 @@ func (s *AvsSubscriber) processNewBatchV2(batch *servicemanager.ContractAlignedL
 	}
 }
 
-func (s *AvsSubscriber) processNewBatchV3(batch *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3, batchesSet map[[32]byte]struct{}, newBatchMutex *sync.Mutex, newTaskCreatedChan chan<- *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3) {
-	newBatchMutex.Lock()
-	defer newBatchMutex.Unlock()
-
-	batchIdentifier := append(batch.BatchMerkleRoot[:], batch.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-
-	if _, ok := batchesSet[batchIdentifierHash]; !ok {
-		s.logger.Info("Received new task",
-			"batchMerkleRoot", hex.EncodeToString(batch.BatchMerkleRoot[:]),
-			"senderAddress", hex.EncodeToString(batch.SenderAddress[:]),
-			"batchIdentifierHash", hex.EncodeToString(batchIdentifierHash[:]))
-
-		batchesSet[batchIdentifierHash] = struct{}{}
-		newTaskCreatedChan <- batch
-
-		// Remove the batch from the set after RemoveBatchFromSetInterval time
-		go func() {
-			time.Sleep(RemoveBatchFromSetInterval)
-			newBatchMutex.Lock()
-			delete(batchesSet, batchIdentifierHash)
-			newBatchMutex.Unlock()
-		}()
-	}
-}
-
-// getLatestNotRespondedTaskFromEthereum queries the blockchain for the latest not responded task using the FilterNewBatch method.
-func (s *AvsSubscriber) getLatestNotRespondedTaskFromEthereumV2() (*servicemanager.ContractAlignedLayerServiceManagerNewBatchV2, error) {
-
-	latestBlock, err := s.BlockNumberRetryable(context.Background(), retry.NetworkRetryParams())
-	if err != nil {
-		return nil, err
-	}
-
-	var fromBlock uint64
-
-	if latestBlock < BlockInterval {
-		fromBlock = 0
-	} else {
-		fromBlock = latestBlock - BlockInterval
-	}
-
-	logs, err := s.FilterBatchV2Retryable(&bind.FilterOpts{Start: fromBlock, End: nil, Context: context.Background()}, nil, retry.NetworkRetryParams())
-	if err != nil {
-		return nil, err
-	}
-
-	var lastLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2
-
-	// Iterate over the logs until the end
-	for logs.Next() {
-		lastLog = logs.Event
-	}
-
-	if err := logs.Error(); err != nil {
-		return nil, err
-	}
-
-	if lastLog == nil {
-		return nil, nil
-	}
-
-	batchIdentifier := append(lastLog.BatchMerkleRoot[:], lastLog.SenderAddress[:]...)
-	batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-	state, err := s.BatchesStateRetryable(nil, batchIdentifierHash, retry.NetworkRetryParams())
-	if err != nil {
-		return nil, err
-	}
-
-	if state.Responded {
-		return nil, nil
-	}
-
-	return lastLog, nil
-}
-
-// getLatestNotRespondedTaskFromEthereum queries the blockchain for the latest not responded task using the FilterNewBatch method.
-func (s *AvsSubscriber) getLatestNotRespondedTaskFromEthereumV3() (*servicemanager.ContractAlignedLayerServiceManagerNewBatchV3, error) {
+// getLatestNotRespondedBatchFromEthereum queries the blockchain for the latest not responded batch, of any NewBatch event version.
+func (s *AvsSubscriber) getLatestNotRespondedBatchFromEthereum() (*NewBatch, error) {
 	latestBlock, err := s.BlockNumberRetryable(context.Background(), retry.NetworkRetryParams())
 	if err != nil {
 		return nil, err
 	}
+	s.subscription.recordHead(latestBlock)
 
 	var fromBlock uint64
 
@@ -320,29 +240,17 @@ func (s *AvsSubscriber) getLatestNotRespondedTaskFromEthereumV3() (*servicemanag
 		fromBlock = latestBlock - BlockInterval
 	}
 
-	logs, err := s.FilterBatchV3Retryable(&bind.FilterOpts{Start: fromBlock, End: nil, Context: context.Background()}, nil, retry.NetworkRetryParams())
+	newBatches, err := s.FilterNewBatchesRetryable(context.Background(), fromBlock, retry.NetworkRetryParams())
 	if err != nil {
 		return nil, err
 	}
 
-	var lastLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
-
-	// Iterate over the logs until the end
-	for logs.Next() {
-		lastLog = logs.Event
-	}
-
-	if err := logs.Error(); err != nil {
-		return nil, err
-	}
-
-	if lastLog == nil {
+	if len(newBatches) == 0 {
 		return nil, nil
 	}
+	lastBatch := newBatches[len(newBatches)-1]
 
-	batchIdentifier := append(lastLog.BatchMerkleRoot[:], lastLog.SenderAddress[:]...)
-	batchIdentifierHash := *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-	state, err := s.BatchesStateRetryable(nil, batchIdentifierHash, retry.NetworkRetryParams())
+	state, err := s.BatchesStateRetryable(nil, lastBatch.BatchIdentifierHash(), retry.NetworkRetryParams())
 	if err != nil {
 		return nil, err
 	}
@@ -351,7 +259,7 @@ func (s *AvsSubscriber) getLatestNotRespondedTaskFromEthereumV3() (*servicemanag
 		return nil, nil
 	}
 
-	return lastLog, nil
+	return lastBatch, nil
 }
 
 func (s *AvsSubscriber) WaitForOneBlock(startBlock uint64) error {
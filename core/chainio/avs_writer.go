@@ -9,7 +9,9 @@ import (
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients"
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/signer"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -24,6 +26,7 @@ import (
 
 type AvsWriter struct {
 	*avsregistry.ChainWriter
+	elChainWriter       *elcontracts.ChainWriter
 	AvsContractBindings *AvsServiceBindings
 	logger              logging.Logger
 	Signer              signer.Signer
@@ -33,6 +36,14 @@ type AvsWriter struct {
 }
 
 func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.EcdsaConfig, metrics *metrics.Metrics) (*AvsWriter, error) {
+	return NewAvsWriterFromConfigWithTxManager(baseConfig, ecdsaConfig, metrics, nil)
+}
+
+// NewAvsWriterFromConfigWithTxManager creates an AvsWriter whose registry and EigenLayer ChainWriters
+// send their transactions through txMgr, or through a TxManager of the ECDSA account if txMgr is nil.
+// The aggregated responses are always signed with the signer of the ECDSA account, which is either
+// its private key or an external signer.
+func NewAvsWriterFromConfigWithTxManager(baseConfig *config.BaseConfig, ecdsaConfig *config.EcdsaConfig, metrics *metrics.Metrics, txMgr txmgr.TxManager) (*AvsWriter, error) {
 
 	buildAllConfig := clients.BuildAllConfig{
 		EthHttpUrl:                 baseConfig.EthRpcUrl,
@@ -43,11 +54,33 @@ func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.E
 		PromMetricsIpPortAddress:   baseConfig.EigenMetricsIpPortAddress,
 	}
 
-	clients, err := clients.BuildAll(buildAllConfig, ecdsaConfig.PrivateKey, baseConfig.Logger)
-
-	if err != nil {
-		baseConfig.Logger.Error("Cannot build signer config", "err", err)
-		return nil, err
+	var readClients *clients.ReadClients
+	var chainWriter *avsregistry.ChainWriter
+	var elChainWriter *elcontracts.ChainWriter
+	if ecdsaConfig.ExternalSigner == nil {
+		allClients, err := clients.BuildAll(buildAllConfig, ecdsaConfig.PrivateKey, baseConfig.Logger)
+		if err != nil {
+			baseConfig.Logger.Error("Cannot build signer config", "err", err)
+			return nil, err
+		}
+		readClients = &allClients.ReadClients
+		chainWriter = allClients.AvsRegistryChainWriter
+		elChainWriter = allClients.ElChainWriter
+	} else {
+		// Without the private key the writers are built on a TxManager of the external signer
+		var err error
+		readClients, err = clients.BuildReadClients(buildAllConfig, baseConfig.Logger)
+		if err != nil {
+			baseConfig.Logger.Error("Cannot build read clients", "err", err)
+			return nil, err
+		}
+		if txMgr == nil {
+			txMgr, err = ecdsaConfig.ExternalSigner.NewTxManager(&baseConfig.EthRpcClient, baseConfig.Logger)
+			if err != nil {
+				baseConfig.Logger.Error("Cannot create external signer TxManager", "err", err)
+				return nil, err
+			}
+		}
 	}
 
 	avsServiceBindings, err := NewAvsServiceBindings(baseConfig.AlignedLayerDeploymentConfig.AlignedLayerServiceManagerAddr, baseConfig.AlignedLayerDeploymentConfig.AlignedLayerOperatorStateRetrieverAddr, baseConfig.EthRpcClient, baseConfig.EthRpcClientFallback, baseConfig.Logger)
@@ -57,25 +90,43 @@ func NewAvsWriterFromConfig(baseConfig *config.BaseConfig, ecdsaConfig *config.E
 		return nil, err
 	}
 
-	privateKeySigner, err := signer.NewPrivateKeySigner(ecdsaConfig.PrivateKey, baseConfig.ChainId)
-	if err != nil {
-		baseConfig.Logger.Error("Cannot create signer", "err", err)
-		return nil, err
+	if txMgr != nil {
+		chainWriter, err = avsregistry.NewWriterFromConfig(avsregistry.Config{
+			RegistryCoordinatorAddress:    baseConfig.AlignedLayerDeploymentConfig.AlignedLayerRegistryCoordinatorAddr,
+			OperatorStateRetrieverAddress: baseConfig.AlignedLayerDeploymentConfig.AlignedLayerOperatorStateRetrieverAddr,
+		}, readClients.EthHttpClient, txMgr, baseConfig.Logger)
+		if err != nil {
+			baseConfig.Logger.Error("Cannot create AVS registry writer", "err", err)
+			return nil, err
+		}
+		elChainWriter, err = elcontracts.NewWriterFromConfig(elcontracts.Config{
+			DelegationManagerAddress: readClients.AvsRegistryContractBindings.DelegationManagerAddr,
+			AvsDirectoryAddress:      readClients.AvsRegistryContractBindings.AvsDirectoryAddr,
+		}, readClients.EthHttpClient, baseConfig.Logger, readClients.Metrics, txMgr)
+		if err != nil {
+			baseConfig.Logger.Error("Cannot create EigenLayer writer", "err", err)
+			return nil, err
+		}
 	}
 
-	chainWriter := clients.AvsRegistryChainWriter
-
 	return &AvsWriter{
 		ChainWriter:         chainWriter,
+		elChainWriter:       elChainWriter,
 		AvsContractBindings: avsServiceBindings,
 		logger:              baseConfig.Logger,
-		Signer:              privateKeySigner,
+		Signer:              ecdsaConfig.Signer,
 		Client:              baseConfig.EthRpcClient,
 		ClientFallback:      baseConfig.EthRpcClientFallback,
 		metrics:             metrics,
 	}, nil
 }
 
+// UpdateMetadataURI updates the metadata URI of the operator. The AVS registry keeps no metadata
+// of its own, so it is updated in the EigenLayer DelegationManager.
+func (w *AvsWriter) UpdateMetadataURI(ctx context.Context, uri string, waitForReceipt bool) (*types.Receipt, error) {
+	return w.elChainWriter.UpdateMetadataURI(ctx, uri, waitForReceipt)
+}
+
 // SendAggregatedResponse continuously sends a RespondToTask transaction until it is included in the blockchain.
 // This function:
 //  1. Simulates the transaction to calculate the nonce and initial gas price without broadcasting it.
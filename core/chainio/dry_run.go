@@ -0,0 +1,53 @@
+package chainio
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DryRunTxManager is a TxManager that records the transactions it is given instead of signing and
+// sending them. The contract bindings still fill in the nonce and estimate the gas of each transaction
+// against the chain, so a transaction that would revert fails to be built.
+type DryRunTxManager struct {
+	sender       common.Address
+	mutex        sync.Mutex
+	transactions []*types.Transaction
+}
+
+var _ txmgr.TxManager = (*DryRunTxManager)(nil)
+
+func NewDryRunTxManager(sender common.Address) *DryRunTxManager {
+	return &DryRunTxManager{sender: sender}
+}
+
+func (m *DryRunTxManager) GetNoSendTxOpts() (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From:   m.sender,
+		NoSend: true,
+		Signer: txmgr.NoopSigner,
+	}, nil
+}
+
+// Send records tx. The returned receipt only holds the hash of the unsigned transaction, as it is never mined.
+func (m *DryRunTxManager) Send(ctx context.Context, tx *types.Transaction, waitForReceipt bool) (*types.Receipt, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.transactions = append(m.transactions, tx)
+	return &types.Receipt{TxHash: tx.Hash()}, nil
+}
+
+func (m *DryRunTxManager) Sender() common.Address {
+	return m.sender
+}
+
+// Transactions returns the transactions recorded so far, in the order they were sent.
+func (m *DryRunTxManager) Transactions() []*types.Transaction {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]*types.Transaction(nil), m.transactions...)
+}
@@ -0,0 +1,176 @@
+package chainio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/metrics"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	testRegistryCoordinatorAddr = ethcommon.HexToAddress("0x851356ae760d987E095750cCeb3bC6014560891C")
+	testDelegationManagerAddr   = ethcommon.HexToAddress("0xCf7Ed3AccA5a467e9e704C703E8D87F634fB0Fc9")
+	testOperatorAddr            = ethcommon.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+)
+
+// fakeChain answers what the contract bindings ask for to build a transaction. Transactions
+// can't be sent to it, so a dry run that reaches the chain fails.
+type fakeChain struct {
+	nonce       uint64
+	gas         uint64
+	estimateErr error
+	estimated   []ethereum.CallMsg
+}
+
+func (c *fakeChain) CodeAt(ctx context.Context, contract ethcommon.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x60}, nil
+}
+
+func (c *fakeChain) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, errors.New("calls are not supported by the fake chain")
+}
+
+func (c *fakeChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)}, nil
+}
+
+func (c *fakeChain) PendingCodeAt(ctx context.Context, account ethcommon.Address) ([]byte, error) {
+	return []byte{0x60}, nil
+}
+
+func (c *fakeChain) PendingNonceAt(ctx context.Context, account ethcommon.Address) (uint64, error) {
+	return c.nonce, nil
+}
+
+func (c *fakeChain) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(12), nil
+}
+
+func (c *fakeChain) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(2), nil
+}
+
+func (c *fakeChain) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	c.estimated = append(c.estimated, call)
+	return c.gas, c.estimateErr
+}
+
+func (c *fakeChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return errors.New("the fake chain does not accept transactions")
+}
+
+func (c *fakeChain) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, nil
+}
+
+func (c *fakeChain) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("subscriptions are not supported by the fake chain")
+}
+
+func (c *fakeChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return 100, nil
+}
+
+func (c *fakeChain) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return nil, errors.New("blocks are not supported by the fake chain")
+}
+
+func newDryRunAvsWriter(t *testing.T, chain *fakeChain) (*AvsWriter, *DryRunTxManager) {
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	txMgr := NewDryRunTxManager(testOperatorAddr)
+
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinator(testRegistryCoordinatorAddr, chain)
+	if err != nil {
+		t.Fatalf("could not bind registry coordinator: %v", err)
+	}
+	delegationManager, err := delegationmanager.NewContractDelegationManager(testDelegationManagerAddr, chain)
+	if err != nil {
+		t.Fatalf("could not bind delegation manager: %v", err)
+	}
+	return &AvsWriter{
+		ChainWriter:   avsregistry.NewChainWriter(testServiceManagerAddr, registryCoordinator, nil, nil, nil, nil, logger, chain, txMgr),
+		elChainWriter: elcontracts.NewChainWriter(nil, delegationManager, nil, nil, nil, ethcommon.Address{}, nil, chain, logger, metrics.NewNoopMetrics(), txMgr),
+		logger:        logger,
+	}, txMgr
+}
+
+func TestDryRunRecordsOperatorTransactions(t *testing.T) {
+	chain := &fakeChain{nonce: 7, gas: 50_000}
+	writer, txMgr := newDryRunAvsWriter(t, chain)
+	ctx := context.Background()
+
+	if _, err := writer.DeregisterOperator(ctx, eigentypes.QuorumNums{0}, regcoord.BN254G1Point{}, true); err != nil {
+		t.Fatalf("could not dry run deregister: %v", err)
+	}
+	if _, err := writer.UpdateSocket(ctx, "operator.example.com:8090", true); err != nil {
+		t.Fatalf("could not dry run update socket: %v", err)
+	}
+	receipt, err := writer.UpdateMetadataURI(ctx, "https://example.com/operator.json", true)
+	if err != nil {
+		t.Fatalf("could not dry run update metadata URI: %v", err)
+	}
+
+	transactions := txMgr.Transactions()
+	if len(transactions) != 3 {
+		t.Fatalf("expected 3 transactions to be recorded, got %d", len(transactions))
+	}
+	if receipt.TxHash != transactions[2].Hash() {
+		t.Errorf("expected receipt of the recorded transaction, got %s", receipt.TxHash)
+	}
+
+	registryCoordinatorAbi, _ := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	delegationManagerAbi, _ := delegationmanager.ContractDelegationManagerMetaData.GetAbi()
+	expected := []struct {
+		to     ethcommon.Address
+		method string
+		args   []interface{}
+	}{
+		{testRegistryCoordinatorAddr, "deregisterOperator", []interface{}{[]byte{0}}},
+		{testRegistryCoordinatorAddr, "updateSocket", []interface{}{"operator.example.com:8090"}},
+		{testDelegationManagerAddr, "updateOperatorMetadataURI", []interface{}{"https://example.com/operator.json"}},
+	}
+	for i, tx := range transactions {
+		contractAbi := registryCoordinatorAbi
+		if expected[i].to == testDelegationManagerAddr {
+			contractAbi = delegationManagerAbi
+		}
+		data, err := contractAbi.Pack(expected[i].method, expected[i].args...)
+		if err != nil {
+			t.Fatalf("could not pack %s: %v", expected[i].method, err)
+		}
+		if *tx.To() != expected[i].to || string(tx.Data()) != string(data) {
+			t.Errorf("transaction %d is not a call to %s%v", i, expected[i].method, expected[i].args)
+		}
+		if tx.Nonce() != 7 || tx.Gas() != 50_000 || tx.GasTipCap().Int64() != 2 || tx.GasFeeCap().Int64() != 22 {
+			t.Errorf("transaction %d was not completed from the chain: nonce %d gas %d tip %s fee cap %s",
+				i, tx.Nonce(), tx.Gas(), tx.GasTipCap(), tx.GasFeeCap())
+		}
+	}
+	if len(chain.estimated) != 3 || chain.estimated[0].From != testOperatorAddr {
+		t.Errorf("expected the gas of every transaction to be estimated from the operator address")
+	}
+}
+
+func TestDryRunFailsOnReverts(t *testing.T) {
+	chain := &fakeChain{estimateErr: errors.New("execution reverted: RegistryCoordinator._deregisterOperator: operator is not registered")}
+	writer, txMgr := newDryRunAvsWriter(t, chain)
+
+	if _, err := writer.DeregisterOperator(context.Background(), eigentypes.QuorumNums{0}, regcoord.BN254G1Point{}, true); err == nil {
+		t.Fatalf("expected dry run of a reverting transaction to fail")
+	}
+	if transactions := txMgr.Transactions(); len(transactions) != 0 {
+		t.Errorf("expected reverting transaction not to be recorded, got %d", len(transactions))
+	}
+}
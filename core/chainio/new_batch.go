@@ -0,0 +1,152 @@
+package chainio
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+// NewBatch is a batch created in the service manager, decoded from any of the supported
+// versions of its NewBatch event, so consumers don't depend on the version emitted.
+type NewBatch struct {
+	BatchMerkleRoot  [32]byte
+	SenderAddress    ethcommon.Address
+	BatchDataPointer string
+	TaskCreatedBlock uint32
+	// RespondToTaskFeeLimit is nil for event versions without it
+	RespondToTaskFeeLimit *big.Int
+	// EventVersion is the version of the NewBatch event the batch was decoded from
+	EventVersion uint8
+	// Raw is the log of the event
+	Raw types.Log
+}
+
+// BatchIdentifierHash returns the keccak256 of the merkle root and the sender address,
+// which is how the service manager identifies batches.
+func (b *NewBatch) BatchIdentifierHash() [32]byte {
+	batchIdentifier := append(b.BatchMerkleRoot[:], b.SenderAddress[:]...)
+	return *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+}
+
+// newBatchDecoder decodes one version of the NewBatch event.
+// Supporting a new version of the event only takes adding its decoder to newBatchDecoders.
+type newBatchDecoder struct {
+	version uint8
+	// event is the name of the event in the service manager ABI
+	event  string
+	decode func(serviceManager *servicemanager.ContractAlignedLayerServiceManager, log types.Log) (*NewBatch, error)
+}
+
+var newBatchDecoders = []newBatchDecoder{
+	{
+		version: 2,
+		event:   "NewBatchV2",
+		decode: func(serviceManager *servicemanager.ContractAlignedLayerServiceManager, log types.Log) (*NewBatch, error) {
+			event, err := serviceManager.ParseNewBatchV2(log)
+			if err != nil {
+				return nil, err
+			}
+			return &NewBatch{
+				BatchMerkleRoot:  event.BatchMerkleRoot,
+				SenderAddress:    event.SenderAddress,
+				BatchDataPointer: event.BatchDataPointer,
+				TaskCreatedBlock: event.TaskCreatedBlock,
+				Raw:              event.Raw,
+			}, nil
+		},
+	},
+	{
+		version: 3,
+		event:   "NewBatchV3",
+		decode: func(serviceManager *servicemanager.ContractAlignedLayerServiceManager, log types.Log) (*NewBatch, error) {
+			event, err := serviceManager.ParseNewBatchV3(log)
+			if err != nil {
+				return nil, err
+			}
+			return &NewBatch{
+				BatchMerkleRoot:       event.BatchMerkleRoot,
+				SenderAddress:         event.SenderAddress,
+				BatchDataPointer:      event.BatchDataPointer,
+				TaskCreatedBlock:      event.TaskCreatedBlock,
+				RespondToTaskFeeLimit: event.RespondToTaskFeeLimit,
+				Raw:                   event.Raw,
+			}, nil
+		},
+	},
+}
+
+// newBatchDecodersByTopic maps the topic of every supported NewBatch event to its decoder.
+var newBatchDecodersByTopic = func() map[ethcommon.Hash]newBatchDecoder {
+	serviceManagerAbi, err := servicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		panic(fmt.Sprintf("invalid service manager ABI: %v", err))
+	}
+	decoders := make(map[ethcommon.Hash]newBatchDecoder, len(newBatchDecoders))
+	for _, decoder := range newBatchDecoders {
+		event, ok := serviceManagerAbi.Events[decoder.event]
+		if !ok {
+			panic(fmt.Sprintf("event %s not found in the service manager ABI", decoder.event))
+		}
+		decoders[event.ID] = decoder
+	}
+	return decoders
+}()
+
+// newBatchFilterQuery returns the query matching every supported NewBatch event of the service
+// manager. Filters set the block range on it, while subscriptions use it as is.
+func newBatchFilterQuery(serviceManagerAddr ethcommon.Address) ethereum.FilterQuery {
+	topics := make([]ethcommon.Hash, 0, len(newBatchDecodersByTopic))
+	for topic := range newBatchDecodersByTopic {
+		topics = append(topics, topic)
+	}
+	return ethereum.FilterQuery{
+		Addresses: []ethcommon.Address{serviceManagerAddr},
+		Topics:    [][]ethcommon.Hash{topics},
+	}
+}
+
+// decodeNewBatch decodes a log of any of the supported NewBatch events.
+func decodeNewBatch(serviceManager *servicemanager.ContractAlignedLayerServiceManager, log types.Log) (*NewBatch, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log without topics is not a NewBatch event")
+	}
+	decoder, ok := newBatchDecodersByTopic[log.Topics[0]]
+	if !ok {
+		return nil, fmt.Errorf("log with topic %s is not a supported NewBatch event", log.Topics[0])
+	}
+	newBatch, err := decoder.decode(serviceManager, log)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s event: %w", decoder.event, err)
+	}
+	newBatch.EventVersion = decoder.version
+	return newBatch, nil
+}
+
+// filterNewBatches returns the batches created between the given blocks, in the order they were created.
+// A nil toBlock means the latest block.
+func filterNewBatches(ctx context.Context, client ethereum.LogFilterer, serviceManager *servicemanager.ContractAlignedLayerServiceManager, serviceManagerAddr ethcommon.Address, fromBlock uint64, toBlock *big.Int) ([]*NewBatch, error) {
+	query := newBatchFilterQuery(serviceManagerAddr)
+	query.FromBlock = new(big.Int).SetUint64(fromBlock)
+	query.ToBlock = toBlock
+
+	logs, err := client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	newBatches := make([]*NewBatch, 0, len(logs))
+	for _, log := range logs {
+		newBatch, err := decodeNewBatch(serviceManager, log)
+		if err != nil {
+			return nil, err
+		}
+		newBatches = append(newBatches, newBatch)
+	}
+	return newBatches, nil
+}
@@ -0,0 +1,112 @@
+package chainio
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+)
+
+var testServiceManagerAddr = ethcommon.HexToAddress("0x1613beB3B2C4f22Ee086B2b38C1476A3cE7f78E8")
+
+func newTestServiceManager(t *testing.T) *servicemanager.ContractAlignedLayerServiceManager {
+	serviceManager, err := servicemanager.NewContractAlignedLayerServiceManager(testServiceManagerAddr, nil)
+	if err != nil {
+		t.Fatalf("could not bind service manager: %v", err)
+	}
+	return serviceManager
+}
+
+// newBatchLog builds the log the service manager emits for event, with the given non indexed fields.
+func newBatchLog(t *testing.T, event string, merkleRoot [32]byte, blockNumber uint64, fields ...interface{}) types.Log {
+	serviceManagerAbi, err := servicemanager.ContractAlignedLayerServiceManagerMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("could not parse service manager ABI: %v", err)
+	}
+	data, err := serviceManagerAbi.Events[event].Inputs.NonIndexed().Pack(fields...)
+	if err != nil {
+		t.Fatalf("could not pack %s fields: %v", event, err)
+	}
+	return types.Log{
+		Address:     testServiceManagerAddr,
+		Topics:      []ethcommon.Hash{serviceManagerAbi.Events[event].ID, merkleRoot},
+		Data:        data,
+		BlockNumber: blockNumber,
+	}
+}
+
+func TestDecodeNewBatch(t *testing.T) {
+	serviceManager := newTestServiceManager(t)
+	sender := ethcommon.HexToAddress("0x7969c5eD335650692Bc04293B07F5BF2e7A673C0")
+
+	v2 := newBatchLog(t, "NewBatchV2", [32]byte{2}, 10, sender, uint32(9), "https://batches/v2")
+	newBatch, err := decodeNewBatch(serviceManager, v2)
+	if err != nil {
+		t.Fatalf("could not decode NewBatchV2 log: %v", err)
+	}
+	if newBatch.EventVersion != 2 || newBatch.BatchMerkleRoot != [32]byte{2} || newBatch.SenderAddress != sender ||
+		newBatch.TaskCreatedBlock != 9 || newBatch.BatchDataPointer != "https://batches/v2" || newBatch.RespondToTaskFeeLimit != nil {
+		t.Errorf("unexpected NewBatchV2 batch: %+v", newBatch)
+	}
+
+	v3 := newBatchLog(t, "NewBatchV3", [32]byte{3}, 11, sender, uint32(10), "https://batches/v3", big.NewInt(1000))
+	newBatch, err = decodeNewBatch(serviceManager, v3)
+	if err != nil {
+		t.Fatalf("could not decode NewBatchV3 log: %v", err)
+	}
+	if newBatch.EventVersion != 3 || newBatch.BatchMerkleRoot != [32]byte{3} || newBatch.TaskCreatedBlock != 10 ||
+		newBatch.RespondToTaskFeeLimit == nil || newBatch.RespondToTaskFeeLimit.Int64() != 1000 || newBatch.Raw.BlockNumber != 11 {
+		t.Errorf("unexpected NewBatchV3 batch: %+v", newBatch)
+	}
+
+	unknown := v3
+	unknown.Topics = []ethcommon.Hash{{0xff}, {3}}
+	if _, err := decodeNewBatch(serviceManager, unknown); err == nil {
+		t.Errorf("expected log of an unknown event to fail decoding")
+	}
+}
+
+func TestFilterNewBatchesKeepsEventOrder(t *testing.T) {
+	serviceManager := newTestServiceManager(t)
+	sender := ethcommon.HexToAddress("0x7969c5eD335650692Bc04293B07F5BF2e7A673C0")
+	client := &fakeLogFilterer{logs: []types.Log{
+		newBatchLog(t, "NewBatchV3", [32]byte{1}, 10, sender, uint32(9), "https://batches/1", big.NewInt(1)),
+		newBatchLog(t, "NewBatchV2", [32]byte{2}, 11, sender, uint32(10), "https://batches/2"),
+		newBatchLog(t, "NewBatchV3", [32]byte{3}, 12, sender, uint32(11), "https://batches/3", big.NewInt(1)),
+	}}
+
+	newBatches, err := filterNewBatches(context.Background(), client, serviceManager, testServiceManagerAddr, 5, nil)
+	if err != nil {
+		t.Fatalf("could not filter new batches: %v", err)
+	}
+	if len(newBatches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(newBatches))
+	}
+	for i, expectedVersion := range []uint8{3, 2, 3} {
+		if newBatches[i].BatchMerkleRoot != [32]byte{byte(i + 1)} || newBatches[i].EventVersion != expectedVersion {
+			t.Errorf("batch %d: unexpected batch %+v", i, newBatches[i])
+		}
+	}
+
+	if client.query.FromBlock.Uint64() != 5 || len(client.query.Topics) != 1 || len(client.query.Topics[0]) != len(newBatchDecoders) {
+		t.Errorf("unexpected filter query: %+v", client.query)
+	}
+}
+
+type fakeLogFilterer struct {
+	logs  []types.Log
+	query ethereum.FilterQuery
+}
+
+func (f *fakeLogFilterer) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	f.query = query
+	return f.logs, nil
+}
+
+func (f *fakeLogFilterer) SubscribeFilterLogs(context.Context, ethereum.FilterQuery, chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, ethereum.NotFound
+}
@@ -126,29 +126,22 @@ func (s *AvsSubscriber) BlockNumberRetryable(ctx context.Context, config *retry.
 }
 
 /*
-FilterBatchV2Retryable
-Get NewBatchV2 logs from the AVS contract.
+FilterNewBatchesRetryable
+Get the NewBatch logs of every supported event version from the AVS contract, starting at fromBlock.
 - All errors are considered Transient Errors
 - Retry times (3 retries): 1 sec, 2 sec, 4 sec.
 */
-func (s *AvsSubscriber) FilterBatchV2Retryable(opts *bind.FilterOpts, batchMerkleRoot [][32]byte, config *retry.RetryParams) (*servicemanager.ContractAlignedLayerServiceManagerNewBatchV2Iterator, error) {
-	filterNewBatchV2_func := func() (*servicemanager.ContractAlignedLayerServiceManagerNewBatchV2Iterator, error) {
-		return s.AvsContractBindings.ServiceManager.FilterNewBatchV2(opts, batchMerkleRoot)
-	}
-	return retry.RetryWithData(filterNewBatchV2_func, config)
-}
-
-/*
-FilterBatchV3Retryable
-Get NewBatchV3 logs from the AVS contract.
-- All errors are considered Transient Errors
-- Retry times (3 retries): 1 sec, 2 sec, 4 sec.
-*/
-func (s *AvsSubscriber) FilterBatchV3Retryable(opts *bind.FilterOpts, batchMerkleRoot [][32]byte, config *retry.RetryParams) (*servicemanager.ContractAlignedLayerServiceManagerNewBatchV3Iterator, error) {
-	filterNewBatchV2_func := func() (*servicemanager.ContractAlignedLayerServiceManagerNewBatchV3Iterator, error) {
-		return s.AvsContractBindings.ServiceManager.FilterNewBatchV3(opts, batchMerkleRoot)
+func (s *AvsSubscriber) FilterNewBatchesRetryable(ctx context.Context, fromBlock uint64, config *retry.RetryParams) ([]*NewBatch, error) {
+	filterNewBatches_func := func() ([]*NewBatch, error) {
+		// Try with main connection
+		newBatches, err := filterNewBatches(ctx, &s.AvsContractBindings.ethClient, s.AvsContractBindings.ServiceManager, s.AlignedLayerServiceManagerAddr, fromBlock, nil)
+		if err != nil {
+			// If error try with fallback connection
+			newBatches, err = filterNewBatches(ctx, &s.AvsContractBindings.ethClientFallback, s.AvsContractBindings.ServiceManagerFallback, s.AlignedLayerServiceManagerAddr, fromBlock, nil)
+		}
+		return newBatches, err
 	}
-	return retry.RetryWithData(filterNewBatchV2_func, config)
+	return retry.RetryWithData(filterNewBatches_func, config)
 }
 
 /*
@@ -193,39 +186,20 @@ func (s *AvsSubscriber) SubscribeNewHeadRetryable(ctx context.Context, c chan<-
 }
 
 /*
-SubscribeToNewTasksV2Retryable
-Subscribe to NewBatchV2 logs from the AVS contract.
-- All errors are considered Transient Errors
-- Retry times (3 retries): 1 sec, 2 sec, 4 sec.
-*/
-func SubscribeToNewTasksV2Retryable(
-	opts *bind.WatchOpts,
-	serviceManager *servicemanager.ContractAlignedLayerServiceManager,
-	newTaskCreatedChan chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2,
-	batchMerkleRoot [][32]byte,
-	config *retry.RetryParams,
-) (event.Subscription, error) {
-	subscribe_func := func() (event.Subscription, error) {
-		return serviceManager.WatchNewBatchV2(opts, newTaskCreatedChan, batchMerkleRoot)
-	}
-	return retry.RetryWithData(subscribe_func, config)
-}
-
-/*
-SubscribeToNewTasksV3Retryable
-Subscribe to NewBatchV3 logs from the AVS contract.
+SubscribeToNewBatchesRetryable
+Subscribe to the NewBatch logs of every supported event version from the AVS contract.
 - All errors are considered Transient Errors
 - Retry times (3 retries): 1 sec, 2 sec, 4 sec.
 */
-func SubscribeToNewTasksV3Retryable(
-	opts *bind.WatchOpts,
-	serviceManager *servicemanager.ContractAlignedLayerServiceManager,
-	newTaskCreatedChan chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3,
-	batchMerkleRoot [][32]byte,
+func SubscribeToNewBatchesRetryable(
+	ctx context.Context,
+	client ethereum.LogFilterer,
+	serviceManagerAddr common.Address,
+	newBatchLogsChan chan<- types.Log,
 	config *retry.RetryParams,
 ) (event.Subscription, error) {
 	subscribe_func := func() (event.Subscription, error) {
-		return serviceManager.WatchNewBatchV3(opts, newTaskCreatedChan, batchMerkleRoot)
+		return client.SubscribeFilterLogs(ctx, newBatchFilterQuery(serviceManagerAddr), newBatchLogsChan)
 	}
 	return retry.RetryWithData(subscribe_func, config)
 }
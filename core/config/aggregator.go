@@ -15,39 +15,53 @@ type AggregatorConfig struct {
 	EcdsaConfig *EcdsaConfig
 	BlsConfig   *BlsConfig
 	Aggregator  struct {
-		ServerIpPortAddress           string
-		BlsPublicKeyCompendiumAddress common.Address
-		AvsServiceManagerAddress      common.Address
-		EnableMetrics                 bool
-		MetricsIpPortAddress          string
-		TelemetryIpPortAddress        string
-		GarbageCollectorPeriod        time.Duration
-		GarbageCollectorTasksAge      uint64
-		GarbageCollectorTasksInterval uint64
-		BlsServiceTaskTimeout         time.Duration
-		GasBaseBumpPercentage         uint
-		GasBumpIncrementalPercentage  uint
-		GasBumpPercentageLimit        uint
-		TimeToWaitBeforeBump          time.Duration
+		ServerIpPortAddress            string
+		BlsPublicKeyCompendiumAddress  common.Address
+		AvsServiceManagerAddress       common.Address
+		EnableMetrics                  bool
+		MetricsIpPortAddress           string
+		TelemetryIpPortAddress         string
+		GarbageCollectorPeriod         time.Duration
+		GarbageCollectorTasksAge       uint64
+		GarbageCollectorTasksInterval  uint64
+		BlsServiceTaskTimeout          time.Duration
+		GasBaseBumpPercentage          uint
+		GasBumpIncrementalPercentage   uint
+		GasBumpPercentageLimit         uint
+		TimeToWaitBeforeBump           time.Duration
+		TxSignerUrl                    string
+		TxSignerAddress                common.Address
+		OperatorRpcServerIpPortAddress string
+		OperatorRpcTlsCertFile         string
+		OperatorRpcTlsKeyFile          string
+		OperatorRpcClientCaFile        string
+		OperatorRpcRequestTimeout      time.Duration
 	}
 }
 
 type AggregatorConfigFromYaml struct {
 	Aggregator struct {
-		ServerIpPortAddress           string         `yaml:"server_ip_port_address"`
-		BlsPublicKeyCompendiumAddress common.Address `yaml:"bls_public_key_compendium_address"`
-		AvsServiceManagerAddress      common.Address `yaml:"avs_service_manager_address"`
-		EnableMetrics                 bool           `yaml:"enable_metrics"`
-		MetricsIpPortAddress          string         `yaml:"metrics_ip_port_address"`
-		TelemetryIpPortAddress        string         `yaml:"telemetry_ip_port_address"`
-		GarbageCollectorPeriod        time.Duration  `yaml:"garbage_collector_period"`
-		GarbageCollectorTasksAge      uint64         `yaml:"garbage_collector_tasks_age"`
-		GarbageCollectorTasksInterval uint64         `yaml:"garbage_collector_tasks_interval"`
-		BlsServiceTaskTimeout         time.Duration  `yaml:"bls_service_task_timeout"`
-		GasBaseBumpPercentage         uint           `yaml:"gas_base_bump_percentage"`
-		GasBumpIncrementalPercentage  uint           `yaml:"gas_bump_incremental_percentage"`
-		GasBumpPercentageLimit        uint           `yaml:"gas_bump_percentage_limit"`
-		TimeToWaitBeforeBump          time.Duration  `yaml:"time_to_wait_before_bump"`
+		ServerIpPortAddress            string         `yaml:"server_ip_port_address"`
+		BlsPublicKeyCompendiumAddress  common.Address `yaml:"bls_public_key_compendium_address"`
+		AvsServiceManagerAddress       common.Address `yaml:"avs_service_manager_address"`
+		EnableMetrics                  bool           `yaml:"enable_metrics"`
+		MetricsIpPortAddress           string         `yaml:"metrics_ip_port_address"`
+		TelemetryIpPortAddress         string         `yaml:"telemetry_ip_port_address"`
+		GarbageCollectorPeriod         time.Duration  `yaml:"garbage_collector_period"`
+		GarbageCollectorTasksAge       uint64         `yaml:"garbage_collector_tasks_age"`
+		GarbageCollectorTasksInterval  uint64         `yaml:"garbage_collector_tasks_interval"`
+		BlsServiceTaskTimeout          time.Duration  `yaml:"bls_service_task_timeout"`
+		GasBaseBumpPercentage          uint           `yaml:"gas_base_bump_percentage"`
+		GasBumpIncrementalPercentage   uint           `yaml:"gas_bump_incremental_percentage"`
+		GasBumpPercentageLimit         uint           `yaml:"gas_bump_percentage_limit"`
+		TimeToWaitBeforeBump           time.Duration  `yaml:"time_to_wait_before_bump"`
+		TxSignerUrl                    string         `yaml:"tx_signer_url"`
+		TxSignerAddress                common.Address `yaml:"tx_signer_address"`
+		OperatorRpcServerIpPortAddress string         `yaml:"operator_rpc_server_ip_port_address"`
+		OperatorRpcTlsCertFile         string         `yaml:"operator_rpc_tls_cert_file"`
+		OperatorRpcTlsKeyFile          string         `yaml:"operator_rpc_tls_key_file"`
+		OperatorRpcClientCaFile        string         `yaml:"operator_rpc_client_ca_file"`
+		OperatorRpcRequestTimeout      time.Duration  `yaml:"operator_rpc_request_timeout"`
 	} `yaml:"aggregator"`
 }
 
@@ -62,7 +76,22 @@ func NewAggregatorConfig(configFilePath string) *AggregatorConfig {
 		log.Fatal("Error reading base config: ")
 	}
 
-	ecdsaConfig := NewEcdsaConfig(configFilePath, baseConfig.ChainId)
+	var aggregatorConfigFromYaml AggregatorConfigFromYaml
+	err := utils.ReadYamlConfig(configFilePath, &aggregatorConfigFromYaml)
+	if err != nil {
+		log.Fatal("Error reading aggregator config: ", err)
+	}
+
+	// The responses are signed by the external signer when configured, the ecdsa key store is then not needed
+	var ecdsaConfig *EcdsaConfig
+	if aggregatorConfigFromYaml.Aggregator.TxSignerUrl != "" {
+		if aggregatorConfigFromYaml.Aggregator.TxSignerAddress == (common.Address{}) {
+			log.Fatal("Aggregator tx signer address is empty")
+		}
+		ecdsaConfig = NewExternalSignerEcdsaConfig(aggregatorConfigFromYaml.Aggregator.TxSignerUrl, aggregatorConfigFromYaml.Aggregator.TxSignerAddress, baseConfig.ChainId)
+	} else {
+		ecdsaConfig = NewEcdsaConfig(configFilePath, baseConfig.ChainId)
+	}
 	if ecdsaConfig == nil {
 		log.Fatal("Error reading ecdsa config: ")
 	}
@@ -72,31 +101,32 @@ func NewAggregatorConfig(configFilePath string) *AggregatorConfig {
 		log.Fatal("Error reading bls config: ")
 	}
 
-	var aggregatorConfigFromYaml AggregatorConfigFromYaml
-	err := utils.ReadYamlConfig(configFilePath, &aggregatorConfigFromYaml)
-	if err != nil {
-		log.Fatal("Error reading aggregator config: ", err)
-	}
-
 	return &AggregatorConfig{
 		BaseConfig:  baseConfig,
 		EcdsaConfig: ecdsaConfig,
 		BlsConfig:   blsConfig,
 		Aggregator: struct {
-			ServerIpPortAddress           string
-			BlsPublicKeyCompendiumAddress common.Address
-			AvsServiceManagerAddress      common.Address
-			EnableMetrics                 bool
-			MetricsIpPortAddress          string
-			TelemetryIpPortAddress        string
-			GarbageCollectorPeriod        time.Duration
-			GarbageCollectorTasksAge      uint64
-			GarbageCollectorTasksInterval uint64
-			BlsServiceTaskTimeout         time.Duration
-			GasBaseBumpPercentage         uint
-			GasBumpIncrementalPercentage  uint
-			GasBumpPercentageLimit        uint
-			TimeToWaitBeforeBump          time.Duration
+			ServerIpPortAddress            string
+			BlsPublicKeyCompendiumAddress  common.Address
+			AvsServiceManagerAddress       common.Address
+			EnableMetrics                  bool
+			MetricsIpPortAddress           string
+			TelemetryIpPortAddress         string
+			GarbageCollectorPeriod         time.Duration
+			GarbageCollectorTasksAge       uint64
+			GarbageCollectorTasksInterval  uint64
+			BlsServiceTaskTimeout          time.Duration
+			GasBaseBumpPercentage          uint
+			GasBumpIncrementalPercentage   uint
+			GasBumpPercentageLimit         uint
+			TimeToWaitBeforeBump           time.Duration
+			TxSignerUrl                    string
+			TxSignerAddress                common.Address
+			OperatorRpcServerIpPortAddress string
+			OperatorRpcTlsCertFile         string
+			OperatorRpcTlsKeyFile          string
+			OperatorRpcClientCaFile        string
+			OperatorRpcRequestTimeout      time.Duration
 		}(aggregatorConfigFromYaml.Aggregator),
 	}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/urfave/cli/v2"
 	"github.com/yetanotherco/aligned_layer/core/utils"
+	"go.uber.org/zap"
 )
 
 var (
@@ -27,6 +28,7 @@ type BaseConfig struct {
 	AlignedLayerDeploymentConfig *AlignedLayerDeploymentConfig
 	EigenLayerDeploymentConfig   *EigenLayerDeploymentConfig
 	Logger                       sdklogging.Logger
+	LogLevel                     zap.AtomicLevel // Level of Logger, it can be changed at runtime
 	EthRpcUrl                    string
 	EthWsUrl                     string
 	EthRpcClient                 eth.InstrumentedClient
@@ -90,7 +92,7 @@ func NewBaseConfig(configFilePath string) *BaseConfig {
 	if eigenLayerDeploymentConfig == nil {
 		log.Fatal("Error reading eigen layer deployment config: ", err)
 	}
-	logger, err := NewLogger(baseConfigFromYaml.Environment)
+	logger, logLevel, err := NewLogger(baseConfigFromYaml.Environment)
 
 	if err != nil {
 		log.Fatal("Error initializing logger: ", err)
@@ -145,6 +147,7 @@ func NewBaseConfig(configFilePath string) *BaseConfig {
 		AlignedLayerDeploymentConfig: alignedLayerDeploymentConfig,
 		EigenLayerDeploymentConfig:   eigenLayerDeploymentConfig,
 		Logger:                       logger,
+		LogLevel:                     logLevel,
 		EthRpcUrl:                    baseConfigFromYaml.EthRpcUrl,
 		EthWsUrl:                     baseConfigFromYaml.EthWsUrl,
 		EthRpcClient:                 *ethRpcClient,
@@ -1,22 +1,30 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"log"
 	"os"
 
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/yetanotherco/aligned_layer/core/tasksigner"
 	"github.com/yetanotherco/aligned_layer/core/utils"
 )
 
 type BlsConfig struct {
+	// KeyPair is nil when the key is only held by a remote signer
 	KeyPair *bls.KeyPair
+	Signer  tasksigner.TaskSigner
 }
 
 type BlsConfigFromYaml struct {
 	Bls struct {
-		PrivateKeyStorePath     string `yaml:"private_key_store_path"`
-		PrivateKeyStorePassword string `yaml:"private_key_store_password"`
+		PrivateKeyStorePath        string `yaml:"private_key_store_path"`
+		PrivateKeyStorePassword    string `yaml:"private_key_store_password"`
+		RemoteSignerUrl            string `yaml:"remote_signer_url"`
+		RemoteSignerCaCertFile     string `yaml:"remote_signer_ca_cert_file"`
+		RemoteSignerClientCertFile string `yaml:"remote_signer_client_cert_file"`
+		RemoteSignerClientKeyFile  string `yaml:"remote_signer_client_key_file"`
 	} `yaml:"bls"`
 }
 
@@ -31,16 +39,40 @@ func NewBlsConfig(blsConfigFilePath string) *BlsConfig {
 		log.Fatal("Error reading bls config: ", err)
 	}
 
-	if blsConfigFromYaml.Bls.PrivateKeyStorePath == "" {
+	if blsConfigFromYaml.Bls.PrivateKeyStorePath == "" && blsConfigFromYaml.Bls.RemoteSignerUrl == "" {
 		log.Fatal("Bls private key store path is empty")
 	}
 
-	blsKeyPair, err := bls.ReadPrivateKeyFromFile(blsConfigFromYaml.Bls.PrivateKeyStorePath, blsConfigFromYaml.Bls.PrivateKeyStorePassword)
-	if err != nil {
-		log.Fatal("Error reading bls private key from file: ", err)
+	var blsKeyPair *bls.KeyPair
+	if blsConfigFromYaml.Bls.PrivateKeyStorePath != "" {
+		blsKeyPair, err = bls.ReadPrivateKeyFromFile(blsConfigFromYaml.Bls.PrivateKeyStorePath, blsConfigFromYaml.Bls.PrivateKeyStorePassword)
+		if err != nil {
+			log.Fatal("Error reading bls private key from file: ", err)
+		}
+	}
+
+	// The remote signer is used to sign when configured, the keystore is then only needed to register
+	var signer tasksigner.TaskSigner
+	if blsConfigFromYaml.Bls.RemoteSignerUrl != "" {
+		remoteSigner, err := tasksigner.NewRemoteSigner(context.Background(), tasksigner.RemoteSignerConfig{
+			Url:            blsConfigFromYaml.Bls.RemoteSignerUrl,
+			CaCertFile:     blsConfigFromYaml.Bls.RemoteSignerCaCertFile,
+			ClientCertFile: blsConfigFromYaml.Bls.RemoteSignerClientCertFile,
+			ClientKeyFile:  blsConfigFromYaml.Bls.RemoteSignerClientKeyFile,
+		})
+		if err != nil {
+			log.Fatal("Error connecting to bls remote signer: ", err)
+		}
+		if blsKeyPair != nil && !blsKeyPair.GetPubKeyG1().Equal(remoteSigner.PubKeyG1().G1Affine) {
+			log.Fatal("Bls remote signer key doesn't match the key in the private key store")
+		}
+		signer = remoteSigner
+	} else {
+		signer = tasksigner.NewLocalSigner(blsKeyPair)
 	}
 
 	return &BlsConfig{
 		KeyPair: blsKeyPair,
+		Signer:  signer,
 	}
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"errors"
 	"log"
@@ -9,12 +10,16 @@ import (
 
 	ecdsa2 "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
 	"github.com/Layr-Labs/eigensdk-go/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/txsigner"
 	"github.com/yetanotherco/aligned_layer/core/utils"
 )
 
 type EcdsaConfig struct {
-	PrivateKey *ecdsa.PrivateKey
-	Signer     signer.Signer
+	// PrivateKey is nil when transactions are signed by an external signer
+	PrivateKey     *ecdsa.PrivateKey
+	Signer         signer.Signer
+	ExternalSigner *txsigner.ExternalSigner
 }
 
 type EcdsaConfigFromYaml struct {
@@ -54,3 +59,17 @@ func NewEcdsaConfig(ecdsaConfigFilePath string, chainId *big.Int) *EcdsaConfig {
 		Signer:     privateKeySigner,
 	}
 }
+
+// NewExternalSignerEcdsaConfig creates the config of an account whose transactions are signed with
+// eth_signTransaction by the external signer at url.
+func NewExternalSignerEcdsaConfig(url string, address common.Address, chainId *big.Int) *EcdsaConfig {
+	externalSigner, err := txsigner.NewExternalSigner(context.Background(), url, address, chainId)
+	if err != nil {
+		log.Fatal("Error connecting to external transaction signer: ", err)
+	}
+
+	return &EcdsaConfig{
+		Signer:         externalSigner,
+		ExternalSigner: externalSigner,
+	}
+}
@@ -4,13 +4,27 @@ import (
 	"fmt"
 
 	sdklogging "github.com/Layr-Labs/eigensdk-go/logging"
+	"go.uber.org/zap"
 )
 
-func NewLogger(loggingLevel sdklogging.LogLevel) (sdklogging.Logger, error) {
-	logger, err := sdklogging.NewZapLogger(loggingLevel)
+// NewLogger creates the logger for the given environment, along with the level it logs at,
+// which can be changed while the logger is in use.
+func NewLogger(loggingLevel sdklogging.LogLevel) (sdklogging.Logger, zap.AtomicLevel, error) {
+	var zapConfig zap.Config
+	switch loggingLevel {
+	case sdklogging.Production:
+		zapConfig = zap.NewProductionConfig()
+	case sdklogging.Development:
+		zapConfig = zap.NewDevelopmentConfig()
+	default:
+		fmt.Println("Could not initialize logger")
+		return nil, zap.AtomicLevel{}, fmt.Errorf("unknown environment %q, expected %s or %s", loggingLevel, sdklogging.Development, sdklogging.Production)
+	}
+
+	logger, err := sdklogging.NewZapLoggerByConfig(zapConfig, zap.AddCallerSkip(1))
 	if err != nil {
 		fmt.Println("Could not initialize logger")
-		return nil, err
+		return nil, zap.AtomicLevel{}, err
 	}
-	return logger, nil
+	return logger, zapConfig.Level, nil
 }
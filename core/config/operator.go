@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log"
 	"os"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/yetanotherco/aligned_layer/core/utils"
@@ -15,37 +16,97 @@ type OperatorConfig struct {
 	AlignedLayerDeploymentConfig *AlignedLayerDeploymentConfig
 
 	Operator struct {
-		AggregatorServerIpPortAddress string
-		OperatorTrackerIpPortAddress  string
-		Address                       common.Address
-		EarningsReceiverAddress       common.Address
-		DelegationApproverAddress     common.Address
-		StakerOptOutWindowBlocks      int
-		MetadataUrl                   string
-		RegisterOperatorOnStartup     bool
-		EnableMetrics                 bool
-		MetricsIpPortAddress          string
-		MaxBatchSize                  int64
-		LastProcessedBatchFilePath    string
+		AggregatorServerIpPortAddress              string
+		AggregatorRpcUrl                           string
+		AggregatorRpcCaCertFile                    string
+		AggregatorRpcClientCertFile                string
+		AggregatorRpcClientKeyFile                 string
+		AggregatorRpcTimeout                       time.Duration
+		AggregatorEndpoints                        []string
+		OperatorTrackerIpPortAddress               string
+		Address                                    common.Address
+		EarningsReceiverAddress                    common.Address
+		DelegationApproverAddress                  common.Address
+		StakerOptOutWindowBlocks                   int
+		MetadataUrl                                string
+		RegisterOperatorOnStartup                  bool
+		EnableMetrics                              bool
+		MetricsIpPortAddress                       string
+		MaxBatchSize                               int64
+		LastProcessedBatchFilePath                 string
+		BatchJournalFilePath                       string
+		AuditLogFilePath                           string
+		OutboxDir                                  string
+		AggregatorTaskTimeout                      time.Duration
+		MaxConcurrentVerifications                 int
+		MaxConcurrentVerificationsPerProvingSystem map[string]int
+		VerificationReportsDir                     string
+		MaxVerificationReports                     int
+		BatchCacheDir                              string
+		BatchCacheMaxSize                          int64
+		BatchCacheMaxAge                           time.Duration
+		BatchMirrors                               []string
+		BatchUrlRewrites                           []BatchUrlRewrite
+		BatchDownloadHedgeDelay                    time.Duration
+		ShutdownTimeout                            time.Duration
+		HealthIpPortAddress                        string
+		HealthMaxHeadAge                           time.Duration
+		HealthMaxVerificationBacklog               int64
+		AdminSocketPath                            string
+		AdminIpPortAddress                         string
+		AdminTokenFile                             string
 	}
 }
 
+// BatchUrlRewrite replaces the Prefix of the URL batches are downloaded from with Replacement.
+type BatchUrlRewrite struct {
+	Prefix      string `yaml:"prefix"`
+	Replacement string `yaml:"replacement"`
+}
+
 type OperatorConfigFromYaml struct {
 	Operator struct {
-		AggregatorServerIpPortAddress string         `yaml:"aggregator_rpc_server_ip_port_address"`
-		OperatorTrackerIpPortAddress  string         `yaml:"operator_tracker_ip_port_address"`
-		Address                       common.Address `yaml:"address"`
-		EarningsReceiverAddress       common.Address `yaml:"earnings_receiver_address"`
-		DelegationApproverAddress     common.Address `yaml:"delegation_approver_address"`
-		StakerOptOutWindowBlocks      int            `yaml:"staker_opt_out_window_blocks"`
-		MetadataUrl                   string         `yaml:"metadata_url"`
-		RegisterOperatorOnStartup     bool           `yaml:"register_operator_on_startup"`
-		EnableMetrics                 bool           `yaml:"enable_metrics"`
-		MetricsIpPortAddress          string         `yaml:"metrics_ip_port_address"`
-		MaxBatchSize                  int64          `yaml:"max_batch_size"`
-		LastProcessedBatchFilePath    string         `yaml:"last_processed_batch_filepath"`
+		AggregatorServerIpPortAddress              string            `yaml:"aggregator_rpc_server_ip_port_address"`
+		AggregatorRpcUrl                           string            `yaml:"aggregator_rpc_url"`
+		AggregatorRpcCaCertFile                    string            `yaml:"aggregator_rpc_ca_cert_file"`
+		AggregatorRpcClientCertFile                string            `yaml:"aggregator_rpc_client_cert_file"`
+		AggregatorRpcClientKeyFile                 string            `yaml:"aggregator_rpc_client_key_file"`
+		AggregatorRpcTimeout                       time.Duration     `yaml:"aggregator_rpc_timeout"`
+		AggregatorEndpoints                        []string          `yaml:"aggregator_endpoints"`
+		OperatorTrackerIpPortAddress               string            `yaml:"operator_tracker_ip_port_address"`
+		Address                                    common.Address    `yaml:"address"`
+		EarningsReceiverAddress                    common.Address    `yaml:"earnings_receiver_address"`
+		DelegationApproverAddress                  common.Address    `yaml:"delegation_approver_address"`
+		StakerOptOutWindowBlocks                   int               `yaml:"staker_opt_out_window_blocks"`
+		MetadataUrl                                string            `yaml:"metadata_url"`
+		RegisterOperatorOnStartup                  bool              `yaml:"register_operator_on_startup"`
+		EnableMetrics                              bool              `yaml:"enable_metrics"`
+		MetricsIpPortAddress                       string            `yaml:"metrics_ip_port_address"`
+		MaxBatchSize                               int64             `yaml:"max_batch_size"`
+		LastProcessedBatchFilePath                 string            `yaml:"last_processed_batch_filepath"`
+		BatchJournalFilePath                       string            `yaml:"batch_journal_filepath"`
+		AuditLogFilePath                           string            `yaml:"audit_log_filepath"`
+		OutboxDir                                  string            `yaml:"outbox_dir"`
+		AggregatorTaskTimeout                      time.Duration     `yaml:"aggregator_task_timeout"`
+		MaxConcurrentVerifications                 int               `yaml:"max_concurrent_verifications"`
+		MaxConcurrentVerificationsPerProvingSystem map[string]int    `yaml:"max_concurrent_verifications_per_proving_system"`
+		VerificationReportsDir                     string            `yaml:"verification_reports_dir"`
+		MaxVerificationReports                     int               `yaml:"max_verification_reports"`
+		BatchCacheDir                              string            `yaml:"batch_cache_dir"`
+		BatchCacheMaxSize                          int64             `yaml:"batch_cache_max_size"`
+		BatchCacheMaxAge                           time.Duration     `yaml:"batch_cache_max_age"`
+		BatchMirrors                               []string          `yaml:"batch_mirrors"`
+		BatchUrlRewrites                           []BatchUrlRewrite `yaml:"batch_url_rewrites"`
+		BatchDownloadHedgeDelay                    time.Duration     `yaml:"batch_download_hedge_delay"`
+		ShutdownTimeout                            time.Duration     `yaml:"shutdown_timeout"`
+		HealthIpPortAddress                        string            `yaml:"health_ip_port_address"`
+		HealthMaxHeadAge                           time.Duration     `yaml:"health_max_head_age"`
+		HealthMaxVerificationBacklog               int64             `yaml:"health_max_verification_backlog"`
+		AdminSocketPath                            string            `yaml:"admin_socket_path"`
+		AdminIpPortAddress                         string            `yaml:"admin_ip_port_address"`
+		AdminTokenFile                             string            `yaml:"admin_token_file"`
 	} `yaml:"operator"`
-	BlsConfigFromYaml   BlsConfigFromYaml   `yaml:"bls"`
+	BlsConfigFromYaml BlsConfigFromYaml `yaml:"bls"`
 }
 
 func NewOperatorConfig(configFilePath string) *OperatorConfig {
@@ -75,18 +136,45 @@ func NewOperatorConfig(configFilePath string) *OperatorConfig {
 		BlsConfig:                    blsConfig,
 		AlignedLayerDeploymentConfig: baseConfig.AlignedLayerDeploymentConfig,
 		Operator: struct {
-			AggregatorServerIpPortAddress string
-			OperatorTrackerIpPortAddress  string
-			Address                       common.Address
-			EarningsReceiverAddress       common.Address
-			DelegationApproverAddress     common.Address
-			StakerOptOutWindowBlocks      int
-			MetadataUrl                   string
-			RegisterOperatorOnStartup     bool
-			EnableMetrics                 bool
-			MetricsIpPortAddress          string
-			MaxBatchSize                  int64
-			LastProcessedBatchFilePath    string
+			AggregatorServerIpPortAddress              string
+			AggregatorRpcUrl                           string
+			AggregatorRpcCaCertFile                    string
+			AggregatorRpcClientCertFile                string
+			AggregatorRpcClientKeyFile                 string
+			AggregatorRpcTimeout                       time.Duration
+			AggregatorEndpoints                        []string
+			OperatorTrackerIpPortAddress               string
+			Address                                    common.Address
+			EarningsReceiverAddress                    common.Address
+			DelegationApproverAddress                  common.Address
+			StakerOptOutWindowBlocks                   int
+			MetadataUrl                                string
+			RegisterOperatorOnStartup                  bool
+			EnableMetrics                              bool
+			MetricsIpPortAddress                       string
+			MaxBatchSize                               int64
+			LastProcessedBatchFilePath                 string
+			BatchJournalFilePath                       string
+			AuditLogFilePath                           string
+			OutboxDir                                  string
+			AggregatorTaskTimeout                      time.Duration
+			MaxConcurrentVerifications                 int
+			MaxConcurrentVerificationsPerProvingSystem map[string]int
+			VerificationReportsDir                     string
+			MaxVerificationReports                     int
+			BatchCacheDir                              string
+			BatchCacheMaxSize                          int64
+			BatchCacheMaxAge                           time.Duration
+			BatchMirrors                               []string
+			BatchUrlRewrites                           []BatchUrlRewrite
+			BatchDownloadHedgeDelay                    time.Duration
+			ShutdownTimeout                            time.Duration
+			HealthIpPortAddress                        string
+			HealthMaxHeadAge                           time.Duration
+			HealthMaxVerificationBacklog               int64
+			AdminSocketPath                            string
+			AdminIpPortAddress                         string
+			AdminTokenFile                             string
 		}(operatorConfigFromYaml.Operator),
 	}
 }
@@ -0,0 +1,192 @@
+package tasksigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	retry "github.com/yetanotherco/aligned_layer/core"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+const DefaultRemoteSignerTimeout = 5 * time.Second
+
+// Responses are small JSON documents, anything bigger is not from a signer
+const maxSignerResponseSize = 1 << 12
+
+// RemoteSignerConfig is how the operator reaches a signer service that holds its BLS key.
+type RemoteSignerConfig struct {
+	// Url of the signer, it must be https unless the signer runs on the same host
+	Url string
+	// PEM file with the certificates the signer certificate is checked against, the system ones if empty
+	CaCertFile string
+	// Certificate and key presented to signers that require mutual TLS
+	ClientCertFile string
+	ClientKeyFile  string
+	// Timeout of each request to the signer
+	Timeout time.Duration
+}
+
+// RemoteSigner signs through the HTTP API of a signer service, like the one served by Server.
+// Every signature it gets back is verified against the public key of the signer before being used.
+type RemoteSigner struct {
+	baseUrl  string
+	client   *http.Client
+	pubKeyG1 *bls.G1Point
+	pubKeyG2 *bls.G2Point
+}
+
+var _ TaskSigner = (*RemoteSigner)(nil)
+
+// NewRemoteSigner connects to the signer at config.Url and fetches the public keys it signs with.
+func NewRemoteSigner(ctx context.Context, config RemoteSignerConfig) (*RemoteSigner, error) {
+	signerUrl, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer url: %w", err)
+	}
+	if signerUrl.Scheme != "https" && !(signerUrl.Scheme == "http" && isLoopback(signerUrl.Hostname())) {
+		return nil, fmt.Errorf("remote signer url %s must be https, plain http is only allowed for signers on the same host", config.Url)
+	}
+
+	tlsConfig, err := remoteSignerTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRemoteSignerTimeout
+	}
+	signer := &RemoteSigner{
+		baseUrl: signerUrl.String(),
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}
+
+	var response PublicKeyResponse
+	if err := signer.do(ctx, http.MethodGet, PublicKeyPath, nil, &response); err != nil {
+		return nil, fmt.Errorf("could not get public key of remote signer: %w", err)
+	}
+	signer.pubKeyG1, err = decodeG1Point(response.PubKeyG1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: G1: %v", ErrInvalidPublicKey, err)
+	}
+	signer.pubKeyG2, err = decodeG2Point(response.PubKeyG2)
+	if err != nil {
+		return nil, fmt.Errorf("%w: G2: %v", ErrInvalidPublicKey, err)
+	}
+	if err := checkPublicKeys(signer.pubKeyG1, signer.pubKeyG2); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+func remoteSignerTLSConfig(config RemoteSignerConfig) (*tls.Config, error) {
+	tlsConfig, err := utils.NewClientTLSConfig(config.CaCertFile, config.ClientCertFile, config.ClientKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer TLS config: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+/*
+SignMessage
+Asks the remote signer to sign message, and verifies the signature against its public key.
+- Transport errors and server errors are retried.
+- Refused requests and invalid signatures are permanent errors, they are not retried.
+*/
+func (s *RemoteSigner) SignMessage(ctx context.Context, messageType MessageType, message [32]byte) (*bls.Signature, error) {
+	request := SignRequest{
+		MessageType: messageType,
+		Message:     "0x" + fmt.Sprintf("%x", message),
+	}
+	sign := func() (*bls.Signature, error) {
+		var response SignResponse
+		if err := s.do(ctx, http.MethodPost, SignPath, request, &response); err != nil {
+			return nil, err
+		}
+		point, err := decodeG1Point(response.Signature)
+		if err != nil {
+			return nil, retry.PermanentError{Inner: fmt.Errorf("%w: %v", ErrInvalidSignature, err)}
+		}
+		signature := &bls.Signature{G1Point: point}
+		if err := verifySignature(signature, s.pubKeyG2, message); err != nil {
+			return nil, retry.PermanentError{Inner: err}
+		}
+		return signature, nil
+	}
+	return retry.RetryWithData(sign, retry.NetworkRetryParams())
+}
+
+func (s *RemoteSigner) PubKeyG1() *bls.G1Point {
+	return s.pubKeyG1
+}
+
+func (s *RemoteSigner) PubKeyG2() *bls.G2Point {
+	return s.pubKeyG2
+}
+
+// do sends a request to the signer and decodes its response into response. Client errors
+// are permanent, as the same request would be refused again.
+func (s *RemoteSigner) do(ctx context.Context, method string, path string, request interface{}, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return retry.PermanentError{Inner: err}
+	}
+	var body io.Reader
+	if request != nil {
+		encoded, err := json.Marshal(request)
+		if err != nil {
+			return retry.PermanentError{Inner: err}
+		}
+		body = bytes.NewReader(encoded)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, method, s.baseUrl+path, body)
+	if err != nil {
+		return retry.PermanentError{Inner: err}
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := s.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+	responseBody, err := io.ReadAll(io.LimitReader(httpResponse.Body, maxSignerResponseSize))
+	if err != nil {
+		return err
+	}
+
+	if httpResponse.StatusCode != http.StatusOK {
+		var signerError errorResponse
+		_ = json.Unmarshal(responseBody, &signerError)
+		err := fmt.Errorf("remote signer responded %s: %s", httpResponse.Status, signerError.Error)
+		switch {
+		case httpResponse.StatusCode == http.StatusForbidden:
+			return retry.PermanentError{Inner: fmt.Errorf("%w: %v", ErrMessageTypeNotAllowed, err)}
+		case httpResponse.StatusCode >= 400 && httpResponse.StatusCode < 500:
+			return retry.PermanentError{Inner: err}
+		}
+		return err
+	}
+	if err := json.Unmarshal(responseBody, response); err != nil {
+		return retry.PermanentError{Inner: fmt.Errorf("invalid remote signer response: %w", err)}
+	}
+	return nil
+}
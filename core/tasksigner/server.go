@@ -0,0 +1,107 @@
+package tasksigner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+const (
+	PublicKeyPath = "/v1/public-key"
+	SignPath      = "/v1/sign"
+	// Sign requests only carry a type and a 32 bytes message
+	maxSignRequestSize = 1 << 10
+)
+
+// PublicKeyResponse is the body of the GET /v1/public-key response.
+type PublicKeyResponse struct {
+	PubKeyG1 string `json:"public_key_g1"`
+	PubKeyG2 string `json:"public_key_g2"`
+}
+
+// SignRequest is the body of the POST /v1/sign request. Message is the 0x prefixed hex of the 32 bytes to sign.
+type SignRequest struct {
+	MessageType MessageType `json:"message_type"`
+	Message     string      `json:"message"`
+}
+
+// SignResponse is the body of the POST /v1/sign response, with the signature serialized as a G1 point.
+type SignResponse struct {
+	Signature string `json:"signature"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server signs the messages of an operator with its BLS key, for operators configured with a remote
+// signer. It only signs the allowed message types, and logs every message it signs.
+// It doesn't authenticate clients itself, it is meant to be served with mutual TLS.
+type Server struct {
+	keyPair             *bls.KeyPair
+	allowedMessageTypes map[MessageType]bool
+	logger              logging.Logger
+}
+
+func NewServer(keyPair *bls.KeyPair, allowedMessageTypes []MessageType, logger logging.Logger) *Server {
+	allowed := make(map[MessageType]bool, len(allowedMessageTypes))
+	for _, messageType := range allowedMessageTypes {
+		allowed[messageType] = true
+	}
+	return &Server{
+		keyPair:             keyPair,
+		allowedMessageTypes: allowed,
+		logger:              logger,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+PublicKeyPath, s.handlePublicKey)
+	mux.HandleFunc("POST "+SignPath, s.handleSign)
+	return mux
+}
+
+func (s *Server) handlePublicKey(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, PublicKeyResponse{
+		PubKeyG1: encodeG1Point(s.keyPair.GetPubKeyG1()),
+		PubKeyG2: encodeG2Point(s.keyPair.GetPubKeyG2()),
+	})
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	var request SignRequest
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignRequestSize+1))
+	if err != nil || len(body) > maxSignRequestSize {
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: "could not read request"})
+		return
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	if !s.allowedMessageTypes[request.MessageType] {
+		s.logger.Warn("Refused to sign message", "message type", request.MessageType, "remote", r.RemoteAddr)
+		writeJson(w, http.StatusForbidden, errorResponse{Error: fmt.Sprintf("%s: %q", ErrMessageTypeNotAllowed, request.MessageType)})
+		return
+	}
+	message, err := decodeHex(request.Message, 32)
+	if err != nil {
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid message: %v", err)})
+		return
+	}
+
+	signature := s.keyPair.SignMessage([32]byte(message))
+	s.logger.Info("Signed message", "message type", request.MessageType, "message", request.Message, "remote", r.RemoteAddr)
+	writeJson(w, http.StatusOK, SignResponse{Signature: encodeG1Point(signature.G1Point)})
+}
+
+func writeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
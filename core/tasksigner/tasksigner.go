@@ -0,0 +1,144 @@
+package tasksigner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// MessageType is what a signed message is, so signers can restrict what they sign.
+type MessageType string
+
+const (
+	// The batch identifier hash of a task the operator responds to
+	MessageTypeTaskResponse MessageType = "task_response"
+	// The hash of the operator version, sent to the operator tracker
+	MessageTypeOperatorVersion MessageType = "operator_version"
+)
+
+var (
+	ErrMessageTypeNotAllowed = errors.New("message type not allowed")
+	ErrInvalidSignature      = errors.New("invalid signature")
+	ErrInvalidPublicKey      = errors.New("invalid public key")
+)
+
+// TaskSigner signs the messages of the operator with its BLS key.
+type TaskSigner interface {
+	SignMessage(ctx context.Context, messageType MessageType, message [32]byte) (*bls.Signature, error)
+	// PubKeyG1 is the public key the operator ID is derived from
+	PubKeyG1() *bls.G1Point
+	// PubKeyG2 is the public key the signatures are verified against
+	PubKeyG2() *bls.G2Point
+}
+
+// LocalSigner signs with a BLS key pair held in memory, read from the keystore in the config.
+type LocalSigner struct {
+	keyPair *bls.KeyPair
+}
+
+var _ TaskSigner = (*LocalSigner)(nil)
+
+func NewLocalSigner(keyPair *bls.KeyPair) *LocalSigner {
+	return &LocalSigner{keyPair: keyPair}
+}
+
+func (s *LocalSigner) SignMessage(ctx context.Context, messageType MessageType, message [32]byte) (*bls.Signature, error) {
+	return s.keyPair.SignMessage(message), nil
+}
+
+func (s *LocalSigner) PubKeyG1() *bls.G1Point {
+	return s.keyPair.GetPubKeyG1()
+}
+
+func (s *LocalSigner) PubKeyG2() *bls.G2Point {
+	return s.keyPair.GetPubKeyG2()
+}
+
+// The points are encoded as 0x prefixed hex of their serialization by the SDK.
+const (
+	g1PointLength = 64
+	g2PointLength = 128
+)
+
+func encodeG1Point(point *bls.G1Point) string {
+	return "0x" + ethcommon.Bytes2Hex(point.Serialize())
+}
+
+func encodeG2Point(point *bls.G2Point) string {
+	return "0x" + ethcommon.Bytes2Hex(point.Serialize())
+}
+
+func decodeG1Point(encoded string) (*bls.G1Point, error) {
+	data, err := decodeHex(encoded, g1PointLength)
+	if err != nil {
+		return nil, err
+	}
+	point := new(bls.G1Point).Deserialize(data)
+	if !point.IsOnCurve() || !point.IsInSubGroup() {
+		return nil, errors.New("point is not on the G1 curve")
+	}
+	return point, nil
+}
+
+func decodeG2Point(encoded string) (*bls.G2Point, error) {
+	data, err := decodeHex(encoded, g2PointLength)
+	if err != nil {
+		return nil, err
+	}
+	point := new(bls.G2Point).Deserialize(data)
+	if !point.IsOnCurve() || !point.IsInSubGroup() {
+		return nil, errors.New("point is not on the G2 curve")
+	}
+	return point, nil
+}
+
+func decodeHex(encoded string, length int) ([]byte, error) {
+	if !has0xPrefix(encoded) {
+		return nil, errors.New("missing 0x prefix")
+	}
+	data := ethcommon.FromHex(encoded)
+	if len(data) != length {
+		return nil, fmt.Errorf("expected %d bytes, got %d", length, len(data))
+	}
+	return data, nil
+}
+
+func has0xPrefix(encoded string) bool {
+	return len(encoded) >= 2 && encoded[0] == '0' && (encoded[1] == 'x' || encoded[1] == 'X')
+}
+
+// verifySignature checks signature is the signature of message by the key of pubKeyG2, and
+// that it is a valid point, as signatures from remote signers are not trusted.
+func verifySignature(signature *bls.Signature, pubKeyG2 *bls.G2Point, message [32]byte) error {
+	if signature.G1Point == nil || signature.G1Affine == nil || signature.G1Affine.IsInfinity() {
+		return ErrInvalidSignature
+	}
+	ok, err := signature.Verify(pubKeyG2, message)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// checkPublicKeys makes sure the G1 and G2 public keys belong to the same private key, so the
+// operator ID derived from G1 matches the key signatures are verified against.
+func checkPublicKeys(pubKeyG1 *bls.G1Point, pubKeyG2 *bls.G2Point) error {
+	if pubKeyG1.G1Affine.Equal(new(bn254.G1Affine)) {
+		return fmt.Errorf("%w: zero G1 public key", ErrInvalidPublicKey)
+	}
+	equivalent, err := pubKeyG1.VerifyEquivalence(pubKeyG2)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	if !equivalent {
+		return fmt.Errorf("%w: G1 and G2 public keys don't match", ErrInvalidPublicKey)
+	}
+	return nil
+}
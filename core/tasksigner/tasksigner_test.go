@@ -0,0 +1,119 @@
+package tasksigner
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func newTestKeyPair(t *testing.T, privateKey string) *bls.KeyPair {
+	keyPair, err := bls.NewKeyPairFromString(privateKey)
+	if err != nil {
+		t.Fatalf("could not create key pair: %v", err)
+	}
+	return keyPair
+}
+
+// startSigner serves handler over TLS and returns the config to reach it.
+func startSigner(t *testing.T, handler http.Handler) RemoteSignerConfig {
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caCertFile, caCert, 0o600); err != nil {
+		t.Fatalf("could not write CA certificate: %v", err)
+	}
+	return RemoteSignerConfig{Url: server.URL, CaCertFile: caCertFile}
+}
+
+func TestRemoteSignerSignsLikeLocalSigner(t *testing.T) {
+	keyPair := newTestKeyPair(t, "12345")
+	server := NewServer(keyPair, []MessageType{MessageTypeTaskResponse}, logging.NewTextSLogger(io.Discard, nil))
+	config := startSigner(t, server.Handler())
+
+	remote, err := NewRemoteSigner(context.Background(), config)
+	if err != nil {
+		t.Fatalf("could not connect to remote signer: %v", err)
+	}
+	local := NewLocalSigner(keyPair)
+	if !remote.PubKeyG1().Equal(local.PubKeyG1().G1Affine) || !remote.PubKeyG2().Equal(local.PubKeyG2().G2Affine) {
+		t.Fatalf("remote signer public keys don't match the key pair")
+	}
+
+	message := [32]byte{1, 2, 3}
+	remoteSignature, err := remote.SignMessage(context.Background(), MessageTypeTaskResponse, message)
+	if err != nil {
+		t.Fatalf("could not sign with remote signer: %v", err)
+	}
+	localSignature, _ := local.SignMessage(context.Background(), MessageTypeTaskResponse, message)
+	if !remoteSignature.Equal(localSignature.G1Affine) {
+		t.Errorf("remote signature differs from local signature")
+	}
+
+	_, err = remote.SignMessage(context.Background(), MessageTypeOperatorVersion, message)
+	if !errors.Is(err, ErrMessageTypeNotAllowed) {
+		t.Errorf("expected message type not in the allow-list to be refused, got %v", err)
+	}
+}
+
+func TestRemoteSignerRejectsSignaturesOfAnotherKey(t *testing.T) {
+	keyPair := newTestKeyPair(t, "12345")
+	otherKeyPair := newTestKeyPair(t, "67890")
+	honest := NewServer(keyPair, []MessageType{MessageTypeTaskResponse}, logging.NewTextSLogger(io.Discard, nil))
+	dishonest := NewServer(otherKeyPair, []MessageType{MessageTypeTaskResponse}, logging.NewTextSLogger(io.Discard, nil))
+
+	// Advertises the public key of keyPair but signs with otherKeyPair
+	mux := http.NewServeMux()
+	mux.Handle("GET "+PublicKeyPath, honest.Handler())
+	mux.Handle("POST "+SignPath, dishonest.Handler())
+	remote, err := NewRemoteSigner(context.Background(), startSigner(t, mux))
+	if err != nil {
+		t.Fatalf("could not connect to remote signer: %v", err)
+	}
+
+	_, err = remote.SignMessage(context.Background(), MessageTypeTaskResponse, [32]byte{1})
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected signature of another key to be rejected, got %v", err)
+	}
+}
+
+func TestRemoteSignerRejectsMismatchedPublicKeys(t *testing.T) {
+	keyPair := newTestKeyPair(t, "12345")
+	otherKeyPair := newTestKeyPair(t, "67890")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, PublicKeyResponse{
+			PubKeyG1: encodeG1Point(keyPair.GetPubKeyG1()),
+			PubKeyG2: encodeG2Point(otherKeyPair.GetPubKeyG2()),
+		})
+	})
+
+	_, err := NewRemoteSigner(context.Background(), startSigner(t, handler))
+	if !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("expected public keys of different keys to be rejected, got %v", err)
+	}
+}
+
+func TestRemoteSignerRequiresTLS(t *testing.T) {
+	_, err := NewRemoteSigner(context.Background(), RemoteSignerConfig{Url: "http://signer.example.com:9443"})
+	if err == nil {
+		t.Errorf("expected plain http signer on another host to be rejected")
+	}
+
+	// Without the CA of the signer its certificate can't be verified
+	server := NewServer(newTestKeyPair(t, "12345"), nil, logging.NewTextSLogger(io.Discard, nil))
+	config := startSigner(t, server.Handler())
+	config.CaCertFile = ""
+	if _, err := NewRemoteSigner(context.Background(), config); err == nil {
+		t.Errorf("expected signer with untrusted certificate to be rejected")
+	}
+}
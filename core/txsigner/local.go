@@ -0,0 +1,86 @@
+package txsigner
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NewLocalSignerServer creates a JSON-RPC server answering eth_accounts and eth_signTransaction with
+// privateKey, a stand-in for an external signer in tests and local devnets. It is an http.Handler.
+func NewLocalSignerServer(privateKey *ecdsa.PrivateKey, chainId *big.Int) (*rpc.Server, error) {
+	server := rpc.NewServer()
+	service := &localSignerService{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		chainId:    chainId,
+		signer:     types.LatestSignerForChainID(chainId),
+	}
+	if err := server.RegisterName("eth", service); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+type localSignerService struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+	chainId    *big.Int
+	signer     types.Signer
+}
+
+func (s *localSignerService) Accounts() []common.Address {
+	return []common.Address{s.address}
+}
+
+func (s *localSignerService) SignTransaction(args TransactionArgs) (hexutil.Bytes, error) {
+	if args.From != s.address {
+		return nil, fmt.Errorf("unknown account %s", args.From.Hex())
+	}
+	if args.ChainId != nil && args.ChainId.ToInt().Cmp(s.chainId) != 0 {
+		return nil, fmt.Errorf("chain id %s doesn't match signer chain id %s", args.ChainId.ToInt(), s.chainId)
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+
+	var txData types.TxData
+	switch {
+	case args.GasPrice != nil:
+		txData = &types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    value,
+			Data:     args.Data,
+		}
+	case args.MaxFeePerGas != nil && args.MaxPriorityFeePerGas != nil:
+		txData = &types.DynamicFeeTx{
+			ChainID:   s.chainId,
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     value,
+			Data:      args.Data,
+		}
+	default:
+		return nil, errors.New("missing gas price or fees")
+	}
+
+	signedTx, err := types.SignNewTx(s.privateKey, s.signer, txData)
+	if err != nil {
+		return nil, err
+	}
+	return signedTx.MarshalBinary()
+}
@@ -0,0 +1,211 @@
+package txsigner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/signer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const DefaultExternalSignerTimeout = 10 * time.Second
+
+var (
+	ErrUnknownAccount     = errors.New("account not held by the external signer")
+	ErrInvalidTransaction = errors.New("external signer returned an invalid transaction")
+)
+
+// TransactionArgs are the arguments of eth_signTransaction. Either GasPrice or the EIP-1559 fees are set,
+// following the type of the transaction to sign.
+type TransactionArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainId              *hexutil.Big    `json:"chainId"`
+}
+
+// Backend is the client the transactions of a TxManager are estimated and sent with.
+type Backend interface {
+	wallet.EthBackend
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// ExternalSigner signs transactions through the eth_signTransaction JSON-RPC method of an external signer,
+// like Web3Signer or Clef, so the ECDSA key doesn't have to be on the host sending the transactions.
+// The signer only signs, transactions are still sent by the caller.
+type ExternalSigner struct {
+	client  *rpc.Client
+	address common.Address
+	chainId *big.Int
+	signer  types.Signer
+	timeout time.Duration
+}
+
+var _ signer.Signer = (*ExternalSigner)(nil)
+
+// NewExternalSigner connects to the signer at url and checks it holds the key of address.
+func NewExternalSigner(ctx context.Context, url string, address common.Address, chainId *big.Int) (*ExternalSigner, error) {
+	client, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{Timeout: DefaultExternalSignerTimeout}))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to external signer: %w", err)
+	}
+	externalSigner := &ExternalSigner{
+		client:  client,
+		address: address,
+		chainId: chainId,
+		signer:  types.LatestSignerForChainID(chainId),
+		timeout: DefaultExternalSignerTimeout,
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("could not get accounts of external signer: %w", err)
+	}
+	for _, account := range accounts {
+		if account == address {
+			return externalSigner, nil
+		}
+	}
+	client.Close()
+	return nil, fmt.Errorf("%w: %s", ErrUnknownAccount, address.Hex())
+}
+
+func (s *ExternalSigner) Address() common.Address {
+	return s.address
+}
+
+// GetTxOpts returns the options to sign transactions of the account with the external signer.
+func (s *ExternalSigner) GetTxOpts() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:   s.address,
+		Signer: s.SignTransaction,
+	}
+}
+
+// SendToExternal is not supported, the external signer only signs transactions.
+func (s *ExternalSigner) SendToExternal(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	return common.Hash{}, errors.New("external signer doesn't send transactions")
+}
+
+// SignerFn is the signer of the account for the SDK wallets.
+func (s *ExternalSigner) SignerFn(ctx context.Context, address common.Address) (bind.SignerFn, error) {
+	if address != s.address {
+		return nil, bind.ErrNotAuthorized
+	}
+	return s.SignTransaction, nil
+}
+
+// NewTxManager creates a TxManager that sends through client the transactions signed by the external signer.
+func (s *ExternalSigner) NewTxManager(client Backend, logger logging.Logger) (txmgr.TxManager, error) {
+	externalWallet, err := wallet.NewPrivateKeyWallet(client, s.SignerFn, s.address, logger)
+	if err != nil {
+		return nil, err
+	}
+	return txmgr.NewSimpleTxManager(externalWallet, client, logger, s.address), nil
+}
+
+/*
+SignTransaction
+Signs tx with the external signer. It is a bind.SignerFn, so it can be used in bind.TransactOpts.
+The signed transaction is checked to be tx signed by address, so a misbehaving signer can't get
+a different transaction sent.
+*/
+func (s *ExternalSigner) SignTransaction(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	if address != s.address {
+		return nil, bind.ErrNotAuthorized
+	}
+	args := TransactionArgs{
+		From:    address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainId: (*hexutil.Big)(s.chainId),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("transaction type %d not supported by external signer", tx.Type())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("external signer could not sign transaction: %w", err)
+	}
+	raw, err := decodeSignTransactionResult(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	// Unprotected transactions could be replayed on other chains
+	if !signedTx.Protected() {
+		return nil, fmt.Errorf("%w: transaction is not replay protected", ErrInvalidTransaction)
+	}
+	sender, err := types.Sender(s.signer, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if sender != address {
+		return nil, fmt.Errorf("%w: signed by %s instead of %s", ErrInvalidTransaction, sender.Hex(), address.Hex())
+	}
+	if signedTx.Type() != tx.Type() || s.signer.Hash(signedTx) != s.signer.Hash(tx) {
+		return nil, fmt.Errorf("%w: signed transaction differs from the requested one", ErrInvalidTransaction)
+	}
+	return signedTx, nil
+}
+
+// decodeSignTransactionResult returns the raw signed transaction, which Web3Signer returns as is
+// and Clef and Geth return in the raw field of an object.
+func decodeSignTransactionResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+	var signed struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &signed); err != nil {
+		return nil, err
+	}
+	if len(signed.Raw) == 0 {
+		return nil, errors.New("empty signed transaction")
+	}
+	return signed.Raw, nil
+}
+
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
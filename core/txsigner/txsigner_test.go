@@ -0,0 +1,128 @@
+package txsigner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var testChainId = big.NewInt(31337)
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	privateKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatalf("could not create private key: %v", err)
+	}
+	return privateKey
+}
+
+func startSigner(t *testing.T, handler http.Handler) string {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newExternalSigner(t *testing.T, url string, address common.Address) *ExternalSigner {
+	externalSigner, err := NewExternalSigner(context.Background(), url, address, testChainId)
+	if err != nil {
+		t.Fatalf("could not connect to external signer: %v", err)
+	}
+	t.Cleanup(externalSigner.Close)
+	return externalSigner
+}
+
+func TestExternalSignerSignsBumpedTransactions(t *testing.T) {
+	privateKey := newTestKey(t)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	localSigner, err := NewLocalSignerServer(privateKey, testChainId)
+	if err != nil {
+		t.Fatalf("could not create local signer: %v", err)
+	}
+	externalSigner := newExternalSigner(t, startSigner(t, localSigner), address)
+
+	// The fee bump loop resends the same transaction with the same nonce and a higher gas price
+	to := common.HexToAddress("0xc3e53F4d16Ae77Db1c982e75a937B9f60FE63690")
+	txOpts := externalSigner.GetTxOpts()
+	var previousHash common.Hash
+	for _, gasPrice := range []int64{1_000_000_000, 1_250_000_000} {
+		tx := types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(gasPrice), Gas: 100_000, To: &to, Data: []byte{0xca, 0xfe}})
+		signedTx, err := txOpts.Signer(txOpts.From, tx)
+		if err != nil {
+			t.Fatalf("could not sign transaction: %v", err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(testChainId), signedTx)
+		if err != nil || sender != address {
+			t.Errorf("expected transaction signed by %s, got %s (%v)", address, sender, err)
+		}
+		if signedTx.Nonce() != 7 || signedTx.GasPrice().Int64() != gasPrice || signedTx.ChainId().Cmp(testChainId) != 0 {
+			t.Errorf("signed transaction doesn't match the requested one")
+		}
+		if signedTx.Hash() == previousHash {
+			t.Errorf("expected bumped transaction to be a different transaction")
+		}
+		previousHash = signedTx.Hash()
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: testChainId, Nonce: 8, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2_000_000_000), Gas: 100_000, To: &to})
+	signedTx, err := externalSigner.SignTransaction(address, tx)
+	if err != nil {
+		t.Fatalf("could not sign dynamic fee transaction: %v", err)
+	}
+	if signedTx.Type() != types.DynamicFeeTxType || signedTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 {
+		t.Errorf("signed dynamic fee transaction doesn't match the requested one")
+	}
+}
+
+func TestExternalSignerRejectsUnknownAccount(t *testing.T) {
+	localSigner, err := NewLocalSignerServer(newTestKey(t), testChainId)
+	if err != nil {
+		t.Fatalf("could not create local signer: %v", err)
+	}
+	other := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	_, err = NewExternalSigner(context.Background(), startSigner(t, localSigner), other, testChainId)
+	if !errors.Is(err, ErrUnknownAccount) {
+		t.Errorf("expected account not held by the signer to be rejected, got %v", err)
+	}
+}
+
+// tamperingSigner signs a transaction with a higher gas price than the requested one
+type tamperingSigner struct {
+	*localSignerService
+}
+
+func (s *tamperingSigner) SignTransaction(args TransactionArgs) (hexutil.Bytes, error) {
+	args.GasPrice = (*hexutil.Big)(new(big.Int).Mul(args.GasPrice.ToInt(), big.NewInt(10)))
+	return s.localSignerService.SignTransaction(args)
+}
+
+func TestExternalSignerRejectsTamperedTransaction(t *testing.T) {
+	privateKey := newTestKey(t)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	server := rpc.NewServer()
+	err := server.RegisterName("eth", &tamperingSigner{&localSignerService{
+		privateKey: privateKey,
+		address:    address,
+		chainId:    testChainId,
+		signer:     types.LatestSignerForChainID(testChainId),
+	}})
+	if err != nil {
+		t.Fatalf("could not create tampering signer: %v", err)
+	}
+	externalSigner := newExternalSigner(t, startSigner(t, server), address)
+
+	to := common.HexToAddress("0xc3e53F4d16Ae77Db1c982e75a937B9f60FE63690")
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1_000_000_000), Gas: 100_000, To: &to})
+	if _, err := externalSigner.SignTransaction(address, tx); !errors.Is(err, ErrInvalidTransaction) {
+		t.Errorf("expected tampered transaction to be rejected, got %v", err)
+	}
+}
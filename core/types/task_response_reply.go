@@ -0,0 +1,56 @@
+package types
+
+// TaskResponseOutcome is what the aggregator did with a signed task response.
+type TaskResponseOutcome uint8
+
+const (
+	// The zero value, so a reply the aggregator didn't fill in is never taken as accepted
+	TaskResponseInternalError TaskResponseOutcome = iota
+	// The response was added to the aggregation of its task
+	TaskResponseAccepted
+	// A response of the operator was already counted for the task
+	TaskResponseDuplicate
+	// The aggregator doesn't know the task yet, the response can be sent again later
+	TaskResponseUnknownTask
+	// The task reached quorum and its aggregated response was sent without this one
+	TaskResponseQuorumReached
+	// The task expired before reaching quorum
+	TaskResponseTaskExpired
+	// The signature doesn't verify against the key the operator registered, or the operator is not registered
+	TaskResponseInvalidSignature
+)
+
+var taskResponseOutcomeNames = map[TaskResponseOutcome]string{
+	TaskResponseInternalError:    "internal_error",
+	TaskResponseAccepted:         "accepted",
+	TaskResponseDuplicate:        "duplicate",
+	TaskResponseUnknownTask:      "unknown_task",
+	TaskResponseQuorumReached:    "quorum_reached",
+	TaskResponseTaskExpired:      "task_expired",
+	TaskResponseInvalidSignature: "invalid_signature",
+}
+
+func (o TaskResponseOutcome) String() string {
+	if name, ok := taskResponseOutcomeNames[o]; ok {
+		return name
+	}
+	return taskResponseOutcomeNames[TaskResponseInternalError]
+}
+
+// ParseTaskResponseOutcome returns the outcome named name. Outcomes added by newer
+// aggregators are unknown to older operators, so they are handled as internal errors.
+func ParseTaskResponseOutcome(name string) TaskResponseOutcome {
+	for outcome, outcomeName := range taskResponseOutcomeNames {
+		if outcomeName == name {
+			return outcome
+		}
+	}
+	return TaskResponseInternalError
+}
+
+// SignedTaskResponseReply is the reply of the aggregator to a signed task response.
+type SignedTaskResponseReply struct {
+	Outcome TaskResponseOutcome
+	// Why the response was not accepted
+	Message string
+}
@@ -21,6 +21,12 @@ type Metrics struct {
 	aggregatorGasCostPaidForBatcherTotal   prometheus.Gauge
 	aggregatorNumTimesPaidForBatcher       prometheus.Counter
 	numBumpedGasPriceForAggregatedResponse prometheus.Counter
+	operatorVerificationQueueDepth         prometheus.Gauge
+	numOperatorCancelledVerifications      prometheus.Counter
+	aggregatorOperatorResponses            *prometheus.CounterVec
+	operatorTaskResponseOutcomes           *prometheus.CounterVec
+	operatorOutboxPendingResponses         *prometheus.GaugeVec
+	operatorAggregatorReachable            *prometheus.GaugeVec
 }
 
 const alignedNamespace = "aligned"
@@ -59,6 +65,36 @@ func NewMetrics(ipPortAddress string, reg prometheus.Registerer, logger logging.
 			Name:      "respond_to_task_gas_price_bumped",
 			Help:      "Number of times gas price was bumped while sending aggregated response",
 		}),
+		operatorVerificationQueueDepth: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_verification_queue_depth",
+			Help:      "Number of proofs waiting for a verification worker in the operator",
+		}),
+		numOperatorCancelledVerifications: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_cancelled_verifications",
+			Help:      "Number of proof verifications abandoned by the operator because the verdict of their batch was already final",
+		}),
+		aggregatorOperatorResponses: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "aggregator_operator_responses",
+			Help:      "Number of task responses received by the aggregator, by operator and by whether they were accepted or why they were rejected",
+		}, []string{"operator_id", "result"}),
+		operatorTaskResponseOutcomes: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_task_response_outcomes",
+			Help:      "Number of replies of the aggregators to the task responses sent by the operator, by aggregator and outcome",
+		}, []string{"aggregator", "outcome"}),
+		operatorOutboxPendingResponses: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_outbox_pending_responses",
+			Help:      "Number of signed task responses in the operator outbox waiting to be acknowledged, by aggregator",
+		}, []string{"aggregator"}),
+		operatorAggregatorReachable: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: alignedNamespace,
+			Name:      "operator_aggregator_reachable",
+			Help:      "Whether the last attempt of the operator to send a task response to the aggregator reached it",
+		}, []string{"aggregator"}),
 	}
 }
 
@@ -116,3 +152,31 @@ func (m *Metrics) AddAggregatorGasPaidForBatcher(value float64) {
 func (m *Metrics) IncBumpedGasPriceForAggregatedResponse() {
 	m.numBumpedGasPriceForAggregatedResponse.Inc()
 }
+
+func (m *Metrics) SetOperatorVerificationQueueDepth(value float64) {
+	m.operatorVerificationQueueDepth.Set(value)
+}
+
+func (m *Metrics) IncOperatorCancelledVerifications() {
+	m.numOperatorCancelledVerifications.Inc()
+}
+
+func (m *Metrics) IncAggregatorOperatorResponses(operatorId string, result string) {
+	m.aggregatorOperatorResponses.WithLabelValues(operatorId, result).Inc()
+}
+
+func (m *Metrics) IncOperatorTaskResponseOutcomes(aggregator string, outcome string) {
+	m.operatorTaskResponseOutcomes.WithLabelValues(aggregator, outcome).Inc()
+}
+
+func (m *Metrics) SetOperatorOutboxPendingResponses(aggregator string, value float64) {
+	m.operatorOutboxPendingResponses.WithLabelValues(aggregator).Set(value)
+}
+
+func (m *Metrics) SetOperatorAggregatorReachable(aggregator string, reachable bool) {
+	value := 0.0
+	if reachable {
+		value = 1
+	}
+	m.operatorAggregatorReachable.WithLabelValues(aggregator).Set(value)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/tasksigner"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+)
+
+var (
+	KeystoreFlag = &cli.StringFlag{
+		Name:     "keystore",
+		Usage:    "Path of the BLS keystore of the operator",
+		Required: true,
+	}
+	PasswordFileFlag = &cli.StringFlag{
+		Name:     "password-file",
+		Usage:    "Read the keystore password from this file",
+		EnvVars:  []string{"KEYSTORE_PASSWORD_FILE"},
+		Required: true,
+	}
+	ListenFlag = &cli.StringFlag{
+		Name:  "listen",
+		Usage: "Address to serve the signer API on",
+		Value: "127.0.0.1:9443",
+	}
+	TLSCertFlag = &cli.StringFlag{
+		Name:  "tls-cert",
+		Usage: "PEM certificate the signer is served with",
+	}
+	TLSKeyFlag = &cli.StringFlag{
+		Name:  "tls-key",
+		Usage: "PEM private key of the TLS certificate",
+	}
+	ClientCAFlag = &cli.StringFlag{
+		Name:  "client-ca",
+		Usage: "Only accept clients with a certificate signed by the CAs in this PEM file",
+	}
+	AllowedMessageTypesFlag = &cli.StringSliceFlag{
+		Name:  "allowed-message-types",
+		Usage: "Message types the signer signs, task_response and operator_version",
+		Value: cli.NewStringSlice(string(tasksigner.MessageTypeTaskResponse)),
+	}
+	InsecureHTTPFlag = &cli.BoolFlag{
+		Name:  "insecure-http",
+		Usage: "Serve plain http instead of TLS, only allowed on a loopback address",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+
+	app.Flags = []cli.Flag{KeystoreFlag, PasswordFileFlag, ListenFlag, TLSCertFlag, TLSKeyFlag, ClientCAFlag, AllowedMessageTypesFlag, InsecureHTTPFlag}
+	app.Name = "aligned-bls-signer"
+	app.Usage = "Aligned Layer BLS signer"
+	app.Description = "Reference signer service holding the BLS key of an operator configured with bls.remote_signer_url."
+	app.Action = signerMain
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatalln("Application failed.", "Message:", err)
+	}
+}
+
+func signerMain(ctx *cli.Context) error {
+	password, err := os.ReadFile(ctx.String(PasswordFileFlag.Name))
+	if err != nil {
+		return fmt.Errorf("could not read password file: %w", err)
+	}
+	keyPair, err := bls.ReadPrivateKeyFromFile(ctx.String(KeystoreFlag.Name), strings.TrimRight(string(password), "\r\n"))
+	if err != nil {
+		return fmt.Errorf("could not read BLS keystore: %w", err)
+	}
+
+	var allowedMessageTypes []tasksigner.MessageType
+	for _, messageType := range ctx.StringSlice(AllowedMessageTypesFlag.Name) {
+		switch tasksigner.MessageType(messageType) {
+		case tasksigner.MessageTypeTaskResponse, tasksigner.MessageTypeOperatorVersion:
+			allowedMessageTypes = append(allowedMessageTypes, tasksigner.MessageType(messageType))
+		default:
+			return fmt.Errorf("unknown message type %q", messageType)
+		}
+	}
+
+	logger := logging.NewTextSLogger(os.Stderr, nil)
+	server := &http.Server{
+		Addr:              ctx.String(ListenFlag.Name),
+		Handler:           tasksigner.NewServer(keyPair, allowedMessageTypes, logger).Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	if ctx.Bool(InsecureHTTPFlag.Name) {
+		host, _, err := net.SplitHostPort(server.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid listen address: %w", err)
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return errors.New("plain http is only allowed on a loopback address")
+		}
+		logger.Warn("Serving the signer over plain http", "address", server.Addr, "allowed message types", allowedMessageTypes)
+		return server.ListenAndServe()
+	}
+
+	if ctx.String(TLSCertFlag.Name) == "" || ctx.String(TLSKeyFlag.Name) == "" {
+		return errors.New("--tls-cert and --tls-key are required, or --insecure-http to serve on a loopback address")
+	}
+	server.TLSConfig, err = utils.NewServerTLSConfig(ctx.String(ClientCAFlag.Name))
+	if err != nil {
+		return err
+	}
+	if server.TLSConfig.ClientCAs == nil {
+		logger.Warn("No client CA configured, any client reaching the signer can get messages signed")
+	}
+
+	logger.Info("Serving the signer", "address", server.Addr, "allowed message types", allowedMessageTypes)
+	return server.ListenAndServeTLS(ctx.String(TLSCertFlag.Name), ctx.String(TLSKeyFlag.Name))
+}
@@ -0,0 +1,119 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var (
+	AuditLogFileFlag = &cli.StringFlag{
+		Name:  "audit-log",
+		Usage: "Path of the audit log, the `audit_log_filepath` of the config file if not set",
+	}
+	SinceFlag = &cli.TimestampFlag{
+		Name:   "since",
+		Usage:  "Only export the responses signed at or after this time",
+		Layout: time.RFC3339,
+	}
+)
+
+var AuditLogCommand = &cli.Command{
+	Name:        "audit-log",
+	Usage:       "Export and verify the log of the task responses signed by the operator",
+	Description: "CLI commands to inspect the hash-chained audit log kept when `audit_log_filepath` is set",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "export",
+			Usage:  "Print the signed task responses of the audit log",
+			Flags:  []cli.Flag{config.ConfigFileFlag, AuditLogFileFlag, SinceFlag, JsonFlag},
+			Action: exportAuditLogMain,
+		},
+		{
+			Name:   "verify",
+			Usage:  "Check the chain of the audit log and the signature of every entry against the operator BLS key",
+			Flags:  []cli.Flag{config.ConfigFileFlag, AuditLogFileFlag},
+			Action: verifyAuditLogMain,
+		},
+	},
+}
+
+func auditLogPath(ctx *cli.Context) (string, error) {
+	if path := ctx.String(AuditLogFileFlag.Name); path != "" {
+		return path, nil
+	}
+	var operatorConfig config.OperatorConfigFromYaml
+	if err := utils.ReadYamlConfig(ctx.String(config.ConfigFileFlag.Name), &operatorConfig); err != nil {
+		return "", err
+	}
+	if operatorConfig.Operator.AuditLogFilePath == "" {
+		return "", errors.New("config file field `audit_log_filepath` not provided, signed task responses are not being logged")
+	}
+	return operatorConfig.Operator.AuditLogFilePath, nil
+}
+
+func exportAuditLogMain(ctx *cli.Context) error {
+	path, err := auditLogPath(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := operator.ReadAuditLog(path)
+	if err != nil {
+		return err
+	}
+
+	// Entries keep their hashes, so they can still be matched against the chain of the full log
+	if since := ctx.Timestamp(SinceFlag.Name); since != nil {
+		filtered := make([]operator.AuditEntry, 0, len(entries))
+		for _, entry := range entries {
+			if !entry.Timestamp.Before(*since) {
+				filtered = append(filtered, entry)
+			}
+		}
+		entries = filtered
+	}
+	return printAuditEntries(os.Stdout, entries, ctx.Bool(JsonFlag.Name))
+}
+
+func printAuditEntries(out io.Writer, entries []operator.AuditEntry, asJson bool) error {
+	if asJson {
+		if entries == nil {
+			entries = []operator.AuditEntry{}
+		}
+		return printJson(out, entries)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQUENCE\tSIGNED AT\tBATCH IDENTIFIER HASH\tMERKLE ROOT\tSENDER\tREPORT DIGEST")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", entry.Sequence, entry.Timestamp.Format(time.RFC3339),
+			entry.BatchIdentifierHash.Hex(), entry.BatchMerkleRoot.Hex(), entry.SenderAddress.Hex(), entry.ReportDigest.Hex())
+	}
+	return w.Flush()
+}
+
+func verifyAuditLogMain(ctx *cli.Context) error {
+	path, err := auditLogPath(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := operator.ReadAuditLog(path)
+	if err != nil {
+		return err
+	}
+
+	blsConfig := config.NewBlsConfig(ctx.String(config.ConfigFileFlag.Name))
+	if err := operator.VerifyAuditLog(entries, blsConfig.Signer.PubKeyG2()); err != nil {
+		return err
+	}
+	fmt.Printf("Audit log verified: %d signed task responses\n", len(entries))
+	return nil
+}
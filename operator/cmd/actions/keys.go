@@ -0,0 +1,158 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var (
+	KeyTypeFlag = &cli.StringFlag{
+		Name:     "key-type",
+		Usage:    "Type of the key, bls or ecdsa",
+		Required: true,
+	}
+	KeystoreOutputFlag = &cli.StringFlag{
+		Name:     "output",
+		Usage:    "Path of the keystore to create, it must not exist",
+		Required: true,
+	}
+	KeystoreFlag = &cli.StringFlag{
+		Name:     "keystore",
+		Usage:    "Path of the keystore",
+		Required: true,
+	}
+	PrivateKeyFileFlag = &cli.StringFlag{
+		Name:     "private-key-file",
+		Usage:    "Read the private key to import from this file, BLS keys as decimal or 0x prefixed hex and ECDSA keys as hex",
+		Required: true,
+	}
+)
+
+var KeysCommand = &cli.Command{
+	Name:        "keys",
+	Usage:       "Manage the BLS and ECDSA keystores of the operator",
+	Description: "CLI commands to create keystores compatible with the EigenLayer CLI and show their public keys",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "generate",
+			Usage:  "Generate a new key in an encrypted keystore",
+			Flags:  append([]cli.Flag{KeyTypeFlag, KeystoreOutputFlag, JsonFlag}, passwordFlags...),
+			Action: generateKeyMain,
+		},
+		{
+			Name:   "import",
+			Usage:  "Import a private key into an encrypted keystore",
+			Flags:  append([]cli.Flag{KeyTypeFlag, KeystoreOutputFlag, PrivateKeyFileFlag, JsonFlag}, passwordFlags...),
+			Action: importKeyMain,
+		},
+		{
+			Name:   "export-public",
+			Usage:  "Show the public keys of a keystore",
+			Flags:  append([]cli.Flag{KeyTypeFlag, KeystoreFlag, JsonFlag}, passwordFlags...),
+			Action: exportPublicKeyMain,
+		},
+		{
+			Name:   "show-operator-id",
+			Usage:  "Show the operator ID derived from a BLS keystore",
+			Flags:  append([]cli.Flag{KeystoreFlag}, passwordFlags...),
+			Action: showOperatorIdMain,
+		},
+	},
+}
+
+func generateKeyMain(ctx *cli.Context) error {
+	keyType, err := operator.ParseKeyType(ctx.String(KeyTypeFlag.Name))
+	if err != nil {
+		return err
+	}
+	password, err := readPassword(ctx, "Keystore password", true)
+	if err != nil {
+		return err
+	}
+
+	info, err := operator.GenerateKeystore(keyType, ctx.String(KeystoreOutputFlag.Name), password)
+	if err != nil {
+		return err
+	}
+	if !ctx.Bool(JsonFlag.Name) {
+		fmt.Fprintln(os.Stderr, "Back up the keystore and its password, the key can't be recovered without them")
+	}
+	return printPublicKeyInfo(os.Stdout, info, ctx.Bool(JsonFlag.Name))
+}
+
+func importKeyMain(ctx *cli.Context) error {
+	keyType, err := operator.ParseKeyType(ctx.String(KeyTypeFlag.Name))
+	if err != nil {
+		return err
+	}
+	// The private key is read from a file so it doesn't end up in the shell history
+	privateKey, err := os.ReadFile(ctx.String(PrivateKeyFileFlag.Name))
+	if err != nil {
+		return fmt.Errorf("could not read private key file: %w", err)
+	}
+	password, err := readPassword(ctx, "Keystore password", true)
+	if err != nil {
+		return err
+	}
+
+	info, err := operator.ImportKeystore(keyType, string(privateKey), ctx.String(KeystoreOutputFlag.Name), password)
+	if err != nil {
+		return err
+	}
+	return printPublicKeyInfo(os.Stdout, info, ctx.Bool(JsonFlag.Name))
+}
+
+func exportPublicKeyMain(ctx *cli.Context) error {
+	keyType, err := operator.ParseKeyType(ctx.String(KeyTypeFlag.Name))
+	if err != nil {
+		return err
+	}
+	password, err := readPassword(ctx, "Keystore password", false)
+	if err != nil {
+		return err
+	}
+
+	info, err := operator.ReadPublicKeyInfo(keyType, ctx.String(KeystoreFlag.Name), password)
+	if err != nil {
+		return err
+	}
+	return printPublicKeyInfo(os.Stdout, info, ctx.Bool(JsonFlag.Name))
+}
+
+func showOperatorIdMain(ctx *cli.Context) error {
+	password, err := readPassword(ctx, "BLS keystore password", false)
+	if err != nil {
+		return err
+	}
+
+	info, err := operator.ReadPublicKeyInfo(operator.BlsKeyType, ctx.String(KeystoreFlag.Name), password)
+	if err != nil {
+		return err
+	}
+	fmt.Println(info.OperatorId)
+	return nil
+}
+
+func printPublicKeyInfo(out io.Writer, info *operator.PublicKeyInfo, asJson bool) error {
+	if asJson {
+		return printJson(out, info)
+	}
+
+	fmt.Fprintf(out, "Key type:       %s\n", strings.ToUpper(string(info.KeyType)))
+	fmt.Fprintf(out, "Keystore:       %s\n", info.Keystore)
+	switch info.KeyType {
+	case operator.BlsKeyType:
+		fmt.Fprintf(out, "Public key G1:  %s\n", info.PublicKeyG1)
+		fmt.Fprintf(out, "Public key G2:  %s\n", info.PublicKeyG2)
+		fmt.Fprintf(out, "Operator ID:    %s\n", info.OperatorId)
+	case operator.EcdsaKeyType:
+		fmt.Fprintf(out, "Public key:     %s\n", info.PublicKey)
+		fmt.Fprintf(out, "Address:        %s\n", info.Address.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var (
+	DryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the transaction instead of sending it",
+	}
+	YesFlag = &cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "Send the transaction without asking for confirmation",
+	}
+	SocketFlag = &cli.StringFlag{
+		Name:     "socket",
+		Usage:    "New socket of the operator",
+		Required: true,
+	}
+	MetadataURIFlag = &cli.StringFlag{
+		Name:     "metadata-uri",
+		Usage:    "New metadata URI of the operator",
+		Required: true,
+	}
+)
+
+var lifecycleFlags = []cli.Flag{
+	config.ConfigFileFlag,
+	DryRunFlag,
+	YesFlag,
+}
+
+var DeregisterCommand = &cli.Command{
+	Name:        "deregister",
+	Usage:       "Deregister operator from Aligned Layer",
+	Description: "CLI command to deregister the operator from the quorums it registered in",
+	Flags:       lifecycleFlags,
+	Action:      deregisterOperatorMain,
+}
+
+var UpdateSocketCommand = &cli.Command{
+	Name:        "update-socket",
+	Usage:       "Update the socket of the operator in Aligned Layer",
+	Description: "CLI command to update the socket the operator registered with",
+	Flags:       append([]cli.Flag{SocketFlag}, lifecycleFlags...),
+	Action:      updateSocketMain,
+}
+
+var UpdateMetadataURICommand = &cli.Command{
+	Name:        "update-metadata-uri",
+	Usage:       "Update the metadata URI of the operator in EigenLayer",
+	Description: "CLI command to update the metadata URI of the operator",
+	Flags:       append([]cli.Flag{MetadataURIFlag}, lifecycleFlags...),
+	Action:      updateMetadataURIMain,
+}
+
+func deregisterOperatorMain(ctx *cli.Context) error {
+	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
+	// Same quorums the register command registers the operator in
+	quorumNumbers := eigentypes.QuorumNums{0}
+	return sendOperatorChange(ctx, operatorConfig.BaseConfig, func(operatorAddress common.Address) operator.OperatorChange {
+		return operator.DeregisterOperatorChange(operatorAddress, operatorConfig.BlsConfig.Signer.PubKeyG1(), quorumNumbers)
+	})
+}
+
+func updateSocketMain(ctx *cli.Context) error {
+	baseConfig := config.NewBaseConfig(ctx.String(config.ConfigFileFlag.Name))
+	return sendOperatorChange(ctx, baseConfig, func(operatorAddress common.Address) operator.OperatorChange {
+		return operator.UpdateSocketChange(operatorAddress, ctx.String(SocketFlag.Name))
+	})
+}
+
+func updateMetadataURIMain(ctx *cli.Context) error {
+	baseConfig := config.NewBaseConfig(ctx.String(config.ConfigFileFlag.Name))
+	return sendOperatorChange(ctx, baseConfig, func(operatorAddress common.Address) operator.OperatorChange {
+		return operator.UpdateMetadataURIChange(operatorAddress, ctx.String(MetadataURIFlag.Name))
+	})
+}
+
+// sendOperatorChange sends the change of the operator the ECDSA key belongs to, after asking for
+// confirmation unless --yes is set. With --dry-run, the transaction is built and printed but not sent.
+func sendOperatorChange(ctx *cli.Context, baseConfig *config.BaseConfig, newChange func(operatorAddress common.Address) operator.OperatorChange) error {
+	ecdsaConfig := config.NewEcdsaConfig(ctx.String(config.ConfigFileFlag.Name), baseConfig.ChainId)
+	operatorAddress := crypto.PubkeyToAddress(ecdsaConfig.PrivateKey.PublicKey)
+
+	options := operator.OperatorChangeOptions{
+		AssumeYes: ctx.Bool(YesFlag.Name),
+		In:        os.Stdin,
+		Out:       os.Stdout,
+	}
+	var writer *chainio.AvsWriter
+	var err error
+	if ctx.Bool(DryRunFlag.Name) {
+		options.DryRun = chainio.NewDryRunTxManager(operatorAddress)
+		writer, err = chainio.NewAvsWriterFromConfigWithTxManager(baseConfig, ecdsaConfig, nil, options.DryRun)
+	} else {
+		writer, err = chainio.NewAvsWriterFromConfig(baseConfig, ecdsaConfig, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("could not create AVS writer: %w", err)
+	}
+
+	_, err = operator.SendOperatorChange(context.Background(), writer, newChange(operatorAddress), options)
+	if errors.Is(err, operator.ErrNotConfirmed) {
+		fmt.Println("Aborted, no transaction was sent")
+		return nil
+	}
+	return err
+}
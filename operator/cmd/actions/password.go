@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	PasswordFileFlag = &cli.StringFlag{
+		Name:    "password-file",
+		Usage:   "Read the keystore password from the first line of this file",
+		EnvVars: []string{"KEYSTORE_PASSWORD_FILE"},
+	}
+	PasswordStdinFlag = &cli.BoolFlag{
+		Name:  "password-stdin",
+		Usage: "Read the keystore password from the first line of stdin",
+	}
+)
+
+var passwordFlags = []cli.Flag{
+	PasswordFileFlag,
+	PasswordStdinFlag,
+}
+
+// readPassword reads the keystore password from --password-file or --password-stdin, so keys can be
+// managed by scripts, or else asks for it on the terminal. New passwords are asked for twice.
+func readPassword(ctx *cli.Context, prompt string, newPassword bool) (string, error) {
+	if path := ctx.String(PasswordFileFlag.Name); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("could not read password file: %w", err)
+		}
+		defer file.Close()
+		return readPasswordLine(bufio.NewReader(file))
+	}
+	if ctx.Bool(PasswordStdinFlag.Name) {
+		return readPasswordLine(bufio.NewReader(os.Stdin))
+	}
+
+	password, err := readTerminalPassword(prompt)
+	if errors.Is(err, errNotATerminal) {
+		return "", fmt.Errorf("%w, use --%s or --%s to pass the password", err, PasswordFileFlag.Name, PasswordStdinFlag.Name)
+	}
+	if err != nil || !newPassword {
+		return password, err
+	}
+
+	repeated, err := readTerminalPassword("Repeat " + strings.ToLower(prompt[:1]) + prompt[1:])
+	if err != nil {
+		return "", err
+	}
+	if repeated != password {
+		return "", errors.New("passwords do not match")
+	}
+	return password, nil
+}
+
+// readPasswordLine reads the first line of reader, without its line ending.
+func readPasswordLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
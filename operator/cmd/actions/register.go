@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"errors"
 	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
 	"time"
 
@@ -26,6 +27,11 @@ func registerOperatorMain(ctx *cli.Context) error {
 	operatorConfig := config.NewOperatorConfig(ctx.String(config.ConfigFileFlag.Name))
 	ecdsaConfig := config.NewEcdsaConfig(ctx.String(config.ConfigFileFlag.Name), operatorConfig.BaseConfig.ChainId)
 
+	// Registering proves ownership of the BLS key with the key itself, a remote signer can't be used
+	if operatorConfig.BlsConfig.KeyPair == nil {
+		return errors.New("registering the operator requires the BLS private key store, set bls.private_key_store_path")
+	}
+
 	quorumNumbers := []byte{0}
 
 	// Generate salt and expiry
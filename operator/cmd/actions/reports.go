@@ -0,0 +1,127 @@
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var (
+	BatchFlag = &cli.StringFlag{
+		Name:  "batch",
+		Usage: "Merkle root or identifier hash of the batch to show the report of",
+	}
+	LimitFlag = &cli.IntFlag{
+		Name:  "limit",
+		Usage: "Number of recent batches to list",
+		Value: 20,
+	}
+	JsonFlag = &cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the output as JSON",
+	}
+)
+
+var reportsFlags = []cli.Flag{
+	config.ConfigFileFlag,
+	BatchFlag,
+	LimitFlag,
+	JsonFlag,
+}
+
+var ReportsCommand = &cli.Command{
+	Name:        "reports",
+	Description: "CLI command to inspect the verification reports of recent batches",
+	Flags:       reportsFlags,
+	Action:      reportsMain,
+}
+
+func reportsMain(ctx *cli.Context) error {
+	var operatorConfig config.OperatorConfigFromYaml
+	if err := utils.ReadYamlConfig(ctx.String(config.ConfigFileFlag.Name), &operatorConfig); err != nil {
+		return err
+	}
+	reportsDir := operatorConfig.Operator.VerificationReportsDir
+	if reportsDir == "" {
+		return errors.New("config file field `verification_reports_dir` not provided, reports are not being stored")
+	}
+
+	reports, err := operator.ReadReports(reportsDir)
+	if err != nil {
+		return err
+	}
+
+	if batch := ctx.String(BatchFlag.Name); batch != "" {
+		for i := len(reports) - 1; i >= 0; i-- {
+			if reports[i].Matches(batch) {
+				return printVerificationReport(os.Stdout, reports[i], ctx.Bool(JsonFlag.Name))
+			}
+		}
+		return fmt.Errorf("no verification report found for batch %s", batch)
+	}
+
+	// Most recent first
+	var recent []*operator.BatchVerificationReport
+	for i := len(reports) - 1; i >= 0 && len(recent) < ctx.Int(LimitFlag.Name); i-- {
+		recent = append(recent, reports[i])
+	}
+	return printVerificationReportList(os.Stdout, recent, ctx.Bool(JsonFlag.Name))
+}
+
+func printVerificationReportList(out io.Writer, reports []*operator.BatchVerificationReport, asJson bool) error {
+	if asJson {
+		return printJson(out, reports)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RECEIVED\tBLOCK\tMERKLE ROOT\tSENDER\tPROOFS\tVERIFIED\tERROR")
+	for _, report := range reports {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%t\t%s\n",
+			report.ReceivedAt.Format(time.RFC3339), report.BlockNumber, report.BatchMerkleRoot,
+			report.SenderAddress, len(report.Proofs), report.Verified, report.ErrorCategory)
+	}
+	return w.Flush()
+}
+
+// printVerificationReport prints the summary of the batch followed by one row per proof.
+func printVerificationReport(out io.Writer, report *operator.BatchVerificationReport, asJson bool) error {
+	if asJson {
+		return printJson(out, report)
+	}
+
+	fmt.Fprintf(out, "Batch merkle root:      %s\n", report.BatchMerkleRoot)
+	fmt.Fprintf(out, "Sender address:         %s\n", report.SenderAddress)
+	fmt.Fprintf(out, "Batch identifier hash:  %s\n", report.BatchIdentifierHash)
+	fmt.Fprintf(out, "Block number:           %d\n", report.BlockNumber)
+	fmt.Fprintf(out, "Received at:            %s\n", report.ReceivedAt.Format(time.RFC3339))
+	fmt.Fprintf(out, "Download duration:      %s\n", report.DownloadDuration)
+	fmt.Fprintf(out, "Verification duration:  %s\n", report.VerificationDuration)
+	fmt.Fprintf(out, "Verified:               %t\n", report.Verified)
+	if !report.Verified {
+		fmt.Fprintf(out, "Error:                  %s (%s)\n", report.Error, report.ErrorCategory)
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tPROVING SYSTEM\tVERIFIED\tVERIFIER\tDURATION\tERROR CATEGORY\tERROR")
+	for _, proof := range report.Proofs {
+		fmt.Fprintf(w, "%d\t%s\t%t\t%s\t%s\t%s\t%s\n", proof.Index, proof.ProvingSystem, proof.Verified,
+			proof.VerifierVersion, proof.Duration, proof.ErrorCategory, proof.Error)
+	}
+	return w.Flush()
+}
+
+func printJson(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
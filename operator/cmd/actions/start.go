@@ -3,6 +3,9 @@ package actions
 import (
 	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/urfave/cli/v2"
 	"github.com/yetanotherco/aligned_layer/core/config"
@@ -39,13 +42,17 @@ func operatorMain(ctx *cli.Context) error {
 		return err
 	}
 
+	// The operator drains its in-flight batches before exiting when interrupted or terminated
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	operator.Logger.Info("Operator starting...")
-	err = operator.Start(context.Background())
+	err = operator.Start(signalCtx)
 	if err != nil {
 		return err
 	}
 
-	log.Println("Operator started")
+	log.Println("Operator stopped")
 
 	return nil
 }
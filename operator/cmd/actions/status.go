@@ -0,0 +1,122 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+var (
+	UnrespondedTasksBlocksFlag = &cli.Uint64Flag{
+		Name:  "blocks",
+		Usage: "Number of blocks before the chain head to look for unresponded tasks in",
+		Value: chainio.BlockInterval,
+	}
+)
+
+var statusFlags = []cli.Flag{
+	config.ConfigFileFlag,
+	UnrespondedTasksBlocksFlag,
+	JsonFlag,
+}
+
+var StatusCommand = &cli.Command{
+	Name:        "status",
+	Usage:       "Show the on-chain and local state of the operator",
+	Description: "CLI command to check the registration, stake, verifiers, progress and aggregator connectivity of the operator",
+	Flags:       statusFlags,
+	Action:      statusMain,
+}
+
+func statusMain(ctx *cli.Context) error {
+	configFile := ctx.String(config.ConfigFileFlag.Name)
+	baseConfig := config.NewBaseConfig(configFile)
+	var operatorConfig config.OperatorConfigFromYaml
+	if err := utils.ReadYamlConfig(configFile, &operatorConfig); err != nil {
+		return err
+	}
+
+	avsReader, err := chainio.NewAvsReaderFromConfig(baseConfig)
+	if err != nil {
+		return fmt.Errorf("could not create AVS reader: %w", err)
+	}
+
+	// The status is of the first aggregator responses are sent to
+	aggregatorEndpoints := operator.AggregatorEndpoints(operatorConfig.Operator.AggregatorEndpoints,
+		operatorConfig.Operator.AggregatorRpcUrl, operatorConfig.Operator.AggregatorServerIpPortAddress)
+	status := operator.ReadOperatorStatus(context.Background(), avsReader, operator.OperatorStatusConfig{
+		Address:                operatorConfig.Operator.Address,
+		AggregatorAddress:      operator.AggregatorEndpointAddress(aggregatorEndpoints[0]),
+		BatchJournalPath:       operator.BatchJournalPath(operatorConfig.Operator.BatchJournalFilePath, operatorConfig.Operator.LastProcessedBatchFilePath),
+		UnrespondedTasksBlocks: ctx.Uint64(UnrespondedTasksBlocksFlag.Name),
+	})
+	return printOperatorStatus(os.Stdout, status, ctx.Bool(JsonFlag.Name))
+}
+
+func printOperatorStatus(out io.Writer, status *operator.OperatorStatus, asJson bool) error {
+	if asJson {
+		return printJson(out, status)
+	}
+
+	fmt.Fprintf(out, "Operator address:       %s\n", status.Address)
+	fmt.Fprintf(out, "Registered:             %t\n", status.Registered)
+	if status.OperatorId != "" {
+		fmt.Fprintf(out, "Operator ID:            %s\n", status.OperatorId)
+	}
+	for _, stake := range status.StakePerQuorum {
+		fmt.Fprintf(out, "Stake in quorum %-3d     %s\n", stake.Quorum, stake.Stake)
+	}
+	disabledVerifiers := "none"
+	if len(status.DisabledVerifiers) > 0 {
+		disabledVerifiers = strings.Join(status.DisabledVerifiers, ", ")
+	}
+	fmt.Fprintf(out, "Disabled verifiers:     %s (bitmap %s)\n", disabledVerifiers, status.DisabledVerifiersBitmap)
+	fmt.Fprintf(out, "Chain head:             %d\n", status.ChainHead)
+	if status.LastProcessedBlock == 0 {
+		fmt.Fprintf(out, "Last processed block:   none, no batch was ever seen\n")
+	} else {
+		fmt.Fprintf(out, "Last processed block:   %d (%d blocks behind head)\n", status.LastProcessedBlock, status.BlocksBehindHead)
+	}
+	fmt.Fprintf(out, "Pending batches:        %d\n", status.PendingBatches)
+	if status.Aggregator.Reachable {
+		fmt.Fprintf(out, "Aggregator:             %s reachable\n", status.Aggregator.Address)
+	} else {
+		fmt.Fprintf(out, "Aggregator:             %s not reachable: %s\n", status.Aggregator.Address, status.Aggregator.Error)
+	}
+	fmt.Fprintln(out)
+
+	fmt.Fprintf(out, "Unresponded tasks since block %d: %d\n", status.UnrespondedTasksFrom, len(status.UnrespondedTasks))
+	if len(status.UnrespondedTasks) > 0 {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "BLOCK\tTASK CREATED BLOCK\tMERKLE ROOT\tSENDER\tEVENT\tLOCAL STAGE")
+		for _, task := range status.UnrespondedTasks {
+			localStage := string(task.LocalStage)
+			if localStage == "" {
+				localStage = "not seen"
+			}
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\tV%d\t%s\n", task.BlockNumber, task.TaskCreatedBlock,
+				task.BatchMerkleRoot, task.SenderAddress, task.EventVersion, localStage)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(status.Errors) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Errors:")
+		for _, statusErr := range status.Errors {
+			fmt.Fprintf(out, "  %s\n", statusErr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package actions
+
+import "errors"
+
+var errNotATerminal = errors.New("interactive password input is not supported on this platform")
+
+func readTerminalPassword(prompt string) (string, error) {
+	return "", errNotATerminal
+}
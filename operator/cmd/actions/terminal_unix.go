@@ -0,0 +1,36 @@
+//go:build linux || darwin
+
+package actions
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+var errNotATerminal = errors.New("stdin is not a terminal")
+
+// readTerminalPassword asks for a password on the terminal on stdin, and reads it without echoing it.
+func readTerminalPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return "", errNotATerminal
+	}
+
+	noEcho := *termios
+	noEcho.Lflag &^= unix.ECHO
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &noEcho); err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = unix.IoctlSetTermios(fd, ioctlSetTermios, termios)
+		fmt.Fprintln(os.Stderr)
+	}()
+
+	fmt.Fprint(os.Stderr, prompt+": ")
+	return readPasswordLine(bufio.NewReader(os.Stdin))
+}
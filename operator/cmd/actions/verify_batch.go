@@ -0,0 +1,120 @@
+package actions
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/utils"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
+)
+
+const (
+	// Exit codes of verify-batch, so scripts can tell a rejected batch from one that could not be checked
+	batchRejectedExitCode   = 1
+	batchUnverifiedExitCode = 2
+)
+
+var (
+	BatchSourceFlag = &cli.StringFlag{
+		Name:     "source",
+		Required: true,
+		Usage:    "Path or http(s) URL of the batch to verify",
+	}
+	MerkleRootFlag = &cli.StringFlag{
+		Name:     "merkle-root",
+		Required: true,
+		Usage:    "Expected merkle root of the batch, as hex",
+	}
+	SenderAddressFlag = &cli.StringFlag{
+		Name:  "sender",
+		Usage: "Address of the batcher that sent the batch, only used to compute the batch identifier hash",
+		Value: ethcommon.Address{}.Hex(),
+	}
+	OptionalConfigFileFlag = &cli.StringFlag{
+		Name:  "config",
+		Usage: "Operator config `FILE` to take the batch download and verification settings from",
+	}
+	VerboseFlag = &cli.BoolFlag{
+		Name:  "verbose",
+		Usage: "Log the progress of the verification to stderr",
+	}
+)
+
+var verifyBatchFlags = []cli.Flag{
+	BatchSourceFlag,
+	MerkleRootFlag,
+	SenderAddressFlag,
+	OptionalConfigFileFlag,
+	JsonFlag,
+	VerboseFlag,
+}
+
+var VerifyBatchCommand = &cli.Command{
+	Name:        "verify-batch",
+	Description: "CLI command to check whether the operator would sign a batch, without reading the chain or signing anything. Exits with 1 if the batch is rejected and 2 if it could not be verified",
+	Flags:       verifyBatchFlags,
+	Action:      verifyBatchMain,
+}
+
+func verifyBatchMain(ctx *cli.Context) error {
+	var merkleRoot [32]byte
+	decodedMerkleRoot, err := hex.DecodeString(strings.TrimPrefix(ctx.String(MerkleRootFlag.Name), "0x"))
+	if err != nil || len(decodedMerkleRoot) != len(merkleRoot) {
+		return fmt.Errorf("invalid merkle root %q", ctx.String(MerkleRootFlag.Name))
+	}
+	copy(merkleRoot[:], decodedMerkleRoot)
+
+	sender := ctx.String(SenderAddressFlag.Name)
+	if !ethcommon.IsHexAddress(sender) {
+		return fmt.Errorf("invalid sender address %q", sender)
+	}
+
+	verifierConfig := operator.OfflineBatchVerifierConfig{}
+	if configFile := ctx.String(OptionalConfigFileFlag.Name); configFile != "" {
+		var operatorConfig config.OperatorConfigFromYaml
+		if err := utils.ReadYamlConfig(configFile, &operatorConfig); err != nil {
+			return err
+		}
+		verifierConfig = operator.OfflineBatchVerifierConfig{
+			MaxBatchSize:                               operatorConfig.Operator.MaxBatchSize,
+			MaxConcurrentVerifications:                 operatorConfig.Operator.MaxConcurrentVerifications,
+			MaxConcurrentVerificationsPerProvingSystem: operatorConfig.Operator.MaxConcurrentVerificationsPerProvingSystem,
+			BatchMirrors:                               operatorConfig.Operator.BatchMirrors,
+			BatchUrlRewrites:                           operatorConfig.Operator.BatchUrlRewrites,
+			BatchDownloadHedgeDelay:                    operatorConfig.Operator.BatchDownloadHedgeDelay,
+		}
+	}
+
+	logLevel := slog.LevelWarn
+	if ctx.Bool(VerboseFlag.Name) {
+		logLevel = slog.LevelInfo
+	}
+	logger := logging.NewTextSLogger(os.Stderr, &logging.SLoggerOptions{Level: logLevel})
+
+	verifier, err := operator.NewOfflineBatchVerifier(verifierConfig, logger)
+	if err != nil {
+		return fmt.Errorf("could not create batch verifier: %w", err)
+	}
+
+	report, verifyErr := verifier.Verify(context.Background(), ctx.String(BatchSourceFlag.Name), merkleRoot, ethcommon.HexToAddress(sender))
+	if err := printVerificationReport(os.Stdout, report, ctx.Bool(JsonFlag.Name)); err != nil {
+		return err
+	}
+
+	switch {
+	case verifyErr == nil:
+		return nil
+	case report.Rejected():
+		return cli.Exit(fmt.Sprintf("Batch rejected: %v", verifyErr), batchRejectedExitCode)
+	default:
+		return cli.Exit(fmt.Sprintf("Batch could not be verified: %v", verifyErr), batchUnverifiedExitCode)
+	}
+}
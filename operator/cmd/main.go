@@ -18,8 +18,16 @@ func main() {
 		Name: "Aligned Layer Node Operator",
 		Commands: []*cli.Command{
 			actions.RegisterCommand,
+			actions.DeregisterCommand,
+			actions.UpdateSocketCommand,
+			actions.UpdateMetadataURICommand,
 			actions.StartCommand,
 			actions.DepositIntoStrategyCommand,
+			actions.KeysCommand,
+			actions.ReportsCommand,
+			actions.VerifyBatchCommand,
+			actions.StatusCommand,
+			actions.AuditLogCommand,
 		},
 		Version: Version,
 	}
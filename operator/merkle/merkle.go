@@ -0,0 +1,154 @@
+// Package merkle computes the merkle trees of Aligned batches natively in Go.
+// Trees match the ones built by the batcher with lambdaworks: nodes are keccak256 hashes,
+// a parent is the hash of its two children concatenated, and the leaves are padded to a
+// power of two by repeating the last one.
+// Inclusion proofs match the ones checked on chain by VerifyBatchInclusion of the service
+// manager, through Merkle.verifyInclusionKeccak.
+package merkle
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrEmptyTree         = errors.New("merkle tree has no leaves")
+	ErrLeafIndex         = errors.New("leaf index out of range")
+	ErrInvalidProofBytes = errors.New("merkle proof length is not a multiple of 32")
+)
+
+// Tree is a merkle tree of batch leaves.
+type Tree struct {
+	leaves int
+	// levels holds the nodes of every level of the tree, from the padded leaves up to the root
+	levels [][][32]byte
+}
+
+// NewTree builds the merkle tree of the leaves.
+func NewTree(leaves [][32]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+
+	level := padLeaves(leaves)
+	levels := [][][32]byte{level}
+	for len(level) > 1 {
+		parents := make([][32]byte, len(level)/2)
+		for i := range parents {
+			parents[i] = HashParent(level[2*i], level[2*i+1])
+		}
+		level = parents
+		levels = append(levels, level)
+	}
+	return &Tree{leaves: len(leaves), levels: levels}, nil
+}
+
+// Root returns the merkle root of the tree.
+func (t *Tree) Root() [32]byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Leaves returns the number of leaves the tree was built from, without the padding.
+func (t *Tree) Leaves() int {
+	return t.leaves
+}
+
+// Leaf returns the leaf at position index.
+func (t *Tree) Leaf(index int) ([32]byte, error) {
+	if index < 0 || index >= t.leaves {
+		return [32]byte{}, fmt.Errorf("%w: %d of %d", ErrLeafIndex, index, t.leaves)
+	}
+	return t.levels[0][index], nil
+}
+
+// Proof returns the inclusion proof of the leaf at position index.
+func (t *Tree) Proof(index int) (Proof, error) {
+	if index < 0 || index >= t.leaves {
+		return nil, fmt.Errorf("%w: %d of %d", ErrLeafIndex, index, t.leaves)
+	}
+
+	proof := make(Proof, 0, len(t.levels)-1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		proof = append(proof, level[index^1])
+		index /= 2
+	}
+	return proof, nil
+}
+
+// Proof is the list of siblings of the nodes in the path from a leaf to the root, starting at the leaf.
+type Proof [][32]byte
+
+// Bytes returns the siblings concatenated, as the merkle proof expected by VerifyBatchInclusion.
+func (p Proof) Bytes() []byte {
+	encoded := make([]byte, 0, len(p)*32)
+	for _, sibling := range p {
+		encoded = append(encoded, sibling[:]...)
+	}
+	return encoded
+}
+
+// ParseProof decodes a proof encoded as its siblings concatenated.
+func ParseProof(encoded []byte) (Proof, error) {
+	if len(encoded)%32 != 0 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidProofBytes, len(encoded))
+	}
+	proof := make(Proof, len(encoded)/32)
+	for i := range proof {
+		copy(proof[i][:], encoded[32*i:])
+	}
+	return proof, nil
+}
+
+// Verify reports whether the proof shows leaf is at position index of the tree with the given root.
+// Like Merkle.verifyInclusionKeccak, at every level the node is hashed as the left child if
+// the index is even and as the right one otherwise, and the index is halved.
+// Note the contract rejects empty proofs, so the leaf of a batch of a single proof, which is
+// its root, verifies here but can't be proven on chain.
+func (p Proof) Verify(root [32]byte, leaf [32]byte, index uint64) bool {
+	node := leaf
+	for _, sibling := range p {
+		if index%2 == 0 {
+			node = HashParent(node, sibling)
+		} else {
+			node = HashParent(sibling, node)
+		}
+		index /= 2
+	}
+	return node == root
+}
+
+// HashParent returns the node whose children are left and right.
+func HashParent(left [32]byte, right [32]byte) [32]byte {
+	var parent [32]byte
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(left[:])
+	hasher.Write(right[:])
+	hasher.Sum(parent[:0])
+	return parent
+}
+
+// Root returns the merkle root of the leaves. The root of a single leaf is the leaf itself.
+// It panics if there are no leaves, since empty batches don't have a merkle root.
+func Root(leaves [][32]byte) [32]byte {
+	tree, err := NewTree(leaves)
+	if err != nil {
+		panic("merkle: " + err.Error())
+	}
+	return tree.Root()
+}
+
+// padLeaves returns a copy of leaves, completed up to a power of two by repeating the last one.
+func padLeaves(leaves [][32]byte) [][32]byte {
+	size := 1
+	for size < len(leaves) {
+		size *= 2
+	}
+	padded := make([][32]byte, size)
+	copy(padded, leaves)
+	for i := len(leaves); i < size; i++ {
+		padded[i] = leaves[len(leaves)-1]
+	}
+	return padded
+}
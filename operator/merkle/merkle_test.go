@@ -0,0 +1,73 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+func testLeaves(n int) [][32]byte {
+	leaves := make([][32]byte, n)
+	for i := range leaves {
+		hasher := sha3.NewLegacyKeccak256()
+		hasher.Write([]byte{byte(i)})
+		hasher.Sum(leaves[i][:0])
+	}
+	return leaves
+}
+
+func TestRoot(t *testing.T) {
+	leaves := testLeaves(3)
+	if root := Root(leaves[:1]); root != leaves[0] {
+		t.Errorf("expected the root of a single leaf to be the leaf, got %x", root)
+	}
+
+	// The third leaf is repeated to complete the tree
+	expected := HashParent(HashParent(leaves[0], leaves[1]), HashParent(leaves[2], leaves[2]))
+	if root := Root(leaves); root != expected {
+		t.Errorf("expected root %x, got %x", expected, root)
+	}
+
+	if _, err := NewTree(nil); !errors.Is(err, ErrEmptyTree) {
+		t.Errorf("expected empty tree error, got %v", err)
+	}
+}
+
+func TestProofs(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		leaves := testLeaves(n)
+		tree, err := NewTree(leaves)
+		if err != nil {
+			t.Fatalf("could not build tree of %d leaves: %v", n, err)
+		}
+
+		for i, leaf := range leaves {
+			proof, err := tree.Proof(i)
+			if err != nil {
+				t.Fatalf("could not get proof of leaf %d of %d: %v", i, n, err)
+			}
+			if !proof.Verify(tree.Root(), leaf, uint64(i)) {
+				t.Errorf("proof of leaf %d of %d does not verify", i, n)
+			}
+			// The last leaf may be repeated as its own sibling, which verifies at both positions
+			if n > 1 && proof[0] != leaf && proof.Verify(tree.Root(), leaf, uint64(i^1)) {
+				t.Errorf("proof of leaf %d of %d verifies at the wrong index", i, n)
+			}
+
+			parsed, err := ParseProof(proof.Bytes())
+			if err != nil || !bytes.Equal(parsed.Bytes(), proof.Bytes()) {
+				t.Errorf("proof of leaf %d of %d does not survive encoding: %v", i, n, err)
+			}
+		}
+
+		if _, err := tree.Proof(n); !errors.Is(err, ErrLeafIndex) {
+			t.Errorf("expected out of range proof to fail, got %v", err)
+		}
+	}
+
+	if _, err := ParseProof(make([]byte, 33)); !errors.Is(err, ErrInvalidProofBytes) {
+		t.Errorf("expected proof of 33 bytes to be rejected, got %v", err)
+	}
+}
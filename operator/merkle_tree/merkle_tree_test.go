@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	operator "github.com/yetanotherco/aligned_layer/operator/pkg"
 )
 
 const BatchFilePath = "lib/test_files/merkle_tree_batch.bin"
@@ -38,3 +41,46 @@ func TestVerifyMerkleTreeBatch(t *testing.T) {
 	}
 
 }
+
+// TestNativeMerkleTreeMatchesFFI checks the merkle root computed in Go for every prefix of the
+// test batch is the one the FFI verifies, and that the FFI rejects any other root.
+func TestNativeMerkleTreeMatchesFFI(t *testing.T) {
+	batchByteValue, err := os.ReadFile(BatchFilePath)
+	if err != nil {
+		t.Fatalf("Error reading batch file: %v", err)
+	}
+
+	// Entries are kept as they were encoded, so every sub-batch has the same encoding the batcher used
+	var entries []cbor.RawMessage
+	if err := cbor.Unmarshal(batchByteValue, &entries); err != nil {
+		t.Fatalf("Error decoding batch entries: %v", err)
+	}
+
+	for n := 1; n <= len(entries); n++ {
+		subBatch, err := cbor.Marshal(entries[:n])
+		if err != nil {
+			t.Fatalf("Error encoding sub-batch of %d entries: %v", n, err)
+		}
+
+		var verificationDataBatch []operator.VerificationData
+		if err := cbor.Unmarshal(subBatch, &verificationDataBatch); err != nil {
+			t.Fatalf("Error decoding sub-batch of %d entries: %v", n, err)
+		}
+		tree, err := operator.NewBatchMerkleTree(verificationDataBatch)
+		if err != nil {
+			t.Fatalf("Error building merkle tree of %d entries: %v", n, err)
+		}
+
+		verified, err := VerifyMerkleTreeBatch(subBatch, tree.Root())
+		if err != nil || !verified {
+			t.Errorf("FFI did not verify native merkle root of %d entries: %v", n, err)
+		}
+
+		wrongRoot := tree.Root()
+		wrongRoot[0] ^= 1
+		verified, err = VerifyMerkleTreeBatch(subBatch, wrongRoot)
+		if err != nil || verified {
+			t.Errorf("FFI verified a wrong merkle root of %d entries: %v", n, err)
+		}
+	}
+}
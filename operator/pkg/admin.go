@@ -0,0 +1,477 @@
+package operator
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ReprocessFrom is the step a batch is reprocessed from when forced through the admin API.
+type ReprocessFrom string
+
+const (
+	// The batch is downloaded again instead of read from the batch cache, then verified and signed
+	ReprocessFromDownload ReprocessFrom = "download"
+	// The batch is verified again and signed, reading it from the batch cache if it is there
+	ReprocessFromVerification ReprocessFrom = "verify"
+)
+
+var (
+	ErrBatchNotFound        = errors.New("batch not found")
+	ErrBatchInFlight        = errors.New("batch is already being handled")
+	ErrOperatorShuttingDown = errors.New("operator is shutting down")
+)
+
+// InFlightBatch is a batch the operator received and has not finished handling.
+type InFlightBatch struct {
+	BatchIdentifierHash string            `json:"batch_identifier_hash"`
+	BatchMerkleRoot     ethcommon.Hash    `json:"batch_merkle_root"`
+	SenderAddress       ethcommon.Address `json:"sender_address"`
+	BlockNumber         uint64            `json:"block_number"`
+	Stage               BatchStage        `json:"stage"`
+	// Set if the batch is being reprocessed through the admin API
+	Reprocess ReprocessFrom `json:"reprocess,omitempty"`
+	// Set if the batch is waiting for the operator to be resumed
+	Deferred bool      `json:"deferred,omitempty"`
+	Since    time.Time `json:"since"`
+
+	newBatch *chainio.NewBatch
+}
+
+func newInFlightBatch(newBatch *chainio.NewBatch, reprocess ReprocessFrom) *InFlightBatch {
+	return &InFlightBatch{
+		BatchIdentifierHash: BatchIdentifierHashHex(newBatch.BatchMerkleRoot, newBatch.SenderAddress),
+		BatchMerkleRoot:     newBatch.BatchMerkleRoot,
+		SenderAddress:       newBatch.SenderAddress,
+		BlockNumber:         newBatch.Raw.BlockNumber,
+		Reprocess:           reprocess,
+		Since:               time.Now(),
+		newBatch:            newBatch,
+	}
+}
+
+// trackInFlight registers the batch as in flight until the returned function is called.
+// It returns false if the batch is already in flight, in which case it must not be handled.
+func (o *Operator) trackInFlight(newBatch *chainio.NewBatch, reprocess ReprocessFrom) (func(), bool) {
+	inFlightBatch := newInFlightBatch(newBatch, reprocess)
+
+	o.inFlightMutex.Lock()
+	defer o.inFlightMutex.Unlock()
+	if _, ok := o.inFlightBatches[inFlightBatch.BatchIdentifierHash]; ok {
+		return nil, false
+	}
+	o.inFlightBatches[inFlightBatch.BatchIdentifierHash] = inFlightBatch
+	return func() {
+		o.inFlightMutex.Lock()
+		defer o.inFlightMutex.Unlock()
+		delete(o.inFlightBatches, inFlightBatch.BatchIdentifierHash)
+	}, true
+}
+
+// dispatchNewBatch handles the batch in the background, unless the operator is paused,
+// in which case the batch is journaled and deferred until the operator is resumed.
+func (o *Operator) dispatchNewBatch(newBatch *chainio.NewBatch) {
+	o.pauseMutex.Lock()
+	if !o.paused {
+		o.pauseMutex.Unlock()
+		o.goHandleBatch(func() { o.handleNewBatch(newBatch) })
+		return
+	}
+	deferredBatch := newInFlightBatch(newBatch, "")
+	deferredBatch.Deferred = true
+	o.deferredBatches = append(o.deferredBatches, deferredBatch)
+	o.pauseMutex.Unlock()
+
+	// Journaled so that it is resumed on the next start if the operator stops while paused
+	if _, err := o.batchJournal.RecordSeen(JournaledBatchFromNewBatch(newBatch)); err != nil {
+		o.Logger.Errorf("Could not journal batch %x: %v", newBatch.BatchMerkleRoot, err)
+	}
+	o.Logger.Infof("Operator is paused, deferring batch %x", newBatch.BatchMerkleRoot)
+}
+
+// Pause stops the operator from handling new batches, which are deferred until it is resumed.
+// The batches already being handled are not affected.
+func (o *Operator) Pause() {
+	o.pauseMutex.Lock()
+	defer o.pauseMutex.Unlock()
+	if !o.paused {
+		o.paused = true
+		o.Logger.Info("Operator paused, new batches will be deferred until it is resumed")
+	}
+}
+
+// Resume makes the operator handle new batches again, starting with the ones deferred while
+// it was paused, and returns how many of them there were.
+func (o *Operator) Resume() int {
+	o.pauseMutex.Lock()
+	deferredBatches := o.deferredBatches
+	o.deferredBatches = nil
+	wasPaused := o.paused
+	o.paused = false
+	o.pauseMutex.Unlock()
+
+	if wasPaused {
+		o.Logger.Infof("Operator resumed, handling %d deferred batches", len(deferredBatches))
+	}
+	for _, deferredBatch := range deferredBatches {
+		o.dispatchNewBatch(deferredBatch.newBatch)
+	}
+	return len(deferredBatches)
+}
+
+// Paused reports whether the operator is deferring new batches.
+func (o *Operator) Paused() bool {
+	o.pauseMutex.Lock()
+	defer o.pauseMutex.Unlock()
+	return o.paused
+}
+
+// InFlightBatches returns the batches being handled and the ones deferred while paused,
+// oldest first, along with the stage each of them reached.
+func (o *Operator) InFlightBatches() []InFlightBatch {
+	var batches []InFlightBatch
+	o.inFlightMutex.Lock()
+	for _, inFlightBatch := range o.inFlightBatches {
+		batches = append(batches, *inFlightBatch)
+	}
+	o.inFlightMutex.Unlock()
+
+	o.pauseMutex.Lock()
+	for _, deferredBatch := range o.deferredBatches {
+		batches = append(batches, *deferredBatch)
+	}
+	o.pauseMutex.Unlock()
+
+	for i := range batches {
+		batches[i].Stage, _ = o.batchJournal.Stage(batches[i].BatchIdentifierHash)
+	}
+	sort.Slice(batches, func(a, b int) bool {
+		return batches[a].Since.Before(batches[b].Since)
+	})
+	return batches
+}
+
+// ReprocessBatch forces the batch to be handled again from the given step, whatever stage it
+// reached, and returns once it is started. The batch is looked up in the batch journal and,
+// if it is not there and fromBlock is not zero, among the batches not responded since fromBlock.
+func (o *Operator) ReprocessBatch(batchMerkleRoot [32]byte, senderAddress ethcommon.Address, from ReprocessFrom, fromBlock uint64) error {
+	switch from {
+	case ReprocessFromDownload, ReprocessFromVerification:
+	default:
+		return fmt.Errorf("unknown reprocess step %q, expected %s or %s", from, ReprocessFromDownload, ReprocessFromVerification)
+	}
+
+	newBatch, err := o.findBatch(batchMerkleRoot, senderAddress, fromBlock)
+	if err != nil {
+		return err
+	}
+	if _, err := o.batchJournal.RecordSeen(JournaledBatchFromNewBatch(newBatch)); err != nil {
+		o.Logger.Errorf("Could not journal batch %x: %v", newBatch.BatchMerkleRoot, err)
+	}
+
+	untrack, tracked := o.trackInFlight(newBatch, from)
+	if !tracked {
+		return ErrBatchInFlight
+	}
+	started := o.goHandleBatch(func() {
+		defer untrack()
+		o.reprocessBatch(newBatch, from)
+	})
+	if !started {
+		untrack()
+		return ErrOperatorShuttingDown
+	}
+	return nil
+}
+
+func (o *Operator) findBatch(batchMerkleRoot [32]byte, senderAddress ethcommon.Address, fromBlock uint64) (*chainio.NewBatch, error) {
+	if journaledBatch, _, ok := o.batchJournal.Batch(BatchIdentifierHashHex(batchMerkleRoot, senderAddress)); ok {
+		return journaledBatch.ToNewBatch(), nil
+	}
+	if fromBlock == 0 {
+		return nil, fmt.Errorf("%w in the batch journal, a block to look for it on chain from is needed", ErrBatchNotFound)
+	}
+
+	newBatches, err := o.avsReader.GetNotRespondedTasksFrom(fromBlock)
+	if err != nil {
+		return nil, fmt.Errorf("could not get batches from block %d: %w", fromBlock, err)
+	}
+	for _, newBatch := range newBatches {
+		if newBatch.BatchMerkleRoot == batchMerkleRoot && newBatch.SenderAddress == senderAddress {
+			return newBatch, nil
+		}
+	}
+	return nil, fmt.Errorf("%w among the batches not responded since block %d", ErrBatchNotFound, fromBlock)
+}
+
+func (o *Operator) reprocessBatch(newBatch *chainio.NewBatch, from ReprocessFrom) {
+	o.Logger.Infof("Reprocessing batch %x from %s", newBatch.BatchMerkleRoot, from)
+	if from == ReprocessFromDownload && o.batchCache != nil {
+		o.batchCache.Remove(newBatch.BatchMerkleRoot)
+	}
+	o.verifyAndSendTaskResponse(newBatch)
+}
+
+// AdminServer serves the admin API of the operator, which only answers requests carrying the
+// admin token as `Authorization: Bearer <token>`. It is meant to be reached locally, so it
+// listens on a unix socket and/or a loopback address.
+type AdminServer struct {
+	operator *Operator
+	token    []byte
+	logLevel zap.AtomicLevel
+}
+
+// NewAdminServer creates the admin server of operator, reading its token from tokenFile.
+// logLevel is the level of the operator logger, changed through the API.
+func NewAdminServer(operator *Operator, tokenFile string, logLevel zap.AtomicLevel) (*AdminServer, error) {
+	if tokenFile == "" {
+		return nil, errors.New("`admin_token_file` is required to serve the admin API")
+	}
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read admin token: %w", err)
+	}
+	token = []byte(strings.TrimSpace(string(token)))
+	if len(token) == 0 {
+		return nil, fmt.Errorf("admin token file %s is empty", tokenFile)
+	}
+	return &AdminServer{operator: operator, token: token, logLevel: logLevel}, nil
+}
+
+type reprocessRequest struct {
+	BatchMerkleRoot string        `json:"batch_merkle_root"`
+	SenderAddress   string        `json:"sender_address"`
+	From            ReprocessFrom `json:"from"`
+	// Optional, only used if the batch is not in the batch journal
+	FromBlock uint64 `json:"from_block"`
+}
+
+type logLevelMessage struct {
+	Level string `json:"level"`
+}
+
+// Handler returns the handler of the admin API:
+//   - POST /pause and POST /resume stop and restart the handling of new batches
+//   - GET /batches lists the batches in flight
+//   - POST /batches/reprocess forces a batch to be downloaded or verified again, and signed if valid
+//   - GET and PUT /log-level read and change the log level
+func (s *AdminServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /pause", func(w http.ResponseWriter, r *http.Request) {
+		s.operator.Pause()
+		writeAdminResponse(w, http.StatusOK, map[string]interface{}{"paused": true})
+	})
+	mux.HandleFunc("POST /resume", func(w http.ResponseWriter, r *http.Request) {
+		resumed := s.operator.Resume()
+		writeAdminResponse(w, http.StatusOK, map[string]interface{}{"paused": false, "resumed_batches": resumed})
+	})
+	mux.HandleFunc("GET /batches", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminResponse(w, http.StatusOK, map[string]interface{}{
+			"paused":  s.operator.Paused(),
+			"batches": s.operator.InFlightBatches(),
+		})
+	})
+	mux.HandleFunc("POST /batches/reprocess", s.handleReprocess)
+	mux.HandleFunc("GET /log-level", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminResponse(w, http.StatusOK, logLevelMessage{Level: s.logLevel.Level().String()})
+	})
+	mux.HandleFunc("PUT /log-level", s.handleSetLogLevel)
+	return s.authenticated(mux)
+}
+
+func (s *AdminServer) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), s.token) != 1 {
+			writeAdminError(w, http.StatusUnauthorized, errors.New("missing or invalid admin token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *AdminServer) handleReprocess(w http.ResponseWriter, r *http.Request) {
+	var request reprocessRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	var batchMerkleRoot [32]byte
+	decodedMerkleRoot, err := hex.DecodeString(strings.TrimPrefix(request.BatchMerkleRoot, "0x"))
+	if err != nil || len(decodedMerkleRoot) != len(batchMerkleRoot) {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid batch merkle root %q", request.BatchMerkleRoot))
+		return
+	}
+	copy(batchMerkleRoot[:], decodedMerkleRoot)
+	if !ethcommon.IsHexAddress(request.SenderAddress) {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid sender address %q", request.SenderAddress))
+		return
+	}
+
+	err = s.operator.ReprocessBatch(batchMerkleRoot, ethcommon.HexToAddress(request.SenderAddress), request.From, request.FromBlock)
+	switch {
+	case err == nil:
+		writeAdminResponse(w, http.StatusAccepted, map[string]interface{}{
+			"batch_identifier_hash": BatchIdentifierHashHex(batchMerkleRoot, ethcommon.HexToAddress(request.SenderAddress)),
+			"reprocess":             request.From,
+		})
+	case errors.Is(err, ErrBatchNotFound):
+		writeAdminError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrBatchInFlight):
+		writeAdminError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrOperatorShuttingDown):
+		writeAdminError(w, http.StatusServiceUnavailable, err)
+	default:
+		writeAdminError(w, http.StatusBadRequest, err)
+	}
+}
+
+func (s *AdminServer) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var request logLevelMessage
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	level, err := zapcore.ParseLevel(request.Level)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.logLevel.SetLevel(level)
+	s.operator.Logger.Infof("Log level changed to %s through the admin API", level)
+	writeAdminResponse(w, http.StatusOK, logLevelMessage{Level: level.String()})
+}
+
+func writeAdminResponse(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	writeAdminResponse(w, status, map[string]string{"error": err.Error()})
+}
+
+// Start serves the admin API on the unix socket at socketPath and on the loopback address
+// ipPortAddress, skipping the empty ones, until ctx is done. A stale socket file left by a
+// previous run is replaced, and the new one is only accessible by the operator user.
+func (s *AdminServer) Start(ctx context.Context, socketPath string, ipPortAddress string) (<-chan error, error) {
+	var listeners []net.Listener
+	closeListeners := func() {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+	}
+
+	if socketPath != "" {
+		if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("could not remove stale admin socket: %w", err)
+		}
+		listener, err := listenPrivateUnixSocket(socketPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not listen on admin socket: %w", err)
+		}
+		listeners = append(listeners, listener)
+	}
+	if ipPortAddress != "" {
+		if err := checkLoopbackAddress(ipPortAddress); err != nil {
+			closeListeners()
+			return nil, err
+		}
+		listener, err := net.Listen("tcp", ipPortAddress)
+		if err != nil {
+			closeListeners()
+			return nil, fmt.Errorf("could not listen on admin address: %w", err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	errC := make(chan error, len(listeners))
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	for _, listener := range listeners {
+		s.operator.Logger.Infof("Serving admin API at %s", listener.Addr())
+		go func() {
+			err := server.Serve(listener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errC <- fmt.Errorf("admin server failed: %w", err)
+			}
+		}()
+	}
+	return errC, nil
+}
+
+// listenPrivateUnixSocket listens on a unix socket at socketPath only accessible by its owner.
+// The socket is created in a directory only accessible by its owner and moved to socketPath once
+// its permissions are restricted, so it is never reachable by other users in between.
+func listenPrivateUnixSocket(socketPath string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(socketPath), ".admin-socket-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	privatePath := filepath.Join(dir, "admin.sock")
+	listener, err := net.Listen("unix", privatePath)
+	if err != nil {
+		return nil, err
+	}
+	// The socket is removed from where it is moved to once the listener is closed
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(privatePath, 0o600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("could not restrict access to admin socket: %w", err)
+	}
+	if err := os.Rename(privatePath, socketPath); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &removeOnCloseListener{Listener: listener, path: socketPath}, nil
+}
+
+type removeOnCloseListener struct {
+	net.Listener
+	path string
+}
+
+func (l *removeOnCloseListener) Close() error {
+	err := l.Listener.Close()
+	os.Remove(l.path)
+	return err
+}
+
+// checkLoopbackAddress makes sure the admin API is not exposed beyond the operator host.
+func checkLoopbackAddress(ipPortAddress string) error {
+	host, _, err := net.SplitHostPort(ipPortAddress)
+	if err != nil {
+		return fmt.Errorf("invalid admin address %q: %w", ipPortAddress, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("admin address %q is not a loopback address", ipPortAddress)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const testAdminToken = "admin-token"
+
+func newTestAdminServer(t *testing.T, operator *Operator) *AdminServer {
+	tokenFile := filepath.Join(t.TempDir(), "admin.token")
+	if err := os.WriteFile(tokenFile, []byte(testAdminToken+"\n"), 0o600); err != nil {
+		t.Fatalf("could not write admin token: %v", err)
+	}
+	server, err := NewAdminServer(operator, tokenFile, zap.NewAtomicLevelAt(zapcore.InfoLevel))
+	if err != nil {
+		t.Fatalf("could not create admin server: %v", err)
+	}
+	return server
+}
+
+func adminRequest(t *testing.T, server *AdminServer, method, path, body string) (int, map[string]interface{}) {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+testAdminToken)
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s %s: could not decode response %q: %v", method, path, recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+func TestAdminServerRequiresToken(t *testing.T) {
+	server := newTestAdminServer(t, newTestOperator(t))
+
+	for _, authorization := range []string{"", "Bearer wrong-token", testAdminToken} {
+		request := httptest.NewRequest(http.MethodPost, "/pause", nil)
+		request.Header.Set("Authorization", authorization)
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("authorization %q: expected 401, got %d", authorization, recorder.Code)
+		}
+	}
+	if server.operator.Paused() {
+		t.Errorf("expected unauthenticated requests not to pause the operator")
+	}
+}
+
+func TestAdminPauseDefersNewBatches(t *testing.T) {
+	operator := newTestOperator(t)
+	server := newTestAdminServer(t, operator)
+
+	if code, _ := adminRequest(t, server, http.MethodPost, "/pause", ""); code != http.StatusOK {
+		t.Fatalf("could not pause operator: %d", code)
+	}
+	batch := journaledBatch(1, 10)
+	operator.dispatchNewBatch(batch.ToNewBatch())
+
+	_, response := adminRequest(t, server, http.MethodGet, "/batches", "")
+	batches, _ := response["batches"].([]interface{})
+	if response["paused"] != true || len(batches) != 1 {
+		t.Fatalf("expected a deferred batch in the paused operator, got %v", response)
+	}
+	if deferred := batches[0].(map[string]interface{}); deferred["deferred"] != true || deferred["stage"] != string(BatchSeen) {
+		t.Errorf("unexpected deferred batch %v", deferred)
+	}
+
+	// Already handled batches are skipped once resumed, so the test doesn't need a chain
+	operator.recordBatchStage(batch.BatchIdentifierHash(), BatchDelivered)
+	_, response = adminRequest(t, server, http.MethodPost, "/resume", "")
+	if response["resumed_batches"] != float64(1) {
+		t.Errorf("expected the deferred batch to be resumed, got %v", response)
+	}
+	if err := operator.Shutdown(0); err != nil {
+		t.Fatalf("could not shut down: %v", err)
+	}
+	if batches := operator.InFlightBatches(); len(batches) != 0 {
+		t.Errorf("expected no batch in flight after resuming, got %v", batches)
+	}
+}
+
+func TestAdminReprocessBatch(t *testing.T) {
+	operator := newTestOperator(t)
+	server := newTestAdminServer(t, operator)
+	batch := journaledBatch(1, 10)
+	operator.batchJournal.RecordSeen(batch)
+
+	untrack, _ := operator.trackInFlight(batch.ToNewBatch(), "")
+	body := `{"batch_merkle_root": "` + batch.BatchMerkleRoot.Hex() + `", "sender_address": "` + batch.SenderAddress.Hex() + `", "from": "verify"}`
+	if code, response := adminRequest(t, server, http.MethodPost, "/batches/reprocess", body); code != http.StatusConflict {
+		t.Errorf("expected batch in flight not to be reprocessed, got %d %v", code, response)
+	}
+	untrack()
+
+	missing := journaledBatch(2, 10)
+	body = `{"batch_merkle_root": "` + missing.BatchMerkleRoot.Hex() + `", "sender_address": "` + missing.SenderAddress.Hex() + `", "from": "verify"}`
+	if code, response := adminRequest(t, server, http.MethodPost, "/batches/reprocess", body); code != http.StatusNotFound {
+		t.Errorf("expected batch missing from the journal not to be found, got %d %v", code, response)
+	}
+
+	body = `{"batch_merkle_root": "` + batch.BatchMerkleRoot.Hex() + `", "sender_address": "` + batch.SenderAddress.Hex() + `", "from": "everything"}`
+	if code, response := adminRequest(t, server, http.MethodPost, "/batches/reprocess", body); code != http.StatusBadRequest {
+		t.Errorf("expected unknown reprocess step to be rejected, got %d %v", code, response)
+	}
+
+	// Responses are only signed for batches verified again
+	body = `{"batch_merkle_root": "` + batch.BatchMerkleRoot.Hex() + `", "sender_address": "` + batch.SenderAddress.Hex() + `", "from": "sign"}`
+	if code, response := adminRequest(t, server, http.MethodPost, "/batches/reprocess", body); code != http.StatusBadRequest {
+		t.Errorf("expected signing without verifying to be rejected, got %d %v", code, response)
+	}
+
+	operator.Shutdown(0)
+	body = `{"batch_merkle_root": "` + batch.BatchMerkleRoot.Hex() + `", "sender_address": "` + batch.SenderAddress.Hex() + `", "from": "download"}`
+	if code, response := adminRequest(t, server, http.MethodPost, "/batches/reprocess", body); code != http.StatusServiceUnavailable {
+		t.Errorf("expected no batch to be reprocessed after shutting down, got %d %v", code, response)
+	}
+}
+
+func TestAdminChangesLogLevel(t *testing.T) {
+	server := newTestAdminServer(t, newTestOperator(t))
+
+	if code, response := adminRequest(t, server, http.MethodPut, "/log-level", `{"level": "debug"}`); code != http.StatusOK || response["level"] != "debug" {
+		t.Fatalf("could not change log level: %d %v", code, response)
+	}
+	if level := server.logLevel.Level(); level != zapcore.DebugLevel {
+		t.Errorf("expected debug level, got %s", level)
+	}
+	if code, _ := adminRequest(t, server, http.MethodPut, "/log-level", `{"level": "verbose"}`); code != http.StatusBadRequest {
+		t.Errorf("expected unknown log level to be rejected, got %d", code)
+	}
+}
+
+func TestAdminServerOnlyListensLocally(t *testing.T) {
+	server := newTestAdminServer(t, newTestOperator(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := server.Start(ctx, "", "0.0.0.0:0"); err == nil {
+		t.Errorf("expected admin server not to listen on a public address")
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	if _, err := server.Start(ctx, socketPath, "127.0.0.1:0"); err != nil {
+		t.Fatalf("could not start admin server: %v", err)
+	}
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatalf("admin socket not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected admin socket to only be accessible by its owner, got %s", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(socketPath)); len(entries) != 1 {
+		t.Errorf("expected only the admin socket to be left in its directory, got %v", entries)
+	}
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("could not connect to admin socket: %v", err)
+	}
+	conn.Close()
+}
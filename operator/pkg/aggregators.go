@@ -0,0 +1,115 @@
+package operator
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/aggregatorrpc"
+)
+
+// aggregatorEndpoint is an aggregator the operator sends its signed task responses to. Each one has
+// its own outbox, so an aggregator that is down or behind doesn't hold back delivery to the others.
+type aggregatorEndpoint struct {
+	address string
+	client  AggregatorClient
+	outbox  *Outbox
+}
+
+// AggregatorEndpoints returns the aggregators the operator sends its responses to, given the
+// `aggregator_endpoints`, `aggregator_rpc_url` and `aggregator_rpc_server_ip_port_address` config fields.
+func AggregatorEndpoints(aggregatorEndpoints []string, aggregatorRpcUrl string, aggregatorServerIpPortAddress string) []string {
+	if len(aggregatorEndpoints) > 0 {
+		return aggregatorEndpoints
+	}
+	if aggregatorRpcUrl != "" {
+		return []string{aggregatorRpcUrl}
+	}
+	return []string{aggregatorServerIpPortAddress}
+}
+
+// AggregatorEndpointAddress returns the host and port the aggregator at endpoint is reached at.
+func AggregatorEndpointAddress(endpoint string) string {
+	if isJsonRpcEndpoint(endpoint) {
+		return AggregatorAddress(endpoint, "")
+	}
+	return endpoint
+}
+
+// Endpoints given as URLs are reached with the versioned JSON-RPC protocol, and host:port
+// addresses with the legacy one.
+func isJsonRpcEndpoint(endpoint string) bool {
+	return strings.Contains(endpoint, "://")
+}
+
+// newAggregatorClient returns a client of the aggregator at endpoint. JSON-RPC endpoints are reached
+// with the TLS settings and timeout of clientConfig.
+func newAggregatorClient(endpoint string, clientConfig aggregatorrpc.ClientConfig, logger logging.Logger) AggregatorClient {
+	if isJsonRpcEndpoint(endpoint) {
+		clientConfig.Url = endpoint
+		return NewAggregatorJsonRpcClient(clientConfig, logger)
+	}
+	return NewAggregatorRpcClient(endpoint, logger)
+}
+
+// AggregatorOutboxPath returns where the outbox of the responses to the aggregator at endpoint is kept,
+// a directory of the outbox directory named after the endpoint, so it doesn't change if endpoints are
+// added, removed or reordered.
+func AggregatorOutboxPath(outboxPath string, endpoint string) string {
+	name := strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, endpoint)
+	// Never the outbox directory itself or its parent
+	if strings.Trim(name, ".") == "" {
+		name = "_" + name
+	}
+	return filepath.Join(outboxPath, name)
+}
+
+// MigrateOutbox moves the responses kept in the outbox directory itself, where operators sending
+// responses to a single aggregator kept them, to the outbox of every aggregator.
+func MigrateOutbox(outboxPath string, outboxes []*Outbox) error {
+	legacy, err := OpenOutbox(outboxPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range legacy.Entries() {
+		for _, outbox := range outboxes {
+			if err := outbox.restore(entry); err != nil {
+				return err
+			}
+		}
+		if err := legacy.Remove(entry.BatchIdentifierHash()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectAggregators connects to every aggregator concurrently, returning how many could be reached.
+func connectAggregators(ctx context.Context, aggregators []*aggregatorEndpoint, logger logging.Logger) int {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	connected := 0
+	for _, aggregator := range aggregators {
+		wg.Add(1)
+		go func(aggregator *aggregatorEndpoint) {
+			defer wg.Done()
+			if err := aggregator.client.Connect(ctx); err != nil {
+				logger.Warn("Could not connect to aggregator, its signed responses are kept in the outbox until it is reachable",
+					"aggregator", aggregator.address, "err", err)
+				return
+			}
+			mutex.Lock()
+			connected++
+			mutex.Unlock()
+		}(aggregator)
+	}
+	wg.Wait()
+	return connected
+}
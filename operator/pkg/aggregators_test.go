@@ -0,0 +1,205 @@
+package operator
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+func TestAggregatorEndpoints(t *testing.T) {
+	endpoints := AggregatorEndpoints([]string{"aggregator:8090", "https://standby.aggregator:8091"}, "https://aggregator:8091", "aggregator:8090")
+	if len(endpoints) != 2 {
+		t.Errorf("expected the configured endpoints, got %v", endpoints)
+	}
+	if endpoints := AggregatorEndpoints(nil, "https://aggregator:8091", "aggregator:8090"); len(endpoints) != 1 || endpoints[0] != "https://aggregator:8091" {
+		t.Errorf("expected the versioned endpoint, got %v", endpoints)
+	}
+	if endpoints := AggregatorEndpoints(nil, "", "aggregator:8090"); len(endpoints) != 1 || endpoints[0] != "aggregator:8090" {
+		t.Errorf("expected the legacy endpoint, got %v", endpoints)
+	}
+
+	if address := AggregatorEndpointAddress("https://standby.aggregator"); address != "standby.aggregator:443" {
+		t.Errorf("expected address of the versioned endpoint, got %s", address)
+	}
+	if address := AggregatorEndpointAddress("aggregator:8090"); address != "aggregator:8090" {
+		t.Errorf("expected address of the legacy endpoint, got %s", address)
+	}
+}
+
+func TestAggregatorOutboxPath(t *testing.T) {
+	if path := AggregatorOutboxPath("operator.outbox", "https://standby.aggregator:8091"); path != filepath.Join("operator.outbox", "https___standby.aggregator_8091") {
+		t.Errorf("expected the outbox to be named after the endpoint, got %s", path)
+	}
+	for _, endpoint := range []string{"", ".."} {
+		if path := AggregatorOutboxPath("operator.outbox", endpoint); filepath.Dir(path) != "operator.outbox" {
+			t.Errorf("expected the outbox of %q to be a directory of the outbox, got %s", endpoint, path)
+		}
+	}
+}
+
+func TestMigrateOutbox(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	outboxPath := filepath.Join(t.TempDir(), "operator.outbox")
+	legacy, err := OpenOutbox(outboxPath)
+	if err != nil {
+		t.Fatalf("could not open outbox: %v", err)
+	}
+	legacy.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+	retried := legacy.Entries()[0]
+	retried.Attempts = 3
+	legacy.Update(retried)
+
+	var outboxes []*Outbox
+	for _, endpoint := range []string{"aggregator:8090", "https://standby.aggregator:8091"} {
+		outbox, err := OpenOutbox(AggregatorOutboxPath(outboxPath, endpoint))
+		if err != nil {
+			t.Fatalf("could not open outbox: %v", err)
+		}
+		outboxes = append(outboxes, outbox)
+	}
+	if err := MigrateOutbox(outboxPath, outboxes); err != nil {
+		t.Fatalf("could not migrate outbox: %v", err)
+	}
+
+	for _, outbox := range outboxes {
+		if entries := outbox.Entries(); len(entries) != 1 || entries[0].Attempts != 3 {
+			t.Errorf("expected the kept response to be moved to every aggregator, got %+v", entries)
+		}
+	}
+	reopened, err := OpenOutbox(outboxPath)
+	if err != nil {
+		t.Fatalf("could not reopen outbox: %v", err)
+	}
+	if reopened.Len() != 0 {
+		t.Errorf("expected the outbox directory to only hold the outboxes of the aggregators, got %d responses", reopened.Len())
+	}
+}
+
+// newTestAggregators adds an aggregator to the operator for each client, each with its own outbox.
+func newTestAggregators(t *testing.T, operator *Operator, clients ...AggregatorClient) {
+	outboxPath := filepath.Join(t.TempDir(), "operator.outbox")
+	for i, client := range clients {
+		address := string(rune('a'+i)) + ".aggregator:8090"
+		outbox, err := OpenOutbox(AggregatorOutboxPath(outboxPath, address))
+		if err != nil {
+			t.Fatalf("could not open outbox: %v", err)
+		}
+		operator.aggregators = append(operator.aggregators, &aggregatorEndpoint{address: address, client: client, outbox: outbox})
+	}
+}
+
+func TestResponsesAreDeliveredToEveryAggregator(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	operator := newTestOperator(t)
+	up := &testAggregatorClient{reply: types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted}}
+	down := &testAggregatorClient{err: errors.New("connection refused")}
+	newTestAggregators(t, operator, down, up)
+	batch := journaledBatch(1, 10)
+	operator.batchJournal.RecordSeen(batch)
+	for _, aggregator := range operator.aggregators {
+		aggregator.outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+	}
+
+	operator.startOutboxDelivery()
+	deadline := time.Now().Add(5 * time.Second)
+	for operator.aggregators[1].outbox.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	operator.cancelDelivery()
+	<-operator.outboxDelivered
+
+	// An aggregator that is down doesn't hold back delivery to the rest, and keeps its own copy
+	if stage, _ := operator.batchJournal.Stage(batch.BatchIdentifierHash()); stage != BatchDelivered {
+		t.Errorf("expected batch to be delivered once an aggregator accepted it, got %s", stage)
+	}
+	if !operator.aggregators[0].outbox.Contains(batch.BatchIdentifierHash()) {
+		t.Errorf("expected response to be kept for the aggregator that is down")
+	}
+}
+
+func TestShutdownDeliversResponsesSignedWhileDraining(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	operator := newTestOperator(t)
+	up := &testAggregatorClient{reply: types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted}}
+	newTestAggregators(t, operator, up)
+	batch := journaledBatch(1, 10)
+	operator.batchJournal.RecordSeen(batch)
+	operator.startOutboxDelivery()
+
+	// The response is only signed once the operator is already shutting down
+	release := make(chan struct{})
+	operator.goHandleBatch(func() {
+		<-release
+		operator.aggregators[0].outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+	})
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+
+	if err := operator.Shutdown(5 * time.Second); err != nil {
+		t.Fatalf("could not shut down: %v", err)
+	}
+	if stage, _ := operator.batchJournal.Stage(batch.BatchIdentifierHash()); stage != BatchDelivered {
+		t.Errorf("expected response signed while draining to be delivered, got %s", stage)
+	}
+	if length := operator.aggregators[0].outbox.Len(); length != 0 {
+		t.Errorf("expected outbox to be empty after shutting down, got %d responses", length)
+	}
+}
+
+func TestShutdownKeepsUndeliveredResponsesOnceTimedOut(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	operator := newTestOperator(t)
+	down := &testAggregatorClient{err: errors.New("connection refused")}
+	newTestAggregators(t, operator, down)
+	batch := journaledBatch(1, 10)
+	operator.batchJournal.RecordSeen(batch)
+	operator.aggregators[0].outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+	operator.startOutboxDelivery()
+
+	start := time.Now()
+	if err := operator.Shutdown(100 * time.Millisecond); err != nil {
+		t.Fatalf("could not shut down: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond+shutdownCancellationGracePeriod {
+		t.Errorf("expected shutdown to give up on delivery once timed out, took %s", elapsed)
+	}
+	if !operator.aggregators[0].outbox.Contains(batch.BatchIdentifierHash()) {
+		t.Errorf("expected undelivered response to stay in the outbox")
+	}
+}
+
+func TestBatchIsAbandonedOnceEveryAggregatorGivesUp(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	operator := newTestOperator(t)
+	newTestAggregators(t, operator, &testAggregatorClient{}, &testAggregatorClient{})
+	for i := byte(1); i <= 2; i++ {
+		operator.batchJournal.RecordSeen(journaledBatch(i, 10))
+		for _, aggregator := range operator.aggregators {
+			aggregator.outbox.Add(signedTaskResponse(keyPair, i), time.Now().Add(time.Hour))
+		}
+	}
+	finish := func(aggregator int, batchIdentifierHash string, stage BatchStage) {
+		operator.aggregators[aggregator].outbox.Remove(batchIdentifierHash)
+		operator.recordAggregatorFinished(batchIdentifierHash, stage)
+	}
+
+	abandoned := journaledBatch(1, 10).BatchIdentifierHash()
+	finish(0, abandoned, BatchAbandoned)
+	if stage, _ := operator.batchJournal.Stage(abandoned); stage != BatchSeen {
+		t.Errorf("expected batch not to be abandoned while an aggregator may accept it, got %s", stage)
+	}
+	finish(1, abandoned, BatchAbandoned)
+	if stage, _ := operator.batchJournal.Stage(abandoned); stage != BatchAbandoned {
+		t.Errorf("expected batch to be abandoned once every aggregator gave up, got %s", stage)
+	}
+
+	delivered := journaledBatch(2, 10).BatchIdentifierHash()
+	finish(0, delivered, BatchDelivered)
+	finish(1, delivered, BatchAbandoned)
+	if stage, _ := operator.batchJournal.Stage(delivered); stage != BatchDelivered {
+		t.Errorf("expected batch accepted by an aggregator to stay delivered, got %s", stage)
+	}
+}
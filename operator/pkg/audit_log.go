@@ -0,0 +1,241 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+var ErrAuditLogTampered = errors.New("audit log was tampered with")
+
+// AuditEntry is a line of the audit log, recording a task response signed by the operator.
+// Hash covers every other field, PrevHash included, so changing, removing or reordering
+// entries breaks the chain from that entry on.
+type AuditEntry struct {
+	Sequence            uint64            `json:"sequence"`
+	BatchIdentifierHash ethcommon.Hash    `json:"batch_identifier_hash"`
+	BatchMerkleRoot     ethcommon.Hash    `json:"batch_merkle_root"`
+	SenderAddress       ethcommon.Address `json:"sender_address"`
+	// Signature is the serialized G1 point of the BLS signature of the batch identifier hash
+	Signature  string    `json:"signature"`
+	OperatorId string    `json:"operator_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	// ReportDigest is the keccak256 of the JSON verification report of the batch, zero if there was none
+	ReportDigest ethcommon.Hash `json:"report_digest"`
+	PrevHash     ethcommon.Hash `json:"prev_hash"`
+	Hash         ethcommon.Hash `json:"hash"`
+}
+
+// computeHash returns the keccak256 of the JSON encoding of the entry without its hash.
+func (e AuditEntry) computeHash() (ethcommon.Hash, error) {
+	e.Hash = ethcommon.Hash{}
+	encoded, err := json.Marshal(e)
+	if err != nil {
+		return ethcommon.Hash{}, err
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// ReportDigest returns the digest audit entries keep of a verification report.
+func ReportDigest(report *BatchVerificationReport) (ethcommon.Hash, error) {
+	if report == nil {
+		return ethcommon.Hash{}, nil
+	}
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		return ethcommon.Hash{}, fmt.Errorf("could not marshal verification report: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// AuditLog is an append-only, hash-chained log of every task response the operator signs.
+// Like the batch journal, each entry is a JSON line synced to disk before Append returns,
+// and a partially written last line is discarded on open. Unlike the journal it is never compacted.
+type AuditLog struct {
+	path     string
+	mutex    sync.Mutex
+	file     *os.File
+	last     *AuditEntry
+	sequence uint64
+}
+
+// OpenAuditLog opens the audit log at path, creating it if it doesn't exist.
+// It fails if the chain of the existing entries is broken.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	entries, validLength, err := readAuditLog(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAuditChain(entries); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > int64(validLength) {
+		if err := os.Truncate(path, int64(validLength)); err != nil {
+			return nil, fmt.Errorf("could not discard partial audit log entry: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log: %w", err)
+	}
+	auditLog := &AuditLog{path: path, file: file}
+	if len(entries) > 0 {
+		auditLog.last = &entries[len(entries)-1]
+		auditLog.sequence = auditLog.last.Sequence + 1
+	}
+	return auditLog, nil
+}
+
+// ReadAuditLog returns the entries of the audit log at path without opening it for writing,
+// so it can be read while the operator is running. The chain is not verified.
+func ReadAuditLog(path string) ([]AuditEntry, error) {
+	entries, _, err := readAuditLog(path)
+	return entries, err
+}
+
+// readAuditLog returns the entries of the log and the length of the file they span,
+// which is shorter than the file if the last line was partially written.
+func readAuditLog(path string) ([]AuditEntry, int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not read audit log: %w", err)
+	}
+
+	var entries []AuditEntry
+	validLength := 0
+	for len(data[validLength:]) > 0 {
+		lineLength := bytes.IndexByte(data[validLength:], '\n')
+		if lineLength < 0 {
+			// Partially written entry, the operator crashed while appending it
+			break
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(data[validLength:validLength+lineLength], &entry); err != nil {
+			return nil, 0, fmt.Errorf("%w: corrupted entry at offset %d: %v", ErrAuditLogTampered, validLength, err)
+		}
+		entries = append(entries, entry)
+		validLength += lineLength + 1
+	}
+	return entries, validLength, nil
+}
+
+// Append records the signed task response, chained to the previous entry.
+func (l *AuditLog) Append(response *types.SignedTaskResponse, reportDigest ethcommon.Hash) (AuditEntry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry := AuditEntry{
+		Sequence:            l.sequence,
+		BatchIdentifierHash: response.BatchIdentifierHash,
+		BatchMerkleRoot:     response.BatchMerkleRoot,
+		SenderAddress:       response.SenderAddress,
+		Signature:           "0x" + hex.EncodeToString(response.BlsSignature.Serialize()),
+		OperatorId:          "0x" + hex.EncodeToString(response.OperatorId[:]),
+		Timestamp:           time.Now().UTC(),
+		ReportDigest:        reportDigest,
+	}
+	if l.last != nil {
+		entry.PrevHash = l.last.Hash
+	}
+	hash, err := entry.computeHash()
+	if err != nil {
+		return AuditEntry{}, fmt.Errorf("could not hash audit log entry: %w", err)
+	}
+	entry.Hash = hash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return AuditEntry{}, fmt.Errorf("could not marshal audit log entry: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return AuditEntry{}, fmt.Errorf("could not write audit log entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return AuditEntry{}, fmt.Errorf("could not sync audit log: %w", err)
+	}
+	l.last = &entry
+	l.sequence++
+	return entry, nil
+}
+
+// Close closes the audit log file.
+func (l *AuditLog) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.file.Close()
+}
+
+/*
+VerifyAuditLog
+Checks the audit log entries were not tampered with:
+- Every entry hash matches its content and chains to the previous entry.
+- The batch identifier hash is the one of the merkle root and the sender.
+- The signature is a valid BLS signature of the batch identifier hash by pubKeyG2.
+*/
+func VerifyAuditLog(entries []AuditEntry, pubKeyG2 *bls.G2Point) error {
+	if err := verifyAuditChain(entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := verifyAuditSignature(entry, pubKeyG2); err != nil {
+			return fmt.Errorf("%w: entry %d: %v", ErrAuditLogTampered, entry.Sequence, err)
+		}
+	}
+	return nil
+}
+
+func verifyAuditChain(entries []AuditEntry) error {
+	var prevHash ethcommon.Hash
+	for i, entry := range entries {
+		if entry.Sequence != uint64(i) {
+			return fmt.Errorf("%w: entry %d has sequence %d", ErrAuditLogTampered, i, entry.Sequence)
+		}
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("%w: entry %d doesn't chain to the previous entry", ErrAuditLogTampered, entry.Sequence)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return fmt.Errorf("could not hash audit log entry %d: %w", entry.Sequence, err)
+		}
+		if hash != entry.Hash {
+			return fmt.Errorf("%w: entry %d doesn't match its hash", ErrAuditLogTampered, entry.Sequence)
+		}
+		prevHash = entry.Hash
+	}
+	return nil
+}
+
+func verifyAuditSignature(entry AuditEntry, pubKeyG2 *bls.G2Point) error {
+	if batchIdentifierHash(entry.BatchMerkleRoot, entry.SenderAddress) != entry.BatchIdentifierHash {
+		return errors.New("batch identifier hash doesn't match the merkle root and sender")
+	}
+	encoded, err := hexutil.Decode(entry.Signature)
+	if err != nil || len(encoded) != 64 {
+		return errors.New("malformed signature")
+	}
+	point := new(bls.G1Point).Deserialize(encoded)
+	if !point.IsOnCurve() || !point.IsInSubGroup() || point.IsInfinity() {
+		return errors.New("signature is not on the G1 curve")
+	}
+	ok, err := (&bls.Signature{G1Point: point}).Verify(pubKeyG2, entry.BatchIdentifierHash)
+	if err != nil || !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
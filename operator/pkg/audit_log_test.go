@@ -0,0 +1,123 @@
+package operator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+func signedTaskResponse(keyPair *bls.KeyPair, i byte) *types.SignedTaskResponse {
+	batchMerkleRoot := [32]byte{i}
+	senderAddress := [20]byte{i}
+	batchIdentifierHash := batchIdentifierHash(batchMerkleRoot, senderAddress)
+	return &types.SignedTaskResponse{
+		BatchMerkleRoot:     batchMerkleRoot,
+		SenderAddress:       senderAddress,
+		BatchIdentifierHash: batchIdentifierHash,
+		BlsSignature:        *keyPair.SignMessage(batchIdentifierHash),
+		OperatorId:          eigentypes.OperatorIdFromKeyPair(keyPair),
+	}
+}
+
+func writeAuditLog(t *testing.T, keyPair *bls.KeyPair, responses int) string {
+	path := filepath.Join(t.TempDir(), "operator.audit_log")
+	auditLog, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("could not open audit log: %v", err)
+	}
+	defer auditLog.Close()
+	for i := 0; i < responses; i++ {
+		if _, err := auditLog.Append(signedTaskResponse(keyPair, byte(i)), ethcommon.Hash{byte(i)}); err != nil {
+			t.Fatalf("could not append to audit log: %v", err)
+		}
+	}
+	return path
+}
+
+func TestAuditLogVerifies(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	path := writeAuditLog(t, keyPair, 3)
+
+	// Simulate a crash while appending an entry, and keep appending after it
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("could not open audit log file: %v", err)
+	}
+	file.WriteString(`{"sequence":3,"batch_ide`)
+	file.Close()
+	auditLog, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("could not reopen audit log: %v", err)
+	}
+	entry, err := auditLog.Append(signedTaskResponse(keyPair, 3), ethcommon.Hash{})
+	auditLog.Close()
+	if err != nil || entry.Sequence != 3 {
+		t.Fatalf("expected entry 3 to be appended after the partial one was discarded, got %d (%v)", entry.Sequence, err)
+	}
+
+	entries, err := ReadAuditLog(path)
+	if err != nil {
+		t.Fatalf("could not read audit log: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+	if err := VerifyAuditLog(entries, keyPair.GetPubKeyG2()); err != nil {
+		t.Errorf("expected audit log to verify, got %v", err)
+	}
+
+	otherKeyPair, _ := bls.NewKeyPairFromString("67890")
+	if err := VerifyAuditLog(entries, otherKeyPair.GetPubKeyG2()); !errors.Is(err, ErrAuditLogTampered) {
+		t.Errorf("expected signatures to be rejected against another key, got %v", err)
+	}
+}
+
+func TestAuditLogDetectsTampering(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	path := writeAuditLog(t, keyPair, 3)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read audit log file: %v", err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+
+	tamperings := map[string]string{
+		"removed entry":   lines[0] + lines[2],
+		"reordered entry": lines[0] + lines[2] + lines[1],
+		"changed sender":  lines[0] + strings.Replace(lines[1], `"sender_address":"0x01`, `"sender_address":"0x02`, 1) + lines[2],
+	}
+	for name, tampered := range tamperings {
+		tamperedPath := filepath.Join(t.TempDir(), "operator.audit_log")
+		if err := os.WriteFile(tamperedPath, []byte(tampered), 0o644); err != nil {
+			t.Fatalf("could not write tampered audit log: %v", err)
+		}
+		entries, err := ReadAuditLog(tamperedPath)
+		if err != nil {
+			t.Fatalf("%s: could not read audit log: %v", name, err)
+		}
+		if err := VerifyAuditLog(entries, keyPair.GetPubKeyG2()); !errors.Is(err, ErrAuditLogTampered) {
+			t.Errorf("%s: expected tampering to be detected, got %v", name, err)
+		}
+		if _, err := OpenAuditLog(tamperedPath); !errors.Is(err, ErrAuditLogTampered) {
+			t.Errorf("%s: expected tampered audit log not to be opened, got %v", name, err)
+		}
+	}
+
+	// A rehashed entry with a signature over another batch is still caught
+	entries, _ := ReadAuditLog(path)
+	entries[1].Signature = entries[2].Signature
+	for i := 1; i < len(entries); i++ {
+		entries[i].PrevHash = entries[i-1].Hash
+		entries[i].Hash, _ = entries[i].computeHash()
+	}
+	if err := VerifyAuditLog(entries, keyPair.GetPubKeyG2()); !errors.Is(err, ErrAuditLogTampered) {
+		t.Errorf("expected signature of another batch to be rejected, got %v", err)
+	}
+}
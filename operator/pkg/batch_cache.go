@@ -0,0 +1,268 @@
+package operator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBatchCacheMaxSize = 4 << 30 // 4 GiB
+	DefaultBatchCacheMaxAge  = 24 * time.Hour
+
+	batchCacheFileExtension = ".batch"
+)
+
+type batchCacheEntry struct {
+	size       int64
+	storedAt   time.Time
+	lastAccess time.Time
+}
+
+// BatchCache keeps downloaded batches on disk, keyed by their merkle root, so a batch is
+// not downloaded again when it is retried, resumed after a restart or sent by several senders.
+// Cached batches are checked against their merkle root every time they are read, like the
+// downloaded ones, and a corrupted entry is removed once the check fails. Entries are evicted
+// once they are older than the maximum age, and the least recently used ones are evicted when
+// the cache grows over its maximum size.
+type BatchCache struct {
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+	mutex   sync.Mutex
+	entries map[[32]byte]*batchCacheEntry
+	size    int64
+}
+
+// NewBatchCache creates a cache in dir, picking up the batches already stored there.
+// Non-positive limits are replaced by their defaults.
+func NewBatchCache(dir string, maxSize int64, maxAge time.Duration) (*BatchCache, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultBatchCacheMaxSize
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultBatchCacheMaxAge
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create batch cache directory: %w", err)
+	}
+
+	cache := &BatchCache{
+		dir:     dir,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		entries: make(map[[32]byte]*batchCacheEntry),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read batch cache directory: %w", err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".tmp") {
+			// Batches that were being written when the operator stopped
+			_ = os.Remove(filepath.Join(dir, file.Name()))
+			continue
+		}
+		merkleRoot, ok := parseBatchCacheFileName(file.Name())
+		if !ok {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		cache.entries[merkleRoot] = &batchCacheEntry{size: info.Size(), storedAt: info.ModTime(), lastAccess: info.ModTime()}
+		cache.size += info.Size()
+	}
+
+	cache.mutex.Lock()
+	cache.evict()
+	cache.mutex.Unlock()
+	return cache, nil
+}
+
+// Open returns the cached batch with the given merkle root. Expired entries are removed and
+// reported as missing. The caller must check the batch still matches its merkle root, and
+// remove it otherwise.
+func (c *BatchCache) Open(merkleRoot [32]byte) (io.ReadCloser, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[merkleRoot]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(entry.storedAt) > c.maxAge {
+		c.remove(merkleRoot)
+		return nil, false
+	}
+
+	file, err := os.Open(c.path(merkleRoot))
+	if err != nil {
+		c.remove(merkleRoot)
+		return nil, false
+	}
+	entry.lastAccess = time.Now()
+	return file, true
+}
+
+// Create returns a writer storing a batch with the given merkle root in the cache.
+// The batch is only added to the cache once it is committed, which must be done after
+// checking it matches its merkle root.
+func (c *BatchCache) Create(merkleRoot [32]byte) *BatchCacheWriter {
+	writer := &BatchCacheWriter{cache: c, merkleRoot: merkleRoot}
+	writer.file, writer.err = os.CreateTemp(c.dir, "."+hex.EncodeToString(merkleRoot[:])+".*.tmp")
+	return writer
+}
+
+// Remove deletes the batch with the given merkle root from the cache.
+func (c *BatchCache) Remove(merkleRoot [32]byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.remove(merkleRoot)
+}
+
+// BatchCacheWriter writes a batch to the cache while it is being downloaded.
+// Errors writing it don't interrupt the download: the batch is just not cached.
+type BatchCacheWriter struct {
+	cache      *BatchCache
+	merkleRoot [32]byte
+	file       *os.File
+	size       int64
+	err        error
+}
+
+func (w *BatchCacheWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return len(p), nil
+	}
+	w.size += int64(len(p))
+	if w.size > w.cache.maxSize {
+		// Batches larger than the maximum size of the cache are not stored
+		w.err = errBatchTooLarge
+		return len(p), nil
+	}
+	if _, err := w.file.Write(p); err != nil {
+		w.err = err
+	}
+	return len(p), nil
+}
+
+// Commit adds the written batch to the cache. Batches larger than the maximum size of the
+// cache are discarded without an error.
+func (w *BatchCacheWriter) Commit() error {
+	if w.err == errBatchTooLarge {
+		w.Abort()
+		return nil
+	}
+	if w.err != nil {
+		w.Abort()
+		return fmt.Errorf("could not write batch to cache: %w", w.err)
+	}
+
+	if err := w.file.Sync(); err != nil {
+		w.Abort()
+		return fmt.Errorf("could not write batch to cache: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("could not write batch to cache: %w", err)
+	}
+	if err := os.Rename(w.file.Name(), w.cache.path(w.merkleRoot)); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("could not write batch to cache: %w", err)
+	}
+
+	c := w.cache
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if entry, ok := c.entries[w.merkleRoot]; ok {
+		c.size -= entry.size
+	}
+	now := time.Now()
+	c.entries[w.merkleRoot] = &batchCacheEntry{size: w.size, storedAt: now, lastAccess: now}
+	c.size += w.size
+	c.evict()
+	return nil
+}
+
+// Abort discards the written batch.
+func (w *BatchCacheWriter) Abort() {
+	if w.file == nil {
+		return
+	}
+	w.file.Close()
+	os.Remove(w.file.Name())
+}
+
+// Size returns the total size of the cached batches.
+func (c *BatchCache) Size() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.size
+}
+
+// evict removes the expired entries and then the least recently used ones until
+// the cache fits in its maximum size. Must be called with the mutex held.
+func (c *BatchCache) evict() {
+	var merkleRoots [][32]byte
+	for merkleRoot, entry := range c.entries {
+		if time.Since(entry.storedAt) > c.maxAge {
+			c.remove(merkleRoot)
+			continue
+		}
+		merkleRoots = append(merkleRoots, merkleRoot)
+	}
+	if c.size <= c.maxSize {
+		return
+	}
+
+	sort.Slice(merkleRoots, func(i, j int) bool {
+		return c.entries[merkleRoots[i]].lastAccess.Before(c.entries[merkleRoots[j]].lastAccess)
+	})
+	for _, merkleRoot := range merkleRoots {
+		if c.size <= c.maxSize {
+			return
+		}
+		c.remove(merkleRoot)
+	}
+}
+
+// remove deletes the entry and its file. Must be called with the mutex held.
+func (c *BatchCache) remove(merkleRoot [32]byte) {
+	entry, ok := c.entries[merkleRoot]
+	if !ok {
+		return
+	}
+	_ = os.Remove(c.path(merkleRoot))
+	c.size -= entry.size
+	delete(c.entries, merkleRoot)
+}
+
+func (c *BatchCache) path(merkleRoot [32]byte) string {
+	return filepath.Join(c.dir, hex.EncodeToString(merkleRoot[:])+batchCacheFileExtension)
+}
+
+func parseBatchCacheFileName(name string) ([32]byte, bool) {
+	var merkleRoot [32]byte
+	encodedMerkleRoot, found := strings.CutSuffix(name, batchCacheFileExtension)
+	if !found {
+		return merkleRoot, false
+	}
+	decoded, err := hex.DecodeString(encodedMerkleRoot)
+	if err != nil || len(decoded) != len(merkleRoot) {
+		return merkleRoot, false
+	}
+	copy(merkleRoot[:], decoded)
+	return merkleRoot, true
+}
@@ -0,0 +1,129 @@
+package operator
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestBatchCache(t *testing.T, dir string, maxSize int64) *BatchCache {
+	cache, err := NewBatchCache(dir, maxSize, time.Hour)
+	if err != nil {
+		t.Fatalf("could not create batch cache: %v", err)
+	}
+	return cache
+}
+
+// testBatch returns a batch along with a "merkle root" which is just its keccak256 hash,
+// so batches can be checked in tests without decoding them.
+func testBatch(size int, fill byte) ([]byte, [32]byte) {
+	batch := bytes.Repeat([]byte{fill}, size)
+	return batch, *(*[32]byte)(crypto.Keccak256(batch))
+}
+
+func storeTestBatch(t *testing.T, cache *BatchCache, merkleRoot [32]byte, batch []byte) {
+	writer := cache.Create(merkleRoot)
+	writer.Write(batch)
+	if err := writer.Commit(); err != nil {
+		t.Fatalf("could not store batch: %v", err)
+	}
+}
+
+func readCachedBatch(cache *BatchCache, merkleRoot [32]byte) ([]byte, bool) {
+	cachedBatch, ok := cache.Open(merkleRoot)
+	if !ok {
+		return nil, false
+	}
+	defer cachedBatch.Close()
+	batch, err := io.ReadAll(cachedBatch)
+	return batch, err == nil
+}
+
+func TestBatchCacheStoresCommittedBatches(t *testing.T) {
+	dir := t.TempDir()
+	cache := newTestBatchCache(t, dir, 1000)
+	batch, merkleRoot := testBatch(100, 1)
+
+	writer := cache.Create(merkleRoot)
+	writer.Write(batch[:50])
+	if _, ok := cache.Open(merkleRoot); ok {
+		t.Fatalf("batch should not be cached before it is committed")
+	}
+	writer.Write(batch[50:])
+	if err := writer.Commit(); err != nil {
+		t.Fatalf("could not commit batch: %v", err)
+	}
+	if cached, ok := readCachedBatch(cache, merkleRoot); !ok || !bytes.Equal(cached, batch) {
+		t.Fatalf("expected cached batch to be returned")
+	}
+
+	otherBatch, otherMerkleRoot := testBatch(100, 2)
+	writer = cache.Create(otherMerkleRoot)
+	writer.Write(otherBatch)
+	writer.Abort()
+	if _, ok := cache.Open(otherMerkleRoot); ok {
+		t.Errorf("aborted batch should not be cached")
+	}
+
+	cache.Remove(merkleRoot)
+	if _, ok := cache.Open(merkleRoot); ok {
+		t.Errorf("removed batch should not be returned")
+	}
+	if size := cache.Size(); size != 0 {
+		t.Errorf("expected empty cache, got size %d", size)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("expected no files left in the cache directory, got %v", files)
+	}
+
+	largeBatch, largeMerkleRoot := testBatch(2000, 3)
+	storeTestBatch(t, cache, largeMerkleRoot, largeBatch)
+	if _, ok := cache.Open(largeMerkleRoot); ok {
+		t.Errorf("batch larger than the cache should not be stored")
+	}
+}
+
+func TestBatchCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	cache := newTestBatchCache(t, dir, 250)
+
+	first, firstRoot := testBatch(100, 1)
+	second, secondRoot := testBatch(100, 2)
+	third, thirdRoot := testBatch(100, 3)
+	storeTestBatch(t, cache, firstRoot, first)
+	storeTestBatch(t, cache, secondRoot, second)
+	// The first batch is used more recently than the second one
+	time.Sleep(time.Millisecond)
+	readCachedBatch(cache, firstRoot)
+	storeTestBatch(t, cache, thirdRoot, third)
+
+	if _, ok := cache.Open(secondRoot); ok {
+		t.Errorf("least recently used batch should have been evicted")
+	}
+	if _, ok := readCachedBatch(cache, firstRoot); !ok {
+		t.Errorf("recently used batch should still be cached")
+	}
+
+	cache.entries[thirdRoot].storedAt = time.Now().Add(-2 * time.Hour)
+	if _, ok := cache.Open(thirdRoot); ok {
+		t.Errorf("expired batch should not be returned")
+	}
+
+	// Simulate a crash while writing a batch
+	if err := os.WriteFile(filepath.Join(dir, ".batch.123.tmp"), []byte("partial"), 0o644); err != nil {
+		t.Fatalf("could not write partial batch: %v", err)
+	}
+
+	reloaded := newTestBatchCache(t, dir, 250)
+	if _, ok := readCachedBatch(reloaded, firstRoot); !ok || reloaded.Size() != 100 {
+		t.Errorf("expected only the first batch after reloading the cache, size %d", reloaded.Size())
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(files) != 0 {
+		t.Errorf("expected partial batches to be removed, got %v", files)
+	}
+}
@@ -0,0 +1,338 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+// BatchDownloader downloads batches from the URL of their data pointer and from the configured mirrors.
+// As batches are checked against their merkle root, the bytes of any source can be trusted once
+// they match it, so sources are tried one after the other until one of them serves the batch.
+// If a hedge delay is set, the next source is also requested when the current one doesn't start
+// answering within the delay, and the batch is read from the first source that does.
+// A source is abandoned once it takes longer than the read timeout to answer or to send more of
+// the batch, however long reading the whole batch takes.
+type BatchDownloader struct {
+	client       *http.Client
+	mirrors      []string
+	rewrites     []config.BatchUrlRewrite
+	hedgeDelay   time.Duration
+	readTimeout  time.Duration
+	maxBatchSize int64
+	logger       logging.Logger
+}
+
+func NewBatchDownloader(mirrors []string, rewrites []config.BatchUrlRewrite, hedgeDelay time.Duration, maxBatchSize int64, logger logging.Logger) *BatchDownloader {
+	return &BatchDownloader{
+		client:       http.DefaultClient,
+		mirrors:      mirrors,
+		rewrites:     rewrites,
+		hedgeDelay:   hedgeDelay,
+		readTimeout:  BatchDownloadTimeout,
+		maxBatchSize: maxBatchSize,
+		logger:       logger,
+	}
+}
+
+// Sources returns the URLs the batch is downloaded from, in order: the batch URL after
+// applying the first matching rewrite rule, followed by the same path on every mirror.
+func (d *BatchDownloader) Sources(batchURL string) []string {
+	rewrittenURL := batchURL
+	for _, rewrite := range d.rewrites {
+		if strings.HasPrefix(batchURL, rewrite.Prefix) {
+			rewrittenURL = rewrite.Replacement + strings.TrimPrefix(batchURL, rewrite.Prefix)
+			break
+		}
+	}
+	sources := []string{rewrittenURL}
+
+	parsedURL, err := url.Parse(batchURL)
+	if err != nil {
+		return sources
+	}
+	for _, mirror := range d.mirrors {
+		mirrorURL := strings.TrimSuffix(mirror, "/") + parsedURL.RequestURI()
+		if !slices.Contains(sources, mirrorURL) {
+			sources = append(sources, mirrorURL)
+		}
+	}
+	return sources
+}
+
+// Stream hands the batch over to consume while it is being downloaded. consume is expected to
+// check the batch matches its merkle root, returning ErrMerkleRootMismatch otherwise, and is
+// called again with the next source whenever it fails, so it must discard what it read before.
+// All the sources are tried up to maxRetries times, waiting between rounds an exponentially
+// growing delay starting at retryDelay.
+func (d *BatchDownloader) Stream(ctx context.Context, batchURL string, maxRetries int, retryDelay time.Duration, consume func(batch io.Reader) error) error {
+	sources := d.Sources(batchURL)
+
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			d.logger.Infof("Waiting for %s before retrying data fetch (attempt %d of %d)", retryDelay, attempt+1, maxRetries)
+			select {
+			case <-time.After(retryDelay):
+				// Wait before retrying
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			retryDelay *= 2 // Exponential backoff. Ex: 5s, 10s, 20s
+		}
+
+		err = d.streamFromSources(ctx, sources, consume)
+		if err == nil {
+			return nil
+		}
+		// A batch that doesn't match its merkle root won't match it in the next attempt either
+		if isBatchContentError(err) || ctx.Err() != nil {
+			return err
+		}
+		d.logger.Warnf("Error fetching batch from data service - (attempt %d): %v", attempt+1, err)
+	}
+	return err
+}
+
+// isBatchContentError reports whether err means the batch served is not the expected one.
+func isBatchContentError(err error) bool {
+	return errors.Is(err, ErrMerkleRootMismatch) || errors.Is(err, ErrBatchDecoding)
+}
+
+// streamFromSources hands the batch over to consume from the first source that answers,
+// moving to the remaining ones while consume fails.
+// The batch is only reported as not matching its merkle root if every source served a non matching batch.
+func (d *BatchDownloader) streamFromSources(ctx context.Context, sources []string, consume func(batch io.Reader) error) error {
+	var errs []error
+	contentErrors := 0
+	for remaining := sources; len(remaining) > 0; {
+		source, body, untried, err := d.open(ctx, remaining)
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		remaining = untried
+
+		err = consume(body)
+		body.Close()
+		if err == nil {
+			if source != sources[0] {
+				d.logger.Infof("Batch downloaded from %s", source)
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if isBatchContentError(err) {
+			contentErrors++
+		}
+		d.logger.Warnf("Could not read batch from %s: %v", source, err)
+		errs = append(errs, fmt.Errorf("%s: %w", source, err))
+	}
+
+	if contentErrors == len(sources) {
+		return errors.Join(errs...)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Don't wrap the errors: a mismatching mirror doesn't make the batch invalid if another source failed
+	return fmt.Errorf("could not download batch from any source: %v", errors.Join(errs...))
+}
+
+type sourceResponse struct {
+	source string
+	body   io.ReadCloser
+	err    error
+}
+
+// open requests the batch from the first source, also requesting it from the next one when
+// a request fails or the hedge delay elapses, and returns the body of the first successful
+// response. Sources whose request was abandoned in favour of it are returned along with the
+// untried ones, so they can be tried again if reading the batch fails.
+func (d *BatchDownloader) open(ctx context.Context, sources []string) (string, io.ReadCloser, []string, error) {
+	responses := make(chan sourceResponse, len(sources))
+	cancels := make(map[string]context.CancelFunc, len(sources))
+	started, running := 0, 0
+	startNext := func() {
+		source := sources[started]
+		started++
+		running++
+		requestCtx, cancel := context.WithCancel(ctx)
+		cancels[source] = cancel
+		go func() {
+			body, err := d.get(requestCtx, source)
+			if body != nil {
+				// The request must outlive this function, so it is only cancelled once the body is closed
+				body = &cancelOnClose{ReadCloser: body, cancel: cancel}
+			}
+			responses <- sourceResponse{source: source, body: body, err: err}
+		}()
+	}
+
+	startNext()
+	var errs []error
+	for running > 0 {
+		var hedge <-chan time.Time
+		if d.hedgeDelay > 0 && started < len(sources) {
+			hedge = time.After(d.hedgeDelay)
+		}
+
+		select {
+		case response := <-responses:
+			running--
+			cancelRequest := cancels[response.source]
+			delete(cancels, response.source)
+			if response.err == nil {
+				untried := slices.Clone(sources[started:])
+				for _, source := range sources[:started] {
+					if cancel, ok := cancels[source]; ok {
+						cancel()
+						untried = append(untried, source)
+					}
+				}
+				go closeAbandonedResponses(responses, running)
+				return response.source, response.body, untried, nil
+			}
+			// A failed request has no body whose closing cancels it
+			cancelRequest()
+			errs = append(errs, fmt.Errorf("%s: %w", response.source, response.err))
+			if started < len(sources) {
+				startNext()
+			}
+		case <-hedge:
+			d.logger.Infof("No answer received after %s, also trying %s", d.hedgeDelay, sources[started])
+			startNext()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", nil, nil, err
+	}
+	return "", nil, nil, errors.Join(errs...)
+}
+
+// closeAbandonedResponses closes the bodies of the requests still running when another source answered.
+func closeAbandonedResponses(responses <-chan sourceResponse, running int) {
+	for ; running > 0; running-- {
+		if response := <-responses; response.body != nil {
+			response.body.Close()
+		}
+	}
+}
+
+// get requests the batch from a single URL, returning the body of the response,
+// which fails to be read past the maximum batch size.
+func (d *BatchDownloader) get(ctx context.Context, batchURL string) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	timeoutErr := fmt.Errorf("%w after %s", errBatchSourceTimeout, d.readTimeout)
+	timer := time.AfterFunc(d.readTimeout, func() { cancel(timeoutErr) })
+	fail := func(err error) (io.ReadCloser, error) {
+		timer.Stop()
+		if errors.Is(context.Cause(ctx), errBatchSourceTimeout) {
+			err = context.Cause(ctx)
+		}
+		cancel(nil)
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", batchURL, nil)
+	if err != nil {
+		return fail(err)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fail(err)
+	}
+	timer.Stop()
+
+	// Check if the response is OK
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return fail(fmt.Errorf("error getting batch from data service: %s", resp.Status))
+	}
+
+	if resp.ContentLength > d.maxBatchSize {
+		resp.Body.Close()
+		return fail(fmt.Errorf("proof size %d exceeds max batch size %d", resp.ContentLength, d.maxBatchSize))
+	}
+
+	// This is to prevent the operator from downloading a larger than expected file
+	body := &maxSizeReader{ReadCloser: resp.Body, remaining: d.maxBatchSize}
+	return &idleTimeoutReader{ReadCloser: body, ctx: ctx, cancel: cancel, timer: timer, timeout: d.readTimeout}, nil
+}
+
+var errBatchSourceTimeout = errors.New("batch source stopped answering")
+
+// idleTimeoutReader cancels the request once a read waits longer than the timeout for the source
+// to send more of the batch. Only the time spent waiting on the source counts: a batch read
+// slowly because its proofs are verified as they arrive doesn't time out.
+type idleTimeoutReader struct {
+	io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	r.timer.Reset(r.timeout)
+	n, err := r.ReadCloser.Read(p)
+	r.timer.Stop()
+	if err != nil && errors.Is(context.Cause(r.ctx), errBatchSourceTimeout) {
+		err = context.Cause(r.ctx)
+	}
+	return n, err
+}
+
+func (r *idleTimeoutReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel(nil)
+	return err
+}
+
+// maxSizeReader fails once more than the allowed number of bytes are read.
+type maxSizeReader struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, errBatchTooLarge
+	}
+	if int64(len(p)) > r.remaining+1 {
+		// Read one more byte than allowed to find out whether the body is larger than expected
+		p = p[:r.remaining+1]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, errBatchTooLarge
+	}
+	return n, err
+}
+
+var errBatchTooLarge = errors.New("batch size exceeds max batch size")
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
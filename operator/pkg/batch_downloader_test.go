@@ -0,0 +1,196 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/core/config"
+)
+
+func newTestBatchDownloader(mirrors []string, hedgeDelay time.Duration) *BatchDownloader {
+	return NewBatchDownloader(mirrors, nil, hedgeDelay, 1<<20, logging.NewTextSLogger(io.Discard, nil))
+}
+
+// download streams the batch from batchURL, checking its keccak256 hash matches merkleRoot.
+func download(ctx context.Context, downloader *BatchDownloader, batchURL string, merkleRoot [32]byte, maxRetries int) ([]byte, error) {
+	var downloaded []byte
+	err := downloader.Stream(ctx, batchURL, maxRetries, time.Millisecond, func(batch io.Reader) error {
+		var err error
+		downloaded, err = io.ReadAll(batch)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(crypto.Keccak256(downloaded), merkleRoot[:]) {
+			return ErrMerkleRootMismatch
+		}
+		return nil
+	})
+	return downloaded, err
+}
+
+func batchServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBatchDownloaderSources(t *testing.T) {
+	downloader := NewBatchDownloader(
+		[]string{"https://mirror.example.com/", "https://storage.example.com"},
+		[]config.BatchUrlRewrite{{Prefix: "https://storage.example.com/", Replacement: "https://cdn.example.com/"}},
+		0, 1, nil,
+	)
+
+	sources := downloader.Sources("https://storage.example.com/batch.json?v=1")
+	expected := []string{
+		"https://cdn.example.com/batch.json?v=1",
+		"https://mirror.example.com/batch.json?v=1",
+		"https://storage.example.com/batch.json?v=1",
+	}
+	if len(sources) != len(expected) {
+		t.Fatalf("expected sources %v, got %v", expected, sources)
+	}
+	for i := range expected {
+		if sources[i] != expected[i] {
+			t.Errorf("expected source %d to be %s, got %s", i, expected[i], sources[i])
+		}
+	}
+}
+
+func TestBatchDownloaderFallsBackToMirror(t *testing.T) {
+	batch, merkleRoot := testBatch(64, 7)
+	primary := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	tampered := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not the batch"))
+	})
+	mirror := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/batches/batch.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(batch)
+	})
+
+	downloader := newTestBatchDownloader([]string{tampered.URL, mirror.URL}, 0)
+	downloaded, err := download(context.Background(), downloader, primary.URL+"/batches/batch.json", merkleRoot, 1)
+	if err != nil {
+		t.Fatalf("expected batch to be downloaded from mirror: %v", err)
+	}
+	if !bytes.Equal(downloaded, batch) {
+		t.Errorf("downloaded batch doesn't match")
+	}
+
+	_, err = download(context.Background(), downloader, tampered.URL+"/batches/batch.json", merkleRoot, 1)
+	if err != nil {
+		t.Fatalf("expected batch to be downloaded from mirror after a mismatching source: %v", err)
+	}
+
+	downloader = newTestBatchDownloader([]string{tampered.URL}, 0)
+	_, err = download(context.Background(), downloader, tampered.URL+"/batches/batch.json", merkleRoot, 3)
+	if !errors.Is(err, ErrMerkleRootMismatch) {
+		t.Errorf("expected merkle root mismatch when every source serves another batch, got %v", err)
+	}
+}
+
+func TestBatchDownloaderHedgesSlowSource(t *testing.T) {
+	batch, merkleRoot := testBatch(64, 8)
+	release := make(chan struct{})
+	defer close(release)
+	slow := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	mirror := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(batch)
+	})
+
+	downloader := newTestBatchDownloader([]string{mirror.URL}, 10*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	downloaded, err := download(ctx, downloader, slow.URL+"/batch.json", merkleRoot, 1)
+	if err != nil || !bytes.Equal(downloaded, batch) {
+		t.Errorf("expected hedged request to the mirror to win, got %v", err)
+	}
+}
+
+func TestBatchDownloaderLimitsBatchSize(t *testing.T) {
+	batch, merkleRoot := testBatch(64, 9)
+	server := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Without a content length the size is only known while reading the batch
+		w.(http.Flusher).Flush()
+		w.Write(batch)
+	})
+
+	downloader := NewBatchDownloader(nil, nil, 0, 32, logging.NewTextSLogger(io.Discard, nil))
+	_, err := download(context.Background(), downloader, server.URL+"/batch.json", merkleRoot, 1)
+	if err == nil || !strings.Contains(err.Error(), errBatchTooLarge.Error()) {
+		t.Errorf("expected batch larger than the max batch size to be rejected, got %v", err)
+	}
+}
+
+func TestBatchDownloaderTimesOutStalledSource(t *testing.T) {
+	batch, merkleRoot := testBatch(64, 10)
+	release := make(chan struct{})
+	defer close(release)
+	stalled := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(batch[:32])
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	downloader := newTestBatchDownloader(nil, 0)
+	downloader.readTimeout = 50 * time.Millisecond
+	_, err := download(context.Background(), downloader, stalled.URL+"/batch.json", merkleRoot, 1)
+	if err == nil || !strings.Contains(err.Error(), errBatchSourceTimeout.Error()) {
+		t.Errorf("expected a source that stops sending the batch to time out, got %v", err)
+	}
+}
+
+func TestBatchDownloaderDoesNotTimeOutSlowConsumer(t *testing.T) {
+	batch, merkleRoot := testBatch(64, 11)
+	server := batchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(batch)
+	})
+
+	downloader := newTestBatchDownloader(nil, 0)
+	downloader.readTimeout = 50 * time.Millisecond
+	err := downloader.Stream(context.Background(), server.URL+"/batch.json", 1, time.Millisecond, func(batch io.Reader) error {
+		// Reading slower than the timeout, like a batch whose proofs take long to verify
+		var downloaded []byte
+		chunk := make([]byte, 16)
+		for {
+			time.Sleep(30 * time.Millisecond)
+			n, err := batch.Read(chunk)
+			downloaded = append(downloaded, chunk[:n]...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if !bytes.Equal(crypto.Keccak256(downloaded), merkleRoot[:]) {
+			return ErrMerkleRootMismatch
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected a batch read slowly to be downloaded, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package operator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/operator/merkle"
+)
+
+const (
+	cborMajorTypeArray     = 0x80
+	cborMajorTypeMask      = 0xe0
+	cborAdditionalInfoMask = 0x1f
+)
+
+// streamBatch decodes the verification data of a batch while it is being read, calling
+// onVerificationData for every entry in order, and returns the merkle root of the batch.
+// onLength is called with the number of entries as soon as it is known, before any of them.
+// Only CBOR batches with a definite length can be decoded one entry at a time: other batches,
+// like the JSON ones, are read completely before the entries are handed over.
+// Errors reading the batch are returned as they are, since the batch may be readable later,
+// while batches that can't be decoded are reported with ErrBatchDecoding.
+func streamBatch(batch io.Reader, logger logging.Logger, onLength func(length int), onVerificationData func(index int, verificationData VerificationData)) ([32]byte, error) {
+	var merkleRoot [32]byte
+	source := &readErrorRecorder{reader: batch}
+	reader := bufio.NewReader(source)
+
+	var leaves [][32]byte
+	handle := func(verificationData VerificationData) error {
+		leaf, err := verificationData.LeafHash()
+		if err != nil {
+			return fmt.Errorf("%w: entry %d: %v", ErrBatchDecoding, len(leaves), err)
+		}
+		onVerificationData(len(leaves), verificationData)
+		leaves = append(leaves, leaf)
+		return nil
+	}
+
+	length, headerSize, ok := peekCborArrayLength(reader)
+	if ok {
+		if _, err := reader.Discard(headerSize); err != nil {
+			return merkleRoot, source.errOr(err)
+		}
+		decMode, err := createDecoderMode()
+		if err != nil {
+			return merkleRoot, fmt.Errorf("error creating CBOR decoder: %s", err)
+		}
+		decoder := decMode.NewDecoder(reader)
+		onLength(int(length))
+		for i := uint64(0); i < length; i++ {
+			var verificationData VerificationData
+			if err := decoder.Decode(&verificationData); err != nil {
+				return merkleRoot, source.errOr(fmt.Errorf("%w: entry %d: %v", ErrBatchDecoding, i, err))
+			}
+			if err := handle(verificationData); err != nil {
+				return merkleRoot, err
+			}
+		}
+	} else {
+		batchBytes, err := io.ReadAll(reader)
+		if err != nil {
+			return merkleRoot, err
+		}
+		verificationDataBatch, err := decodeBatch(batchBytes, logger)
+		if err != nil {
+			return merkleRoot, err
+		}
+		onLength(len(verificationDataBatch))
+		for _, verificationData := range verificationDataBatch {
+			if err := handle(verificationData); err != nil {
+				return merkleRoot, err
+			}
+		}
+	}
+
+	if len(leaves) == 0 {
+		return merkleRoot, fmt.Errorf("%w: empty batch", ErrBatchDecoding)
+	}
+	return merkle.Root(leaves), nil
+}
+
+// peekCborArrayLength returns the number of entries of the CBOR array the reader starts with,
+// and the size of its header, without consuming it. It returns false if the reader doesn't
+// start with a CBOR array of definite length.
+func peekCborArrayLength(reader *bufio.Reader) (uint64, int, bool) {
+	header, err := reader.Peek(1)
+	if err != nil || header[0]&cborMajorTypeMask != cborMajorTypeArray {
+		return 0, 0, false
+	}
+
+	additionalInfo := header[0] & cborAdditionalInfoMask
+	if additionalInfo < 24 {
+		return uint64(additionalInfo), 1, true
+	}
+	if additionalInfo > 27 {
+		// Indefinite length arrays and reserved values
+		return 0, 0, false
+	}
+
+	lengthSize := 1 << (additionalInfo - 24)
+	header, err = reader.Peek(1 + lengthSize)
+	if err != nil {
+		return 0, 0, false
+	}
+	var length uint64
+	for _, b := range header[1:] {
+		length = length<<8 | uint64(b)
+	}
+	return length, 1 + lengthSize, true
+}
+
+// readErrorRecorder keeps the first error of the underlying reader other than io.EOF,
+// to tell a batch that could not be read apart from one that could not be decoded.
+type readErrorRecorder struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *readErrorRecorder) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// errOr returns the error of the underlying reader if there was one, or err otherwise.
+func (r *readErrorRecorder) errOr(err error) error {
+	if r.err != nil {
+		return r.err
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	testBatchFile      = "../merkle_tree/lib/test_files/merkle_tree_batch.bin"
+	testMerkleRootFile = "../merkle_tree/lib/test_files/merkle_root.bin"
+)
+
+func readTestBatch(t *testing.T) ([]byte, [32]byte) {
+	batch, err := os.ReadFile(testBatchFile)
+	if err != nil {
+		t.Fatalf("could not read test batch: %v", err)
+	}
+	encodedMerkleRoot, err := os.ReadFile(testMerkleRootFile)
+	if err != nil {
+		t.Fatalf("could not read test merkle root: %v", err)
+	}
+	var merkleRoot [32]byte
+	if _, err := hex.Decode(merkleRoot[:], bytes.TrimSpace(encodedMerkleRoot)); err != nil {
+		t.Fatalf("could not decode test merkle root: %v", err)
+	}
+	return batch, merkleRoot
+}
+
+func TestStreamBatchComputesMerkleRoot(t *testing.T) {
+	batch, expectedMerkleRoot := readTestBatch(t)
+	expected, err := decodeBatch(batch, logging.NewTextSLogger(io.Discard, nil))
+	if err != nil {
+		t.Fatalf("could not decode test batch: %v", err)
+	}
+
+	var jsonBatch []byte
+	if err := codec.NewEncoderBytes(&jsonBatch, new(codec.JsonHandle)).Encode(expected); err != nil {
+		t.Fatalf("could not encode test batch as JSON: %v", err)
+	}
+
+	for name, encodedBatch := range map[string][]byte{"cbor": batch, "json": jsonBatch} {
+		var streamed []VerificationData
+		length := -1
+		onLength := func(n int) {
+			if len(streamed) != 0 {
+				t.Errorf("%s: length received after %d entries", name, len(streamed))
+			}
+			length = n
+		}
+		merkleRoot, err := streamBatch(bytes.NewReader(encodedBatch), logging.NewTextSLogger(io.Discard, nil), onLength, func(index int, verificationData VerificationData) {
+			if index != len(streamed) {
+				t.Errorf("%s: entry %d received at position %d", name, index, len(streamed))
+			}
+			streamed = append(streamed, verificationData)
+		})
+		if err != nil {
+			t.Fatalf("%s: could not stream batch: %v", name, err)
+		}
+		if merkleRoot != expectedMerkleRoot {
+			t.Errorf("%s: expected merkle root %x, got %x", name, expectedMerkleRoot, merkleRoot)
+		}
+		if len(streamed) != len(expected) || length != len(expected) {
+			t.Errorf("%s: expected %d entries, got %d of length %d", name, len(expected), len(streamed), length)
+		}
+	}
+}
+
+func TestStreamBatchErrors(t *testing.T) {
+	batch, _ := readTestBatch(t)
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	ignore := func(int, VerificationData) {}
+	ignoreLength := func(int) {}
+
+	_, err := streamBatch(bytes.NewReader(batch[:len(batch)/2]), logger, ignoreLength, ignore)
+	if !errors.Is(err, ErrBatchDecoding) {
+		t.Errorf("expected truncated batch to fail decoding, got %v", err)
+	}
+
+	_, err = streamBatch(bytes.NewReader([]byte{0x80}), logger, ignoreLength, ignore)
+	if !errors.Is(err, ErrBatchDecoding) {
+		t.Errorf("expected empty batch to fail decoding, got %v", err)
+	}
+
+	readErr := errors.New("connection reset")
+	_, err = streamBatch(io.MultiReader(bytes.NewReader(batch[:len(batch)/2]), &failingReader{err: readErr}), logger, ignoreLength, ignore)
+	if !errors.Is(err, readErr) || errors.Is(err, ErrBatchDecoding) {
+		t.Errorf("expected read error to be returned as is, got %v", err)
+	}
+
+	_, err = streamBatch(strings.NewReader("not a batch"), logger, ignoreLength, ignore)
+	if !errors.Is(err, ErrBatchDecoding) {
+		t.Errorf("expected invalid batch to fail decoding, got %v", err)
+	}
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,156 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// batchVerification verifies the proofs of a batch through the shared verification scheduler
+// as they are decoded, before the whole batch is downloaded.
+// Once a proof is found to be invalid, the pending verifications are cancelled and the proofs
+// still to come are not verified, since the verdict of the batch is already final.
+type batchVerification struct {
+	operator                *Operator
+	ctx                     context.Context
+	cancel                  context.CancelFunc
+	disabledVerifiersBitmap *big.Int
+	start                   time.Time
+	wg                      sync.WaitGroup
+	cancelledVerifications  atomic.Int64
+
+	mutex     sync.Mutex
+	proofs    []ProofVerificationReport
+	rejection error
+	rejected  chan struct{}
+	// Proofs of the batch counted in the backlog of the scheduler
+	backlog int64
+}
+
+func (o *Operator) newBatchVerification(ctx context.Context, disabledVerifiersBitmap *big.Int) *batchVerification {
+	ctx, cancel := context.WithCancel(ctx)
+	return &batchVerification{
+		operator:                o,
+		ctx:                     ctx,
+		cancel:                  cancel,
+		disabledVerifiersBitmap: disabledVerifiersBitmap,
+		start:                   time.Now(),
+		rejected:                make(chan struct{}),
+	}
+}
+
+// Expect counts the proofs of the batch in the verification backlog, as soon as their number is
+// known, until they are verified.
+func (v *batchVerification) Expect(proofs int) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.updateBacklog(int64(proofs))
+}
+
+// Submit schedules the verification of the proof at position index of the batch.
+// Proofs must be submitted in order.
+func (v *batchVerification) Submit(index int, verificationData VerificationData) {
+	v.mutex.Lock()
+	// Proofs stay reported as cancelled unless their result arrives before the verdict
+	v.proofs = append(v.proofs, ProofVerificationReport{
+		Index:         index,
+		ProvingSystem: provingSystemName(verificationData.ProvingSystemId),
+		ErrorCategory: ErrorCategoryCancelled,
+	})
+	rejected := v.rejection != nil
+	if rejected {
+		v.updateBacklog(-1)
+	}
+	v.mutex.Unlock()
+	if rejected {
+		return
+	}
+
+	v.wg.Add(1)
+	v.operator.verificationScheduler.Submit(v.ctx, verificationData.ProvingSystemId, func(ctx context.Context) {
+		defer v.wg.Done()
+		defer v.finished()
+		proofReport := v.operator.verify(ctx, index, verificationData, v.disabledVerifiersBitmap)
+		if proofReport.ErrorCategory == ErrorCategoryCancelled {
+			v.cancelledVerifications.Add(1)
+			v.operator.metrics.IncOperatorCancelledVerifications()
+			return
+		}
+		v.record(proofReport)
+	})
+}
+
+// finished removes a proof whose verification is over from the backlog.
+func (v *batchVerification) finished() {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.updateBacklog(-1)
+}
+
+// updateBacklog updates the proofs of the batch counted in the backlog of the scheduler,
+// which never go below zero. v.mutex must be held.
+func (v *batchVerification) updateBacklog(delta int64) {
+	delta = max(delta, -v.backlog)
+	v.backlog += delta
+	v.operator.verificationScheduler.backlog.Add(delta)
+}
+
+func (v *batchVerification) record(proofReport ProofVerificationReport) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.proofs[proofReport.Index] = proofReport
+	if !proofReport.Verified && v.rejection == nil {
+		v.rejection = fmt.Errorf("%w: proof %d (%s): %s", ErrInvalidProof, proofReport.Index, proofReport.ProvingSystem, proofReport.ErrorCategory)
+		close(v.rejected)
+		v.cancel()
+	}
+}
+
+// Wait waits until every submitted proof is verified or one of them is rejected, recording
+// the outcome of each of them in report. It must only be called once every proof of the
+// batch was submitted, and returns an error unless all of them were verified.
+func (v *batchVerification) Wait(report *BatchVerificationReport) error {
+	done := make(chan struct{})
+	go func() {
+		v.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-v.rejected:
+		go func() {
+			<-done
+			v.operator.Logger.Infof("Batch rejected, %d pending proof verifications were cancelled", v.cancelledVerifications.Load())
+		}()
+	case <-v.ctx.Done():
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	report.Proofs = slices.Clone(v.proofs)
+	report.VerificationDuration = time.Since(v.start)
+	if v.rejection != nil {
+		return v.rejection
+	}
+	// Proofs cancelled by the caller don't send a result, so the batch can't be accepted
+	return v.ctx.Err()
+}
+
+// Abort cancels the pending verifications, discarding their outcome, and removes the proofs
+// of the batch from the backlog.
+func (v *batchVerification) Abort() {
+	v.cancel()
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.updateBacklog(-v.backlog)
+}
@@ -0,0 +1,353 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+// HealthStatus is the machine readable outcome of a health check.
+type HealthStatus string
+
+const (
+	HealthStatusOk HealthStatus = "ok"
+	// The operator keeps working, but with less redundancy or more load than expected
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusFailing  HealthStatus = "failing"
+)
+
+const (
+	DefaultHealthMaxHeadAge = 1 * time.Minute
+	// Time each reachability probe has to complete
+	healthProbeTimeout = 5 * time.Second
+)
+
+// HealthCheck is the outcome of one of the checks of the operator health.
+type HealthCheck struct {
+	Name    string                 `json:"name"`
+	Status  HealthStatus           `json:"status"`
+	Reason  string                 `json:"reason,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport is the body of the /healthz and /readyz responses.
+// Status is the worst status among the checks, and Reasons lists why each failing check fails.
+type HealthReport struct {
+	Status  HealthStatus  `json:"status"`
+	Checks  []HealthCheck `json:"checks"`
+	Reasons []string      `json:"reasons,omitempty"`
+}
+
+// blockNumberClient is the part of an eth client used to probe its endpoint.
+type blockNumberClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// HealthChecker checks the state of the operator for the /healthz and /readyz endpoints.
+// Liveness only looks at the state of the operator process, so a failing /healthz means
+// the operator is stalled, while readiness also probes the endpoints the operator depends on.
+type HealthChecker struct {
+	subscriptionStatus  func() chainio.SubscriptionStatus
+	verificationBacklog func() int64
+	lastProcessedBlock  func() uint64
+	// Endpoints are probed in order, the first one of each is the primary
+	rpcClients             []blockNumberClient
+	wsClients              []blockNumberClient
+	aggregatorAddrs        []string
+	maxHeadAge             time.Duration
+	maxVerificationBacklog int64
+	startedAt              time.Time
+}
+
+func (o *Operator) newHealthChecker() *HealthChecker {
+	maxHeadAge := o.Config.Operator.HealthMaxHeadAge
+	if maxHeadAge <= 0 {
+		maxHeadAge = DefaultHealthMaxHeadAge
+	}
+	baseConfig := o.Config.BaseConfig
+	aggregatorAddrs := make([]string, 0, len(o.aggregators))
+	for _, aggregator := range o.aggregators {
+		aggregatorAddrs = append(aggregatorAddrs, AggregatorEndpointAddress(aggregator.address))
+	}
+	return &HealthChecker{
+		subscriptionStatus:     o.avsSubscriber.SubscriptionStatus,
+		verificationBacklog:    o.verificationScheduler.Backlog,
+		lastProcessedBlock:     o.batchJournal.LastSeenBlock,
+		rpcClients:             []blockNumberClient{&baseConfig.EthRpcClient, &baseConfig.EthRpcClientFallback},
+		wsClients:              []blockNumberClient{&baseConfig.EthWsClient, &baseConfig.EthWsClientFallback},
+		aggregatorAddrs:        aggregatorAddrs,
+		maxHeadAge:             maxHeadAge,
+		maxVerificationBacklog: o.Config.Operator.HealthMaxVerificationBacklog,
+		startedAt:              time.Now(),
+	}
+}
+
+// Liveness reports whether the operator is still following the chain and processing batches.
+func (h *HealthChecker) Liveness() HealthReport {
+	subscription := h.subscriptionStatus()
+	return newHealthReport(
+		h.checkSubscription(subscription),
+		h.checkVerificationQueue(),
+		h.checkLastProcessedBlock(subscription),
+	)
+}
+
+// Readiness reports whether the operator can verify batches and deliver its responses,
+// probing the eth endpoints and the aggregator concurrently.
+func (h *HealthChecker) Readiness(ctx context.Context) HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var rpcCheck, wsCheck, aggregatorCheck HealthCheck
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		rpcCheck = checkEthEndpoints(ctx, "eth_rpc", h.rpcClients)
+	}()
+	go func() {
+		defer wg.Done()
+		wsCheck = checkEthEndpoints(ctx, "eth_ws", h.wsClients)
+	}()
+	go func() {
+		defer wg.Done()
+		aggregatorCheck = h.checkAggregator(ctx)
+	}()
+	wg.Wait()
+
+	liveness := h.Liveness()
+	return newHealthReport(append(liveness.Checks, rpcCheck, wsCheck, aggregatorCheck)...)
+}
+
+func newHealthReport(checks ...HealthCheck) HealthReport {
+	report := HealthReport{Status: HealthStatusOk, Checks: checks}
+	for _, check := range checks {
+		switch check.Status {
+		case HealthStatusFailing:
+			report.Status = HealthStatusFailing
+			report.Reasons = append(report.Reasons, fmt.Sprintf("%s: %s", check.Name, check.Reason))
+		case HealthStatusDegraded:
+			if report.Status == HealthStatusOk {
+				report.Status = HealthStatusDegraded
+			}
+		}
+	}
+	return report
+}
+
+func (h *HealthChecker) checkSubscription(subscription chainio.SubscriptionStatus) HealthCheck {
+	check := HealthCheck{
+		Name:   "subscription",
+		Status: HealthStatusOk,
+		Details: map[string]interface{}{
+			"primary_subscribed":  subscription.PrimarySubscribed,
+			"fallback_subscribed": subscription.FallbackSubscribed,
+			"last_event_block":    subscription.LastEventBlock,
+			"last_head":           subscription.LastHead,
+		},
+	}
+	if !subscription.LastEventAt.IsZero() {
+		check.Details["last_event_at"] = subscription.LastEventAt
+	}
+	if !subscription.LastHeadAt.IsZero() {
+		check.Details["last_head_at"] = subscription.LastHeadAt
+	}
+
+	// Before the first head is seen, the age is measured from the start of the checker
+	lastHeadAt := subscription.LastHeadAt
+	if lastHeadAt.Before(h.startedAt) {
+		lastHeadAt = h.startedAt
+	}
+	headAge := time.Since(lastHeadAt)
+
+	switch {
+	case !subscription.PrimarySubscribed && !subscription.FallbackSubscribed:
+		check.Status = HealthStatusFailing
+		check.Reason = "not subscribed to new batches"
+	case headAge > h.maxHeadAge:
+		check.Status = HealthStatusFailing
+		check.Reason = fmt.Sprintf("no new head seen in %s", headAge.Round(time.Second))
+	case !subscription.PrimarySubscribed || !subscription.FallbackSubscribed:
+		check.Status = HealthStatusDegraded
+		check.Reason = "subscribed to new batches through a single connection"
+	}
+	return check
+}
+
+// checkVerificationQueue looks at the proofs of the batches being processed that are not verified
+// yet, which keep growing when verification stalls or can't keep up with the batches.
+func (h *HealthChecker) checkVerificationQueue() HealthCheck {
+	backlog := h.verificationBacklog()
+	check := HealthCheck{
+		Name:    "verification_queue",
+		Status:  HealthStatusOk,
+		Details: map[string]interface{}{"backlog": backlog},
+	}
+	if h.maxVerificationBacklog > 0 && backlog > h.maxVerificationBacklog {
+		check.Status = HealthStatusDegraded
+		check.Reason = fmt.Sprintf("%d proofs waiting for verification, more than %d", backlog, h.maxVerificationBacklog)
+	}
+	return check
+}
+
+func (h *HealthChecker) checkLastProcessedBlock(subscription chainio.SubscriptionStatus) HealthCheck {
+	lastProcessedBlock := h.lastProcessedBlock()
+	check := HealthCheck{
+		Name:    "last_processed_block",
+		Status:  HealthStatusOk,
+		Details: map[string]interface{}{"block": lastProcessedBlock},
+	}
+	// The operator only records the blocks of the batches it sees, so the lag is informative
+	if lastProcessedBlock != 0 && subscription.LastHead >= lastProcessedBlock {
+		check.Details["blocks_behind_head"] = subscription.LastHead - lastProcessedBlock
+	}
+	return check
+}
+
+// checkEthEndpoints fails only if none of the endpoints is reachable, as the operator falls back between them.
+func checkEthEndpoints(ctx context.Context, name string, clients []blockNumberClient) HealthCheck {
+	check := HealthCheck{Name: name, Status: HealthStatusOk, Details: map[string]interface{}{}}
+	var unreachable []string
+	for i, client := range clients {
+		endpoint := "primary"
+		if i > 0 {
+			endpoint = "fallback"
+		}
+		if _, err := client.BlockNumber(ctx); err != nil {
+			check.Details[endpoint] = err.Error()
+			unreachable = append(unreachable, endpoint)
+			continue
+		}
+		check.Details[endpoint] = string(HealthStatusOk)
+	}
+
+	switch {
+	case len(unreachable) == len(clients):
+		check.Status = HealthStatusFailing
+		check.Reason = "no endpoint is reachable"
+	case len(unreachable) > 0:
+		check.Status = HealthStatusDegraded
+		check.Reason = fmt.Sprintf("%s endpoint is not reachable", unreachable[0])
+	}
+	return check
+}
+
+// checkAggregator probes the aggregators concurrently. It fails only if none of them is reachable,
+// as responses are sent to all of them and any one can reach quorum.
+func (h *HealthChecker) checkAggregator(ctx context.Context) HealthCheck {
+	check := HealthCheck{Name: "aggregator", Status: HealthStatusOk, Details: map[string]interface{}{}}
+	errs := make([]error, len(h.aggregatorAddrs))
+	var wg sync.WaitGroup
+	for i, aggregatorAddr := range h.aggregatorAddrs {
+		wg.Add(1)
+		go func(i int, aggregatorAddr string) {
+			defer wg.Done()
+			errs[i] = probeAggregator(ctx, aggregatorAddr)
+		}(i, aggregatorAddr)
+	}
+	wg.Wait()
+
+	var unreachable []string
+	for i, aggregatorAddr := range h.aggregatorAddrs {
+		if errs[i] != nil {
+			check.Details[aggregatorAddr] = errs[i].Error()
+			unreachable = append(unreachable, aggregatorAddr)
+			continue
+		}
+		check.Details[aggregatorAddr] = string(HealthStatusOk)
+	}
+
+	switch {
+	case len(unreachable) == len(h.aggregatorAddrs) && len(unreachable) == 1:
+		check.Status = HealthStatusFailing
+		check.Reason = fmt.Sprintf("aggregator is not reachable: %v", errs[0])
+	case len(unreachable) == len(h.aggregatorAddrs):
+		check.Status = HealthStatusFailing
+		check.Reason = "no aggregator is reachable"
+	case len(unreachable) > 0:
+		check.Status = HealthStatusDegraded
+		check.Reason = fmt.Sprintf("aggregator %s is not reachable", unreachable[0])
+	}
+	return check
+}
+
+// AggregatorAddress returns the host and port the operator reaches the aggregator at, given the
+// `aggregator_rpc_url` and `aggregator_rpc_server_ip_port_address` config fields.
+func AggregatorAddress(aggregatorRpcUrl string, aggregatorServerIpPortAddress string) string {
+	if aggregatorRpcUrl == "" {
+		return aggregatorServerIpPortAddress
+	}
+	parsed, err := url.Parse(aggregatorRpcUrl)
+	if err != nil {
+		return aggregatorRpcUrl
+	}
+	if parsed.Port() == "" {
+		port := "80"
+		if parsed.Scheme == "https" {
+			port = "443"
+		}
+		return net.JoinHostPort(parsed.Hostname(), port)
+	}
+	return parsed.Host
+}
+
+// probeAggregator checks that the aggregator RPC server accepts connections.
+func probeAggregator(ctx context.Context, aggregatorAddr string) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", aggregatorAddr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Handler serves the liveness report at /healthz and the readiness report at /readyz.
+// Both respond 503 Service Unavailable if a check is failing.
+func (h *HealthChecker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, h.Liveness())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, h.Readiness(r.Context()))
+	})
+	return mux
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == HealthStatusFailing {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// Start serves the health endpoints at ipPortAddress until ctx is done.
+func (h *HealthChecker) Start(ctx context.Context, ipPortAddress string) <-chan error {
+	errC := make(chan error, 1)
+	server := &http.Server{
+		Addr:              ipPortAddress,
+		Handler:           h.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      2 * healthProbeTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errC <- fmt.Errorf("health server failed: %w", err)
+		}
+	}()
+	return errC
+}
@@ -0,0 +1,174 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+type fakeBlockNumberClient struct {
+	err error
+}
+
+func (c *fakeBlockNumberClient) BlockNumber(context.Context) (uint64, error) {
+	return 100, c.err
+}
+
+func newTestHealthChecker(t *testing.T, subscription chainio.SubscriptionStatus) *HealthChecker {
+	aggregator, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	t.Cleanup(func() { aggregator.Close() })
+
+	return &HealthChecker{
+		subscriptionStatus:  func() chainio.SubscriptionStatus { return subscription },
+		verificationBacklog: func() int64 { return 3 },
+		lastProcessedBlock:  func() uint64 { return 90 },
+		rpcClients:          []blockNumberClient{&fakeBlockNumberClient{}, &fakeBlockNumberClient{}},
+		wsClients:           []blockNumberClient{&fakeBlockNumberClient{}, &fakeBlockNumberClient{}},
+		aggregatorAddrs:     []string{aggregator.Addr().String()},
+		maxHeadAge:          time.Minute,
+		startedAt:           time.Now(),
+	}
+}
+
+func healthySubscription() chainio.SubscriptionStatus {
+	return chainio.SubscriptionStatus{
+		PrimarySubscribed:  true,
+		FallbackSubscribed: true,
+		LastHead:           100,
+		LastHeadAt:         time.Now(),
+	}
+}
+
+func getHealthReport(t *testing.T, handler http.Handler, path string) (int, HealthReport) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	var report HealthReport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+		t.Fatalf("%s: could not decode report %q: %v", path, recorder.Body.String(), err)
+	}
+	return recorder.Code, report
+}
+
+func findHealthCheck(report HealthReport, name string) HealthCheck {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	return HealthCheck{}
+}
+
+func TestHealthEndpointsReportHealthyOperator(t *testing.T) {
+	checker := newTestHealthChecker(t, healthySubscription())
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		code, report := getHealthReport(t, checker.Handler(), path)
+		if code != http.StatusOK || report.Status != HealthStatusOk || len(report.Reasons) != 0 {
+			t.Errorf("%s: expected healthy operator, got %d %+v", path, code, report)
+		}
+	}
+
+	_, report := getHealthReport(t, checker.Handler(), "/readyz")
+	if len(report.Checks) != 6 {
+		t.Errorf("expected 6 readiness checks, got %+v", report.Checks)
+	}
+	if lag := findHealthCheck(report, "last_processed_block").Details["blocks_behind_head"]; lag != float64(10) {
+		t.Errorf("expected last processed block to be 10 blocks behind head, got %v", lag)
+	}
+}
+
+func TestHealthEndpointsReportStalledSubscription(t *testing.T) {
+	subscription := healthySubscription()
+	subscription.LastHeadAt = time.Now().Add(-2 * time.Minute)
+	checker := newTestHealthChecker(t, subscription)
+	checker.startedAt = subscription.LastHeadAt
+
+	code, report := getHealthReport(t, checker.Handler(), "/healthz")
+	if code != http.StatusServiceUnavailable || report.Status != HealthStatusFailing {
+		t.Fatalf("expected stalled operator to fail liveness, got %d %+v", code, report)
+	}
+	if len(report.Reasons) != 1 || !strings.HasPrefix(report.Reasons[0], "subscription: no new head seen") {
+		t.Errorf("unexpected reasons %v", report.Reasons)
+	}
+}
+
+func TestReadinessFailsWithUnreachableDependencies(t *testing.T) {
+	checker := newTestHealthChecker(t, healthySubscription())
+	checker.rpcClients = []blockNumberClient{&fakeBlockNumberClient{err: errors.New("connection refused")}, &fakeBlockNumberClient{}}
+	checker.wsClients = []blockNumberClient{&fakeBlockNumberClient{err: errors.New("connection refused")}, &fakeBlockNumberClient{err: errors.New("connection refused")}}
+	checker.aggregatorAddrs = []string{"127.0.0.1:1"}
+
+	code, report := getHealthReport(t, checker.Handler(), "/healthz")
+	if code != http.StatusOK {
+		t.Errorf("expected unreachable dependencies not to fail liveness, got %d %+v", code, report)
+	}
+
+	code, report = getHealthReport(t, checker.Handler(), "/readyz")
+	if code != http.StatusServiceUnavailable || report.Status != HealthStatusFailing {
+		t.Fatalf("expected readiness to fail, got %d %+v", code, report)
+	}
+	if status := findHealthCheck(report, "eth_rpc").Status; status != HealthStatusDegraded {
+		t.Errorf("expected eth_rpc to be degraded with the fallback reachable, got %s", status)
+	}
+	if len(report.Reasons) != 2 || !strings.HasPrefix(report.Reasons[0], "eth_ws:") || !strings.HasPrefix(report.Reasons[1], "aggregator:") {
+		t.Errorf("unexpected reasons %v", report.Reasons)
+	}
+}
+
+func TestHealthReportsDegradedVerificationQueue(t *testing.T) {
+	subscription := healthySubscription()
+	subscription.FallbackSubscribed = false
+	checker := newTestHealthChecker(t, subscription)
+	checker.maxVerificationBacklog = 2
+
+	code, report := getHealthReport(t, checker.Handler(), "/healthz")
+	if code != http.StatusOK || report.Status != HealthStatusDegraded {
+		t.Fatalf("expected degraded operator, got %d %+v", code, report)
+	}
+	for _, name := range []string{"subscription", "verification_queue"} {
+		if status := findHealthCheck(report, name).Status; status != HealthStatusDegraded {
+			t.Errorf("expected %s to be degraded, got %s", name, status)
+		}
+	}
+}
+
+func TestReadinessDegradedWithAggregatorDown(t *testing.T) {
+	checker := newTestHealthChecker(t, healthySubscription())
+	checker.aggregatorAddrs = append(checker.aggregatorAddrs, "127.0.0.1:1")
+
+	code, report := getHealthReport(t, checker.Handler(), "/readyz")
+	if code != http.StatusOK || report.Status != HealthStatusDegraded {
+		t.Fatalf("expected readiness to be degraded with an aggregator reachable, got %d %+v", code, report)
+	}
+	check := findHealthCheck(report, "aggregator")
+	if check.Status != HealthStatusDegraded || check.Details[checker.aggregatorAddrs[0]] != string(HealthStatusOk) {
+		t.Errorf("expected the aggregator that is down to be reported, got %+v", check)
+	}
+}
+
+func TestAggregatorAddress(t *testing.T) {
+	cases := []struct {
+		rpcUrl, legacyAddress, expected string
+	}{
+		{"", "aggregator.alignedlayer.com:8090", "aggregator.alignedlayer.com:8090"},
+		{"https://aggregator.alignedlayer.com:8091", "aggregator.alignedlayer.com:8090", "aggregator.alignedlayer.com:8091"},
+		{"https://aggregator.alignedlayer.com", "", "aggregator.alignedlayer.com:443"},
+		{"http://localhost", "", "localhost:80"},
+	}
+	for _, c := range cases {
+		if address := AggregatorAddress(c.rpcUrl, c.legacyAddress); address != c.expected {
+			t.Errorf("expected %q for %q, got %q", c.expected, c.rpcUrl, address)
+		}
+	}
+}
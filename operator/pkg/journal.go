@@ -0,0 +1,435 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+// BatchStage is a step of the lifecycle of a batch in the operator.
+type BatchStage string
+
+const (
+	BatchSeen       BatchStage = "seen"
+	BatchDownloaded BatchStage = "downloaded"
+	BatchVerified   BatchStage = "verified"
+	BatchSigned     BatchStage = "signed"
+	// Final stages, nothing is left to do for the batch
+	BatchRejected  BatchStage = "rejected"
+	BatchDelivered BatchStage = "delivered"
+	// The batch was responded on chain before the operator delivered its response
+	BatchResponded BatchStage = "responded"
+	// The aggregator rejected the response, or didn't acknowledge it within the response window
+	BatchAbandoned BatchStage = "abandoned"
+
+	// Compacted journals start with a checkpoint keeping the last seen block
+	journalCheckpoint BatchStage = "checkpoint"
+)
+
+// DefaultJournalCompactionThreshold is the number of records after which the journal is compacted.
+const DefaultJournalCompactionThreshold = 10000
+
+func (s BatchStage) IsFinal() bool {
+	return s == BatchRejected || s == BatchDelivered || s == BatchResponded || s == BatchAbandoned
+}
+
+// JournaledBatch holds the fields of a NewBatch event needed to process the batch again after a restart.
+type JournaledBatch struct {
+	EventVersion          int               `json:"event_version"`
+	BatchMerkleRoot       ethcommon.Hash    `json:"batch_merkle_root"`
+	SenderAddress         ethcommon.Address `json:"sender_address"`
+	TaskCreatedBlock      uint32            `json:"task_created_block"`
+	BatchDataPointer      string            `json:"batch_data_pointer"`
+	RespondToTaskFeeLimit *big.Int          `json:"respond_to_task_fee_limit,omitempty"`
+	BlockNumber           uint64            `json:"block_number"`
+}
+
+func JournaledBatchFromNewBatch(newBatch *chainio.NewBatch) JournaledBatch {
+	return JournaledBatch{
+		EventVersion:          int(newBatch.EventVersion),
+		BatchMerkleRoot:       newBatch.BatchMerkleRoot,
+		SenderAddress:         newBatch.SenderAddress,
+		TaskCreatedBlock:      newBatch.TaskCreatedBlock,
+		BatchDataPointer:      newBatch.BatchDataPointer,
+		RespondToTaskFeeLimit: newBatch.RespondToTaskFeeLimit,
+		BlockNumber:           newBatch.Raw.BlockNumber,
+	}
+}
+
+func (b JournaledBatch) ToNewBatch() *chainio.NewBatch {
+	newBatch := &chainio.NewBatch{
+		BatchMerkleRoot:       b.BatchMerkleRoot,
+		SenderAddress:         b.SenderAddress,
+		BatchDataPointer:      b.BatchDataPointer,
+		TaskCreatedBlock:      b.TaskCreatedBlock,
+		RespondToTaskFeeLimit: b.RespondToTaskFeeLimit,
+		EventVersion:          uint8(b.EventVersion),
+	}
+	newBatch.Raw.BlockNumber = b.BlockNumber
+	return newBatch
+}
+
+// BatchIdentifierHash returns the hex encoded keccak256 of the merkle root and the sender address,
+// which is how the journal identifies batches.
+func (b JournaledBatch) BatchIdentifierHash() string {
+	return BatchIdentifierHashHex(b.BatchMerkleRoot, b.SenderAddress)
+}
+
+func BatchIdentifierHashHex(batchMerkleRoot [32]byte, senderAddress [20]byte) string {
+	batchIdentifierHash := batchIdentifierHash(batchMerkleRoot, senderAddress)
+	return "0x" + hex.EncodeToString(batchIdentifierHash[:])
+}
+
+func batchIdentifierHash(batchMerkleRoot [32]byte, senderAddress [20]byte) [32]byte {
+	batchIdentifier := append(batchMerkleRoot[:], senderAddress[:]...)
+	return *(*[32]byte)(crypto.Keccak256(batchIdentifier))
+}
+
+// journalRecord is a line of the journal file.
+type journalRecord struct {
+	BatchIdentifierHash string     `json:"batch,omitempty"`
+	Stage               BatchStage `json:"stage"`
+	Time                time.Time  `json:"time"`
+	// Only set on seen records
+	Batch *JournaledBatch `json:"event,omitempty"`
+	// Only set on checkpoint records
+	LastSeenBlock uint64 `json:"last_seen_block,omitempty"`
+}
+
+type journalEntry struct {
+	batch JournaledBatch
+	stage BatchStage
+}
+
+// BatchJournal is a durable, append-only log of the lifecycle of every batch the operator handles.
+// Each record is a JSON line appended and synced to disk before the call recording it returns,
+// so after a crash the journal tells exactly which batches still need a response.
+// A partially written last line, left by a crash in the middle of a write, is discarded on open.
+// Once the file has grown past the compaction threshold it is atomically rewritten
+// keeping only the batches that are still pending.
+type BatchJournal struct {
+	path                string
+	compactionThreshold int
+	mutex               sync.Mutex
+	file                *os.File
+	records             int
+	entries             map[string]*journalEntry
+	lastSeenBlock       uint64
+	// Read only journals don't discard partial records, as the operator may be writing them
+	readOnly bool
+}
+
+// OpenBatchJournal opens the journal at path, creating it if it doesn't exist.
+func OpenBatchJournal(path string, compactionThreshold int) (*BatchJournal, error) {
+	if compactionThreshold <= 0 {
+		compactionThreshold = DefaultJournalCompactionThreshold
+	}
+	journal := &BatchJournal{
+		path:                path,
+		compactionThreshold: compactionThreshold,
+		entries:             make(map[string]*journalEntry),
+	}
+
+	if err := journal.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open batch journal: %w", err)
+	}
+	journal.file = file
+	return journal, nil
+}
+
+// ReadBatchJournal reads the journal at path without opening it for writing, so it can be
+// inspected while the operator is running. The returned journal can't record anything.
+func ReadBatchJournal(path string) (*BatchJournal, error) {
+	journal := &BatchJournal{
+		path:     path,
+		readOnly: true,
+		entries:  make(map[string]*journalEntry),
+	}
+	if err := journal.load(); err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// BatchJournalPath returns where the journal is kept given the `batch_journal_filepath` and
+// `last_processed_batch_filepath` config fields. Operators configured before the batch journal
+// existed keep it next to their last processed batch file.
+func BatchJournalPath(batchJournalFile string, lastProcessedBatchFile string) string {
+	if batchJournalFile == "" && lastProcessedBatchFile != "" {
+		return strings.TrimSuffix(lastProcessedBatchFile, ".json") + ".journal"
+	}
+	return batchJournalFile
+}
+
+func (j *BatchJournal) load() error {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read batch journal: %w", err)
+	}
+
+	validLength := 0
+	for len(data[validLength:]) > 0 {
+		lineLength := bytes.IndexByte(data[validLength:], '\n')
+		if lineLength < 0 {
+			// Partially written record, the operator crashed while appending it
+			break
+		}
+		var record journalRecord
+		if err := json.Unmarshal(data[validLength:validLength+lineLength], &record); err != nil {
+			return fmt.Errorf("corrupted batch journal record at offset %d: %w", validLength, err)
+		}
+		j.apply(record)
+		j.records++
+		validLength += lineLength + 1
+	}
+
+	if validLength < len(data) && !j.readOnly {
+		if err := os.Truncate(j.path, int64(validLength)); err != nil {
+			return fmt.Errorf("could not discard partial batch journal record: %w", err)
+		}
+	}
+	return nil
+}
+
+func (j *BatchJournal) apply(record journalRecord) {
+	switch {
+	case record.Stage == journalCheckpoint:
+		j.lastSeenBlock = max(j.lastSeenBlock, record.LastSeenBlock)
+	case record.Batch != nil:
+		j.entries[record.BatchIdentifierHash] = &journalEntry{batch: *record.Batch, stage: record.Stage}
+		j.lastSeenBlock = max(j.lastSeenBlock, record.Batch.BlockNumber)
+	default:
+		if entry, ok := j.entries[record.BatchIdentifierHash]; ok {
+			entry.stage = record.Stage
+		}
+	}
+}
+
+// RecordSeen journals a new batch and returns its stage. If the batch was already
+// journaled nothing is written and the stage it reached is returned instead.
+func (j *BatchJournal) RecordSeen(batch JournaledBatch) (BatchStage, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	batchIdentifierHash := batch.BatchIdentifierHash()
+	if entry, ok := j.entries[batchIdentifierHash]; ok {
+		return entry.stage, nil
+	}
+	return BatchSeen, j.append(journalRecord{BatchIdentifierHash: batchIdentifierHash, Stage: BatchSeen, Batch: &batch})
+}
+
+// Record journals that the batch reached stage. Batches that were never seen are ignored.
+func (j *BatchJournal) Record(batchIdentifierHash string, stage BatchStage) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if _, ok := j.entries[batchIdentifierHash]; !ok {
+		return nil
+	}
+	return j.append(journalRecord{BatchIdentifierHash: batchIdentifierHash, Stage: stage})
+}
+
+// Checkpoint raises the last seen block, without any batch being seen there.
+func (j *BatchJournal) Checkpoint(lastSeenBlock uint64) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.append(journalRecord{Stage: journalCheckpoint, LastSeenBlock: lastSeenBlock})
+}
+
+// Stage returns the last stage the batch reached, if it is in the journal.
+func (j *BatchJournal) Stage(batchIdentifierHash string) (BatchStage, bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entry, ok := j.entries[batchIdentifierHash]
+	if !ok {
+		return "", false
+	}
+	return entry.stage, true
+}
+
+// Batch returns the journaled batch and the last stage it reached, if it is in the journal.
+func (j *BatchJournal) Batch(batchIdentifierHash string) (JournaledBatch, BatchStage, bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entry, ok := j.entries[batchIdentifierHash]
+	if !ok {
+		return JournaledBatch{}, "", false
+	}
+	return entry.batch, entry.stage, true
+}
+
+// PendingBatch is a journaled batch whose response was never delivered.
+type PendingBatch struct {
+	Batch JournaledBatch
+	Stage BatchStage
+}
+
+// Pending returns the batches that did not reach a final stage, oldest first.
+func (j *BatchJournal) Pending() []PendingBatch {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	var pending []PendingBatch
+	for _, entry := range j.entries {
+		if !entry.stage.IsFinal() {
+			pending = append(pending, PendingBatch{Batch: entry.batch, Stage: entry.stage})
+		}
+	}
+	sort.Slice(pending, func(a, b int) bool {
+		return pending[a].Batch.BlockNumber < pending[b].Batch.BlockNumber
+	})
+	return pending
+}
+
+// LastSeenBlock returns the highest block where a batch was seen. Zero if no batch was ever seen.
+func (j *BatchJournal) LastSeenBlock() uint64 {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.lastSeenBlock
+}
+
+// Close flushes and closes the journal file.
+func (j *BatchJournal) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.readOnly {
+		return nil
+	}
+	return j.file.Close()
+}
+
+// append writes the record and syncs it to disk. Must be called with the mutex held.
+func (j *BatchJournal) append(record journalRecord) error {
+	if j.readOnly {
+		return errors.New("batch journal is read only")
+	}
+	record.Time = time.Now()
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not marshal batch journal record: %w", err)
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("could not write batch journal record: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("could not sync batch journal: %w", err)
+	}
+	j.apply(record)
+	j.records++
+
+	// Only compact if it shrinks the journal noticeably, otherwise every record would trigger it
+	if j.records >= j.compactionThreshold && j.records > 2*j.retainedEntries() {
+		return j.compact()
+	}
+	return nil
+}
+
+// retainedEntries returns the number of batches kept on compaction. Must be called with the mutex held.
+func (j *BatchJournal) retainedEntries() int {
+	retained := 0
+	for _, entry := range j.entries {
+		if j.retained(entry) {
+			retained++
+		}
+	}
+	return retained
+}
+
+// retained reports whether the batch is kept on compaction: it is still pending, or it is final
+// but was seen in the last seen block, so it could still be found by a scan starting there.
+func (j *BatchJournal) retained(entry *journalEntry) bool {
+	return !entry.stage.IsFinal() || entry.batch.BlockNumber >= j.lastSeenBlock
+}
+
+// compact rewrites the journal with a checkpoint and the retained batches. Must be called with the mutex held.
+func (j *BatchJournal) compact() error {
+	records := []journalRecord{{Stage: journalCheckpoint, LastSeenBlock: j.lastSeenBlock}}
+	for batchIdentifierHash, entry := range j.entries {
+		if !j.retained(entry) {
+			delete(j.entries, batchIdentifierHash)
+			continue
+		}
+		batch := entry.batch
+		records = append(records, journalRecord{BatchIdentifierHash: batchIdentifierHash, Stage: entry.stage, Batch: &batch})
+	}
+
+	var buffer bytes.Buffer
+	now := time.Now()
+	for _, record := range records {
+		record.Time = now
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("could not marshal batch journal record: %w", err)
+		}
+		buffer.Write(append(line, '\n'))
+	}
+
+	if err := writeFileAtomic(j.path, buffer.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("could not compact batch journal: %w", err)
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not reopen batch journal after compaction: %w", err)
+	}
+	j.file.Close()
+	j.file = file
+	j.records = len(records)
+	return nil
+}
+
+// MigrateLastProcessedBatch seeds an empty journal from the last processed batch file used before the journal.
+// That file only kept the highest block with a verified batch and, as batches are verified in parallel,
+// the journal starts UnverifiedBatchOffset blocks earlier so the batches that may have been left
+// unverified are processed on the next scan for missed batches.
+func MigrateLastProcessedBatch(journal *BatchJournal, lastProcessedBatchFile string) error {
+	if lastProcessedBatchFile == "" || journal.LastSeenBlock() != 0 {
+		return nil
+	}
+
+	file, err := os.ReadFile(lastProcessedBatchFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var lastProcessedBatch struct {
+		BlockNumber uint32 `json:"block_number"`
+	}
+	if err := json.Unmarshal(file, &lastProcessedBatch); err != nil {
+		return err
+	}
+
+	// this check is necessary for overflows as go does not do saturating arithmetic
+	if lastProcessedBatch.BlockNumber <= UnverifiedBatchOffset {
+		// Zero would mean no batch was ever seen
+		return journal.Checkpoint(1)
+	}
+	return journal.Checkpoint(uint64(lastProcessedBatch.BlockNumber - UnverifiedBatchOffset))
+}
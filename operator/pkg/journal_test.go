@@ -0,0 +1,135 @@
+package operator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func journaledBatch(i byte, blockNumber uint64) JournaledBatch {
+	return JournaledBatch{
+		EventVersion:     3,
+		BatchMerkleRoot:  [32]byte{i},
+		SenderAddress:    [20]byte{i},
+		BatchDataPointer: "https://storage.alignedlayer.com/batch",
+		BlockNumber:      blockNumber,
+	}
+}
+
+func TestBatchJournalResumesUndeliveredBatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operator.batch_journal")
+	journal, err := OpenBatchJournal(path, 0)
+	if err != nil {
+		t.Fatalf("could not open journal: %v", err)
+	}
+
+	stages := [][]BatchStage{
+		{BatchDownloaded, BatchVerified, BatchSigned, BatchDelivered},
+		{BatchDownloaded, BatchRejected},
+		{BatchDownloaded, BatchVerified},
+		{},
+	}
+	for i, batchStages := range stages {
+		batch := journaledBatch(byte(i), uint64(10+i))
+		if _, err := journal.RecordSeen(batch); err != nil {
+			t.Fatalf("could not journal batch: %v", err)
+		}
+		for _, stage := range batchStages {
+			if err := journal.Record(batch.BatchIdentifierHash(), stage); err != nil {
+				t.Fatalf("could not journal stage %s: %v", stage, err)
+			}
+		}
+	}
+	journal.Close()
+
+	// Simulate a crash while appending a record
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("could not open journal file: %v", err)
+	}
+	file.WriteString(`{"batch":"0x12","sta`)
+	file.Close()
+
+	journal, err = OpenBatchJournal(path, 0)
+	if err != nil {
+		t.Fatalf("could not reopen journal: %v", err)
+	}
+	defer journal.Close()
+
+	pending := journal.Pending()
+	if len(pending) != 2 || pending[0].Stage != BatchVerified || pending[1].Stage != BatchSeen {
+		t.Fatalf("expected batches 2 and 3 to be pending, got %+v", pending)
+	}
+	if pending[0].Batch.BatchIdentifierHash() != journaledBatch(2, 12).BatchIdentifierHash() {
+		t.Errorf("unexpected pending batch %+v", pending[0].Batch)
+	}
+	if block := journal.LastSeenBlock(); block != 13 {
+		t.Errorf("expected last seen block 13, got %d", block)
+	}
+	if stage, err := journal.RecordSeen(journaledBatch(0, 10)); err != nil || stage != BatchDelivered {
+		t.Errorf("expected delivered batch to keep its stage, got %s (%v)", stage, err)
+	}
+	unseen := journaledBatch(9, 19).BatchIdentifierHash()
+	if err := journal.Record(unseen, BatchVerified); err != nil {
+		t.Errorf("expected stages of unseen batches to be ignored, got %v", err)
+	}
+	if _, ok := journal.Stage(unseen); ok {
+		t.Errorf("expected unseen batch not to be journaled")
+	}
+}
+
+func TestBatchJournalCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operator.batch_journal")
+	journal, err := OpenBatchJournal(path, 10)
+	if err != nil {
+		t.Fatalf("could not open journal: %v", err)
+	}
+
+	for i := byte(0); i < 10; i++ {
+		batch := journaledBatch(i, uint64(i)+1)
+		journal.RecordSeen(batch)
+		if i != 4 {
+			journal.Record(batch.BatchIdentifierHash(), BatchDelivered)
+		}
+	}
+	journal.Close()
+
+	if journal.records >= 10 {
+		t.Errorf("expected journal to be compacted, it has %d records", journal.records)
+	}
+
+	journal, err = OpenBatchJournal(path, 10)
+	if err != nil {
+		t.Fatalf("could not reopen journal: %v", err)
+	}
+	defer journal.Close()
+
+	pending := journal.Pending()
+	if len(pending) != 1 || pending[0].Batch.BlockNumber != 5 {
+		t.Errorf("expected only the batch of block 5 to be pending, got %+v", pending)
+	}
+	if block := journal.LastSeenBlock(); block != 10 {
+		t.Errorf("expected last seen block 10 to survive compaction, got %d", block)
+	}
+}
+
+func TestMigrateLastProcessedBatch(t *testing.T) {
+	dir := t.TempDir()
+	lastProcessedBatchFile := filepath.Join(dir, "operator.last_processed_batch.json")
+	if err := os.WriteFile(lastProcessedBatchFile, []byte(`{"block_number":1500}`), 0o644); err != nil {
+		t.Fatalf("could not write last processed batch file: %v", err)
+	}
+
+	journal, err := OpenBatchJournal(filepath.Join(dir, "operator.batch_journal"), 0)
+	if err != nil {
+		t.Fatalf("could not open journal: %v", err)
+	}
+	defer journal.Close()
+
+	if err := MigrateLastProcessedBatch(journal, lastProcessedBatchFile); err != nil {
+		t.Fatalf("could not migrate: %v", err)
+	}
+	if block := journal.LastSeenBlock(); block != 1500-UnverifiedBatchOffset {
+		t.Errorf("expected last seen block %d, got %d", 1500-UnverifiedBatchOffset, block)
+	}
+}
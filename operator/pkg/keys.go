@@ -0,0 +1,177 @@
+package operator
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	ecdsa2 "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeyType is the kind of key a keystore holds.
+type KeyType string
+
+const (
+	// BLS keys sign the task responses, and identify the operator in the AVS
+	BlsKeyType KeyType = "bls"
+	// ECDSA keys sign the transactions of the operator
+	EcdsaKeyType KeyType = "ecdsa"
+)
+
+var (
+	ErrUnknownKeyType = errors.New("unknown key type")
+	ErrKeystoreExists = errors.New("keystore already exists")
+	ErrInvalidKey     = errors.New("invalid private key")
+	// Keystores are encrypted with the password, so an empty one would leave the key unprotected
+	ErrEmptyPassword = errors.New("empty keystore password")
+)
+
+func ParseKeyType(keyType string) (KeyType, error) {
+	switch KeyType(strings.ToLower(keyType)) {
+	case BlsKeyType:
+		return BlsKeyType, nil
+	case EcdsaKeyType:
+		return EcdsaKeyType, nil
+	}
+	return "", fmt.Errorf("%w: %q, expected %q or %q", ErrUnknownKeyType, keyType, BlsKeyType, EcdsaKeyType)
+}
+
+// PublicKeyInfo is the public part of a keystore. BLS keystores have the G1 and G2 public keys
+// and the operator ID derived from them, ECDSA keystores the public key and address.
+type PublicKeyInfo struct {
+	KeyType     KeyType            `json:"key_type"`
+	Keystore    string             `json:"keystore"`
+	PublicKeyG1 string             `json:"public_key_g1,omitempty"`
+	PublicKeyG2 string             `json:"public_key_g2,omitempty"`
+	OperatorId  string             `json:"operator_id,omitempty"`
+	PublicKey   string             `json:"public_key,omitempty"`
+	Address     *ethcommon.Address `json:"address,omitempty"`
+}
+
+// GenerateKeystore generates a random key and writes it to a new keystore at path, encrypted with password.
+// The keystores can be read with bls.ReadPrivateKeyFromFile and ecdsa.ReadKey from the EigenLayer SDK,
+// like the ones the EigenLayer CLI creates.
+func GenerateKeystore(keyType KeyType, path string, password string) (*PublicKeyInfo, error) {
+	switch keyType {
+	case BlsKeyType:
+		keyPair, err := bls.GenRandomBlsKeys()
+		if err != nil {
+			return nil, err
+		}
+		return writeBlsKeystore(keyPair, path, password)
+	case EcdsaKeyType:
+		privateKey, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		return writeEcdsaKeystore(privateKey, path, password)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKeyType, keyType)
+}
+
+// ImportKeystore writes privateKey to a new keystore at path, encrypted with password. BLS private keys
+// are decimal or 0x prefixed hex field elements, as the EigenLayer CLI prints them, and ECDSA private keys are hex.
+func ImportKeystore(keyType KeyType, privateKey string, path string, password string) (*PublicKeyInfo, error) {
+	privateKey = strings.TrimSpace(privateKey)
+	switch keyType {
+	case BlsKeyType:
+		keyPair, err := bls.NewKeyPairFromString(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		if keyPair.PrivKey.IsZero() {
+			return nil, fmt.Errorf("%w: zero BLS key", ErrInvalidKey)
+		}
+		return writeBlsKeystore(keyPair, path, password)
+	case EcdsaKeyType:
+		ecdsaKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		return writeEcdsaKeystore(ecdsaKey, path, password)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKeyType, keyType)
+}
+
+// ReadPublicKeyInfo decrypts the keystore at path to derive its public keys.
+func ReadPublicKeyInfo(keyType KeyType, path string, password string) (*PublicKeyInfo, error) {
+	switch keyType {
+	case BlsKeyType:
+		keyPair, err := bls.ReadPrivateKeyFromFile(path, password)
+		if err != nil {
+			return nil, fmt.Errorf("could not read BLS keystore %s: %w", path, err)
+		}
+		return blsPublicKeyInfo(keyPair, path), nil
+	case EcdsaKeyType:
+		privateKey, err := ecdsa2.ReadKey(path, password)
+		if err != nil {
+			return nil, fmt.Errorf("could not read ECDSA keystore %s: %w", path, err)
+		}
+		return ecdsaPublicKeyInfo(privateKey, path), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKeyType, keyType)
+}
+
+func writeBlsKeystore(keyPair *bls.KeyPair, path string, password string) (*PublicKeyInfo, error) {
+	if err := checkNewKeystore(path, password); err != nil {
+		return nil, err
+	}
+	if err := keyPair.SaveToFile(path, password); err != nil {
+		return nil, fmt.Errorf("could not write BLS keystore %s: %w", path, err)
+	}
+	return blsPublicKeyInfo(keyPair, path), nil
+}
+
+func writeEcdsaKeystore(privateKey *ecdsa.PrivateKey, path string, password string) (*PublicKeyInfo, error) {
+	if err := checkNewKeystore(path, password); err != nil {
+		return nil, err
+	}
+	if err := ecdsa2.WriteKey(path, privateKey, password); err != nil {
+		return nil, fmt.Errorf("could not write ECDSA keystore %s: %w", path, err)
+	}
+	// WriteKey creates the file with the default permissions, the key is only for its owner
+	if err := os.Chmod(path, 0o600); err != nil {
+		return nil, err
+	}
+	return ecdsaPublicKeyInfo(privateKey, path), nil
+}
+
+// checkNewKeystore makes sure no keystore is overwritten, as losing a key can't be undone.
+func checkNewKeystore(path string, password string) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%w: %s", ErrKeystoreExists, path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func blsPublicKeyInfo(keyPair *bls.KeyPair, path string) *PublicKeyInfo {
+	operatorId := eigentypes.OperatorIdFromKeyPair(keyPair)
+	return &PublicKeyInfo{
+		KeyType:     BlsKeyType,
+		Keystore:    path,
+		PublicKeyG1: keyPair.GetPubKeyG1().String(),
+		PublicKeyG2: keyPair.GetPubKeyG2().String(),
+		OperatorId:  "0x" + ethcommon.Bytes2Hex(operatorId[:]),
+	}
+}
+
+func ecdsaPublicKeyInfo(privateKey *ecdsa.PrivateKey, path string) *PublicKeyInfo {
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	return &PublicKeyInfo{
+		KeyType:   EcdsaKeyType,
+		Keystore:  path,
+		PublicKey: "0x" + ethcommon.Bytes2Hex(crypto.FromECDSAPub(&privateKey.PublicKey)),
+		Address:   &address,
+	}
+}
@@ -0,0 +1,111 @@
+package operator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	ecdsa2 "github.com/Layr-Labs/eigensdk-go/crypto/ecdsa"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testKeystorePassword = "keystore-password"
+
+func TestGeneratedKeystoresAreReadableBySdk(t *testing.T) {
+	dir := t.TempDir()
+
+	blsPath := filepath.Join(dir, "keys", "operator.bls.key.json")
+	blsInfo, err := GenerateKeystore(BlsKeyType, blsPath, testKeystorePassword)
+	if err != nil {
+		t.Fatalf("could not generate BLS keystore: %v", err)
+	}
+	keyPair, err := bls.ReadPrivateKeyFromFile(blsPath, testKeystorePassword)
+	if err != nil {
+		t.Fatalf("could not read generated BLS keystore: %v", err)
+	}
+	operatorId := eigentypes.OperatorIdFromKeyPair(keyPair)
+	if blsInfo.OperatorId != "0x"+ethcommon.Bytes2Hex(operatorId[:]) {
+		t.Errorf("expected operator id of the generated key, got %s", blsInfo.OperatorId)
+	}
+
+	ecdsaPath := filepath.Join(dir, "keys", "operator.ecdsa.key.json")
+	ecdsaInfo, err := GenerateKeystore(EcdsaKeyType, ecdsaPath, testKeystorePassword)
+	if err != nil {
+		t.Fatalf("could not generate ECDSA keystore: %v", err)
+	}
+	privateKey, err := ecdsa2.ReadKey(ecdsaPath, testKeystorePassword)
+	if err != nil {
+		t.Fatalf("could not read generated ECDSA keystore: %v", err)
+	}
+	if *ecdsaInfo.Address != crypto.PubkeyToAddress(privateKey.PublicKey) {
+		t.Errorf("expected address of the generated key, got %s", ecdsaInfo.Address)
+	}
+
+	for _, path := range []string{blsPath, ecdsaPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("could not stat keystore: %v", err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("expected keystore %s to only be accessible by its owner, got %s", path, info.Mode().Perm())
+		}
+	}
+}
+
+func TestImportKeystore(t *testing.T) {
+	dir := t.TempDir()
+
+	ecdsaPath := filepath.Join(dir, "operator.ecdsa.key.json")
+	ecdsaInfo, err := ImportKeystore(EcdsaKeyType, "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80\n", ecdsaPath, testKeystorePassword)
+	if err != nil {
+		t.Fatalf("could not import ECDSA key: %v", err)
+	}
+	if *ecdsaInfo.Address != ethcommon.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266") {
+		t.Errorf("unexpected address of imported key %s", ecdsaInfo.Address)
+	}
+
+	blsPath := filepath.Join(dir, "operator.bls.key.json")
+	if _, err := ImportKeystore(BlsKeyType, "12345", blsPath, testKeystorePassword); err != nil {
+		t.Fatalf("could not import BLS key: %v", err)
+	}
+	expected, _ := bls.NewKeyPairFromString("12345")
+	blsInfo, err := ReadPublicKeyInfo(BlsKeyType, blsPath, testKeystorePassword)
+	if err != nil {
+		t.Fatalf("could not read imported BLS keystore: %v", err)
+	}
+	expectedOperatorId := eigentypes.OperatorIdFromKeyPair(expected)
+	if blsInfo.OperatorId != "0x"+ethcommon.Bytes2Hex(expectedOperatorId[:]) || blsInfo.PublicKeyG1 != expected.GetPubKeyG1().String() {
+		t.Errorf("imported BLS keystore doesn't hold the imported key: %+v", blsInfo)
+	}
+
+	if _, err := ImportKeystore(BlsKeyType, "0", filepath.Join(dir, "zero.bls.key.json"), testKeystorePassword); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected zero BLS key to be rejected, got %v", err)
+	}
+	if _, err := ImportKeystore(EcdsaKeyType, "not a key", filepath.Join(dir, "invalid.ecdsa.key.json"), testKeystorePassword); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected invalid ECDSA key to be rejected, got %v", err)
+	}
+}
+
+func TestKeystoresAreNeverOverwritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operator.ecdsa.key.json")
+	if err := os.WriteFile(path, []byte("existing key"), 0o600); err != nil {
+		t.Fatalf("could not write keystore: %v", err)
+	}
+
+	if _, err := GenerateKeystore(EcdsaKeyType, path, testKeystorePassword); !errors.Is(err, ErrKeystoreExists) {
+		t.Errorf("expected existing keystore not to be overwritten, got %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "existing key" {
+		t.Errorf("existing keystore was modified")
+	}
+	if _, err := GenerateKeystore(BlsKeyType, filepath.Join(t.TempDir(), "new.json"), ""); !errors.Is(err, ErrEmptyPassword) {
+		t.Errorf("expected keystore without password to be rejected, got %v", err)
+	}
+	if _, err := ParseKeyType("rsa"); !errors.Is(err, ErrUnknownKeyType) {
+		t.Errorf("expected unknown key type to be rejected, got %v", err)
+	}
+}
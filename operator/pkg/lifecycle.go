@@ -0,0 +1,177 @@
+package operator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+var (
+	ErrNotConfirmed      = errors.New("not confirmed")
+	ErrTransactionFailed = errors.New("transaction failed")
+)
+
+// OperatorChainWriter is the part of the AvsWriter that changes the registration of the operator.
+type OperatorChainWriter interface {
+	DeregisterOperator(ctx context.Context, quorumNumbers eigentypes.QuorumNums, pubkey regcoord.BN254G1Point, waitForReceipt bool) (*types.Receipt, error)
+	UpdateSocket(ctx context.Context, socket eigentypes.Socket, waitForReceipt bool) (*types.Receipt, error)
+	UpdateMetadataURI(ctx context.Context, uri string, waitForReceipt bool) (*types.Receipt, error)
+}
+
+// OperatorChange is a change of the registration of the operator, made with SendOperatorChange.
+type OperatorChange struct {
+	// Description is what the operator is asked to confirm
+	Description string
+	send        func(ctx context.Context, writer OperatorChainWriter) (*types.Receipt, error)
+}
+
+func DeregisterOperatorChange(operatorAddress ethcommon.Address, blsPubkey *bls.G1Point, quorumNumbers eigentypes.QuorumNums) OperatorChange {
+	return OperatorChange{
+		Description: fmt.Sprintf("Deregister operator %s from quorums %v of Aligned Layer", operatorAddress, quorumNumbers),
+		send: func(ctx context.Context, writer OperatorChainWriter) (*types.Receipt, error) {
+			return writer.DeregisterOperator(ctx, quorumNumbers, chainioutils.ConvertToBN254G1Point(blsPubkey), true)
+		},
+	}
+}
+
+func UpdateSocketChange(operatorAddress ethcommon.Address, socket string) OperatorChange {
+	return OperatorChange{
+		Description: fmt.Sprintf("Update the socket of operator %s to %q", operatorAddress, socket),
+		send: func(ctx context.Context, writer OperatorChainWriter) (*types.Receipt, error) {
+			return writer.UpdateSocket(ctx, eigentypes.Socket(socket), true)
+		},
+	}
+}
+
+func UpdateMetadataURIChange(operatorAddress ethcommon.Address, uri string) OperatorChange {
+	return OperatorChange{
+		Description: fmt.Sprintf("Update the metadata URI of operator %s to %q", operatorAddress, uri),
+		send: func(ctx context.Context, writer OperatorChainWriter) (*types.Receipt, error) {
+			return writer.UpdateMetadataURI(ctx, uri, true)
+		},
+	}
+}
+
+type OperatorChangeOptions struct {
+	// DryRun is the TxManager the writer was built with for a dry run. Its transactions are
+	// printed instead of being sent, and no confirmation is asked for.
+	DryRun *chainio.DryRunTxManager
+	// AssumeYes skips the confirmation prompt
+	AssumeYes bool
+	In        io.Reader
+	Out       io.Writer
+}
+
+// SendOperatorChange asks the operator to confirm change and sends it with writer, waiting for
+// its receipt. It fails with ErrNotConfirmed if the operator doesn't confirm the change.
+func SendOperatorChange(ctx context.Context, writer OperatorChainWriter, change OperatorChange, options OperatorChangeOptions) (*types.Receipt, error) {
+	if options.DryRun == nil && !options.AssumeYes {
+		confirmed, err := confirm(options.In, options.Out, change.Description+"?")
+		if err != nil {
+			return nil, err
+		}
+		if !confirmed {
+			return nil, ErrNotConfirmed
+		}
+	}
+
+	receipt, err := change.send(ctx, writer)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.DryRun != nil {
+		fmt.Fprintf(options.Out, "Dry run: %s\n", change.Description)
+		for _, tx := range options.DryRun.Transactions() {
+			PrintDryRunTransaction(options.Out, options.DryRun.Sender(), tx)
+		}
+		return receipt, nil
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("%w: %s reverted in block %s", ErrTransactionFailed, receipt.TxHash.Hex(), receipt.BlockNumber)
+	}
+	fmt.Fprintf(options.Out, "%s: transaction %s included in block %s\n", change.Description, receipt.TxHash.Hex(), receipt.BlockNumber)
+	return receipt, nil
+}
+
+// confirm asks question on out and reads the answer from in. Anything but yes, including
+// the end of in, is a no.
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// PrintDryRunTransaction prints tx as it would be sent by from, naming the contract method it calls.
+func PrintDryRunTransaction(out io.Writer, from ethcommon.Address, tx *types.Transaction) {
+	fmt.Fprintf(out, "  From:                     %s\n", from.Hex())
+	if tx.To() != nil {
+		fmt.Fprintf(out, "  To:                       %s\n", tx.To().Hex())
+	}
+	if method := operatorTxMethod(tx.Data()); method != "" {
+		fmt.Fprintf(out, "  Method:                   %s\n", method)
+	}
+	fmt.Fprintf(out, "  Nonce:                    %d\n", tx.Nonce())
+	fmt.Fprintf(out, "  Gas limit:                %d\n", tx.Gas())
+	fmt.Fprintf(out, "  Max fee per gas:          %s wei\n", tx.GasFeeCap())
+	fmt.Fprintf(out, "  Max priority fee per gas: %s wei\n", tx.GasTipCap())
+	fmt.Fprintf(out, "  Value:                    %s wei\n", tx.Value())
+	fmt.Fprintf(out, "  Data:                     0x%s\n", ethcommon.Bytes2Hex(tx.Data()))
+}
+
+// operatorTxMethod decodes the signature of the method called with data, among the contracts
+// the operator changes its registration in.
+func operatorTxMethod(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	for _, metaData := range []*bind.MetaData{
+		regcoord.ContractRegistryCoordinatorMetaData,
+		delegationmanager.ContractDelegationManagerMetaData,
+	} {
+		contractAbi, err := metaData.GetAbi()
+		if err != nil {
+			continue
+		}
+		method, err := contractAbi.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			return method.Sig
+		}
+		return formatMethodCall(method, args)
+	}
+	return ""
+}
+
+func formatMethodCall(method *abi.Method, args []interface{}) string {
+	formattedArgs := make([]string, len(args))
+	for i, arg := range args {
+		if bytes, ok := arg.([]byte); ok {
+			formattedArgs[i] = "0x" + ethcommon.Bytes2Hex(bytes)
+			continue
+		}
+		formattedArgs[i] = fmt.Sprintf("%v", arg)
+	}
+	return fmt.Sprintf("%s(%s)", method.RawName, strings.Join(formattedArgs, ", "))
+}
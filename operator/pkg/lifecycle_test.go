@@ -0,0 +1,135 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+var (
+	testOperatorAddress            = ethcommon.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	testRegistryCoordinatorAddress = ethcommon.HexToAddress("0x851356ae760d987E095750cCeb3bC6014560891C")
+)
+
+// fakeOperatorChainWriter builds the registry coordinator transactions as the AVS registry
+// ChainWriter does, and hands them to txMgr.
+type fakeOperatorChainWriter struct {
+	txMgr   txmgr.TxManager
+	receipt *types.Receipt
+	calls   []string
+}
+
+func (w *fakeOperatorChainWriter) send(ctx context.Context, method string, args ...interface{}) (*types.Receipt, error) {
+	w.calls = append(w.calls, method)
+	if w.txMgr == nil {
+		return w.receipt, nil
+	}
+	registryCoordinatorAbi, err := regcoord.ContractRegistryCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data, err := registryCoordinatorAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		To:        &testRegistryCoordinatorAddress,
+		Nonce:     3,
+		Gas:       90_000,
+		GasFeeCap: big.NewInt(30),
+		GasTipCap: big.NewInt(1),
+		Data:      data,
+	})
+	return w.txMgr.Send(ctx, tx, true)
+}
+
+func (w *fakeOperatorChainWriter) DeregisterOperator(ctx context.Context, quorumNumbers eigentypes.QuorumNums, pubkey regcoord.BN254G1Point, waitForReceipt bool) (*types.Receipt, error) {
+	return w.send(ctx, "deregisterOperator", quorumNumbers.UnderlyingType())
+}
+
+func (w *fakeOperatorChainWriter) UpdateSocket(ctx context.Context, socket eigentypes.Socket, waitForReceipt bool) (*types.Receipt, error) {
+	return w.send(ctx, "updateSocket", socket.String())
+}
+
+func (w *fakeOperatorChainWriter) UpdateMetadataURI(ctx context.Context, uri string, waitForReceipt bool) (*types.Receipt, error) {
+	w.calls = append(w.calls, "updateOperatorMetadataURI")
+	return w.receipt, nil
+}
+
+func TestSendOperatorChangeAsksForConfirmation(t *testing.T) {
+	writer := &fakeOperatorChainWriter{receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)}}
+	change := UpdateSocketChange(testOperatorAddress, "operator.example.com:8090")
+
+	for _, answer := range []string{"", "n\n", "no\n", "maybe\n"} {
+		var out bytes.Buffer
+		_, err := SendOperatorChange(context.Background(), writer, change, OperatorChangeOptions{In: strings.NewReader(answer), Out: &out})
+		if !errors.Is(err, ErrNotConfirmed) {
+			t.Errorf("answer %q: expected change not to be confirmed, got %v", answer, err)
+		}
+		if !strings.Contains(out.String(), change.Description+"? [y/N]") {
+			t.Errorf("answer %q: expected confirmation prompt, got %q", answer, out.String())
+		}
+	}
+	if len(writer.calls) != 0 {
+		t.Fatalf("expected unconfirmed changes not to be sent, got %v", writer.calls)
+	}
+
+	var out bytes.Buffer
+	if _, err := SendOperatorChange(context.Background(), writer, change, OperatorChangeOptions{In: strings.NewReader("Y\n"), Out: &out}); err != nil {
+		t.Fatalf("could not send confirmed change: %v", err)
+	}
+	if len(writer.calls) != 1 || writer.calls[0] != "updateSocket" {
+		t.Errorf("expected socket to be updated, got %v", writer.calls)
+	}
+}
+
+func TestSendOperatorChangeFailsOnRevert(t *testing.T) {
+	writer := &fakeOperatorChainWriter{receipt: &types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(10)}}
+	change := UpdateMetadataURIChange(testOperatorAddress, "https://example.com/operator.json")
+
+	_, err := SendOperatorChange(context.Background(), writer, change, OperatorChangeOptions{AssumeYes: true, Out: &bytes.Buffer{}})
+	if !errors.Is(err, ErrTransactionFailed) {
+		t.Errorf("expected reverted transaction to fail, got %v", err)
+	}
+	if len(writer.calls) != 1 || writer.calls[0] != "updateOperatorMetadataURI" {
+		t.Errorf("expected metadata URI to be updated without confirmation, got %v", writer.calls)
+	}
+}
+
+func TestSendOperatorChangeDryRun(t *testing.T) {
+	dryRun := chainio.NewDryRunTxManager(testOperatorAddress)
+	writer := &fakeOperatorChainWriter{txMgr: dryRun}
+	change := DeregisterOperatorChange(testOperatorAddress, bls.NewG1Point(big.NewInt(1), big.NewInt(2)), eigentypes.QuorumNums{0})
+
+	var out bytes.Buffer
+	// A dry run doesn't ask for confirmation, so nothing is read from In
+	if _, err := SendOperatorChange(context.Background(), writer, change, OperatorChangeOptions{DryRun: dryRun, Out: &out}); err != nil {
+		t.Fatalf("could not dry run change: %v", err)
+	}
+	for _, expected := range []string{
+		"Dry run: " + change.Description,
+		"From:                     " + testOperatorAddress.Hex(),
+		"To:                       " + testRegistryCoordinatorAddress.Hex(),
+		"Method:                   deregisterOperator(0x00)",
+		"Nonce:                    3",
+		"Gas limit:                90000",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected dry run output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "[y/N]") {
+		t.Errorf("expected dry run not to ask for confirmation")
+	}
+}
@@ -0,0 +1,90 @@
+package operator
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+const DefaultMaxBatchSize = 256 * 1024 * 1024 // 256 MiB
+
+// OfflineBatchVerifierConfig holds the operator settings that affect how a batch is read and verified.
+type OfflineBatchVerifierConfig struct {
+	MaxBatchSize                               int64
+	MaxConcurrentVerifications                 int
+	MaxConcurrentVerificationsPerProvingSystem map[string]int
+	BatchMirrors                               []string
+	BatchUrlRewrites                           []config.BatchUrlRewrite
+	BatchDownloadHedgeDelay                    time.Duration
+}
+
+// OfflineBatchVerifier runs the same pipeline the operator runs for every new batch, reading the
+// batch, checking its merkle root, decoding it and verifying its proofs, without following the
+// chain or signing anything. As the chain is not read, no verifier is considered disabled.
+type OfflineBatchVerifier struct {
+	operator *Operator
+}
+
+func NewOfflineBatchVerifier(verifierConfig OfflineBatchVerifierConfig, logger logging.Logger) (*OfflineBatchVerifier, error) {
+	verifierRegistry, err := NewDefaultVerifierRegistry()
+	if err != nil {
+		return nil, err
+	}
+	provingSystemLimits, err := ParseProvingSystemLimits(verifierConfig.MaxConcurrentVerificationsPerProvingSystem)
+	if err != nil {
+		return nil, err
+	}
+	maxBatchSize := verifierConfig.MaxBatchSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+
+	// Metrics are kept in a registry of their own, which is never served
+	operatorMetrics := metrics.NewMetrics("", prometheus.NewRegistry(), logger)
+
+	return &OfflineBatchVerifier{
+		operator: &Operator{
+			Logger:                logger,
+			metrics:               operatorMetrics,
+			verifierRegistry:      verifierRegistry,
+			verificationScheduler: NewVerificationScheduler(verifierConfig.MaxConcurrentVerifications, provingSystemLimits, operatorMetrics),
+			batchDownloader: NewBatchDownloader(verifierConfig.BatchMirrors, verifierConfig.BatchUrlRewrites,
+				verifierConfig.BatchDownloadHedgeDelay, maxBatchSize, logger),
+		},
+	}, nil
+}
+
+// Verify reads the batch from source, which is either an http(s) URL or a file path, and
+// verifies it. The returned report is never nil, and the error is nil only if the operator
+// would have signed the batch.
+func (v *OfflineBatchVerifier) Verify(ctx context.Context, source string, expectedMerkleRoot [32]byte, senderAddress [20]byte) (*BatchVerificationReport, error) {
+	report := NewBatchVerificationReport(expectedMerkleRoot, senderAddress, 0)
+
+	read := func(ctx context.Context, consume func(batch io.Reader) error) error {
+		if isBatchURL(source) {
+			return v.operator.readBatch(ctx, source, expectedMerkleRoot, consume)
+		}
+		file, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return consume(file)
+	}
+
+	err := v.operator.processBatch(ctx, expectedMerkleRoot, new(big.Int), report, read)
+	return report, err
+}
+
+func isBatchURL(source string) bool {
+	parsedURL, err := url.Parse(source)
+	return err == nil && (parsedURL.Scheme == "http" || parsedURL.Scheme == "https")
+}
@@ -0,0 +1,80 @@
+package operator
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func TestOfflineBatchVerifier(t *testing.T) {
+	batch, merkleRoot := readTestBatch(t)
+	verifier, err := NewOfflineBatchVerifier(OfflineBatchVerifierConfig{}, logging.NewTextSLogger(io.Discard, nil))
+	if err != nil {
+		t.Fatalf("could not create offline verifier: %v", err)
+	}
+
+	// The proofs of the test batch are placeholders, so the batch matches its merkle root but is rejected
+	report, err := verifier.Verify(context.Background(), testBatchFile, merkleRoot, [20]byte{})
+	if err == nil || !report.Rejected() || report.ErrorCategory != ErrorCategoryDeserialization {
+		t.Fatalf("expected test batch to be rejected because of its proofs, got %v (%s)", err, report.ErrorCategory)
+	}
+	if len(report.Proofs) != 35 {
+		t.Errorf("expected a report for each of the 35 proofs, got %d", len(report.Proofs))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(batch)
+	}))
+	defer server.Close()
+
+	wrongMerkleRoot := merkleRoot
+	wrongMerkleRoot[0] ^= 1
+	report, err = verifier.Verify(context.Background(), server.URL+"/batch", wrongMerkleRoot, [20]byte{})
+	if err == nil || !report.Rejected() || report.ErrorCategory != ErrorCategoryMerkleRootMismatch {
+		t.Errorf("expected batch not matching its merkle root to be rejected, got %v (%s)", err, report.ErrorCategory)
+	}
+}
+
+func TestVerificationBacklogCountsProofsStillToBeRead(t *testing.T) {
+	batch, merkleRoot := readTestBatch(t)
+	verifier, err := NewOfflineBatchVerifier(OfflineBatchVerifierConfig{}, logging.NewTextSLogger(io.Discard, nil))
+	if err != nil {
+		t.Fatalf("could not create offline verifier: %v", err)
+	}
+	scheduler := verifier.operator.verificationScheduler
+
+	// The batch stops arriving halfway until it is released
+	stalled := make(chan struct{})
+	release := make(chan struct{})
+	batchReader, batchWriter := io.Pipe()
+	go func() {
+		batchWriter.Write(batch[:len(batch)/2])
+		close(stalled)
+		<-release
+		batchWriter.Write(batch[len(batch)/2:])
+		batchWriter.Close()
+	}()
+	read := func(ctx context.Context, consume func(batch io.Reader) error) error {
+		return consume(batchReader)
+	}
+	done := make(chan struct{})
+	go func() {
+		verifier.operator.processBatch(context.Background(), merkleRoot, big.NewInt(0), NewBatchVerificationReport(merkleRoot, [20]byte{}, 0), read)
+		close(done)
+	}()
+
+	<-stalled
+	if backlog := scheduler.Backlog(); backlog <= 0 {
+		t.Errorf("expected the proofs still to be read to be in the backlog, got %d", backlog)
+	}
+	close(release)
+	<-done
+	if backlog := scheduler.Backlog(); backlog != 0 {
+		t.Errorf("expected an empty backlog once the batch is processed, got %d", backlog)
+	}
+}
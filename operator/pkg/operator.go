@@ -5,69 +5,95 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
 	"net/http"
-	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/urfave/cli/v2"
-	"github.com/yetanotherco/aligned_layer/operator/risc_zero"
-	"github.com/yetanotherco/aligned_layer/operator/risc_zero_old"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/yetanotherco/aligned_layer/metrics"
 
-	"github.com/yetanotherco/aligned_layer/operator/sp1"
-	"github.com/yetanotherco/aligned_layer/operator/sp1_old"
-
 	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
-	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark/backend/groth16"
-	"github.com/consensys/gnark/backend/plonk"
-	"github.com/consensys/gnark/backend/witness"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/yetanotherco/aligned_layer/common"
-	servicemanager "github.com/yetanotherco/aligned_layer/contracts/bindings/AlignedLayerServiceManager"
+	"github.com/yetanotherco/aligned_layer/core/aggregatorrpc"
 	"github.com/yetanotherco/aligned_layer/core/chainio"
 	"github.com/yetanotherco/aligned_layer/core/types"
 
 	"github.com/yetanotherco/aligned_layer/core/config"
+	"github.com/yetanotherco/aligned_layer/core/tasksigner"
 )
 
 type Operator struct {
-	Config                    config.OperatorConfig
-	Address                   ethcommon.Address
-	Socket                    string
-	Timeout                   time.Duration
-	KeyPair                   *bls.KeyPair
-	OperatorId                eigentypes.OperatorId
-	avsSubscriber             chainio.AvsSubscriber
-	avsReader                 chainio.AvsReader
-	NewTaskCreatedChanV2      chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2
-	NewTaskCreatedChanV3      chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3
-	Logger                    logging.Logger
-	aggRpcClient              AggregatorRpcClient
-	metricsReg                *prometheus.Registry
-	metrics                   *metrics.Metrics
-	lastProcessedBatch        OperatorLastProcessedBatch
-	lastProcessedBatchLogFile string
+	Config                config.OperatorConfig
+	Address               ethcommon.Address
+	Socket                string
+	Timeout               time.Duration
+	KeyPair               *bls.KeyPair
+	OperatorId            eigentypes.OperatorId
+	avsSubscriber         chainio.AvsSubscriber
+	avsReader             chainio.AvsReader
+	NewBatchChan          chan *chainio.NewBatch
+	Logger                logging.Logger
+	aggregators           []*aggregatorEndpoint
+	metricsReg            *prometheus.Registry
+	metrics               *metrics.Metrics
+	batchJournal          *BatchJournal
+	auditLog              *AuditLog
+	outboxDelivered       chan struct{}
+	verifierRegistry      *VerifierRegistry
+	verificationScheduler *VerificationScheduler
+	reportStore           *ReportStore
+	batchCache            *BatchCache
+	batchDownloader       *BatchDownloader
+	health                *HealthChecker
+	// batchCtx is the context batches are processed with, cancelled on shutdown
+	// if they are not done within the shutdown timeout
+	batchCtx            context.Context
+	cancelBatches       context.CancelFunc
+	batchHandlers       sync.WaitGroup
+	batchHandlersMutex  sync.Mutex
+	acceptingNewBatches bool
+	// deliveryCtx is the context responses are delivered with. On shutdown it outlives batchCtx,
+	// so the responses signed by the last batches are still delivered
+	deliveryCtx    context.Context
+	cancelDelivery context.CancelFunc
+	// Closed on shutdown for outbox delivery to return once every outbox is empty
+	drainOutboxes chan struct{}
+	// While paused, new batches are deferred until the operator is resumed
+	pauseMutex      sync.Mutex
+	paused          bool
+	deferredBatches []*InFlightBatch
+	inFlightMutex   sync.Mutex
+	inFlightBatches map[string]*InFlightBatch
+	admin           *AdminServer
+	// Serializes journaling the stage batches reach once aggregators are done with their responses
+	deliveryMutex sync.Mutex
 	//Socket  string
 	//Timeout time.Duration
 }
 
 const (
+	// Time a batch source has to answer, and to send more of the batch while it is read
 	BatchDownloadTimeout    = 1 * time.Minute
 	BatchDownloadMaxRetries = 3
 	BatchDownloadRetryDelay = 5 * time.Second
 	UnverifiedBatchOffset   = 100
+	DefaultShutdownTimeout  = 20 * time.Second
+	// Time the aggregators have to accept the first connection before the operator starts in degraded mode
+	AggregatorConnectTimeout = 10 * time.Second
+	// Time to wait for the batch handlers to return once their processing is cancelled on shutdown
+	shutdownCancellationGracePeriod = 5 * time.Second
+	// Time to read when a task was created, which its response is delivered until
+	taskCreatedBlockTimeout = 5 * time.Second
 )
 
 func NewOperatorFromConfig(configuration config.OperatorConfig) (*Operator, error) {
@@ -90,127 +116,141 @@ func NewOperatorFromConfig(configuration config.OperatorConfig) (*Operator, erro
 	if err != nil {
 		log.Fatalf("Could not create AVS subscriber")
 	}
-	newTaskCreatedChanV2 := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2)
-	newTaskCreatedChanV3 := make(chan *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3)
-
-	rpcClient, err := NewAggregatorRpcClient(configuration.Operator.AggregatorServerIpPortAddress, logger)
-	if err != nil {
-		return nil, fmt.Errorf("could not create RPC client: %s. Is aggregator running?", err)
-	}
-
-	operatorId := eigentypes.OperatorIdFromKeyPair(configuration.BlsConfig.KeyPair)
-	address := configuration.Operator.Address
-	lastProcessedBatchLogFile := configuration.Operator.LastProcessedBatchFilePath
-
-	if lastProcessedBatchLogFile == "" {
-		logger.Fatalf("Config file field: `last_processed_batch_filepath` not provided.")
-	}
+	newBatchChan := make(chan *chainio.NewBatch)
 
 	// Metrics
 	reg := prometheus.NewRegistry()
 	operatorMetrics := metrics.NewMetrics(configuration.Operator.MetricsIpPortAddress, reg, logger)
 
-	operator := &Operator{
-		Config:                    configuration,
-		Logger:                    logger,
-		avsSubscriber:             *avsSubscriber,
-		avsReader:                 *avsReader,
-		Address:                   address,
-		NewTaskCreatedChanV2:      newTaskCreatedChanV2,
-		NewTaskCreatedChanV3:      newTaskCreatedChanV3,
-		aggRpcClient:              *rpcClient,
-		OperatorId:                operatorId,
-		metricsReg:                reg,
-		metrics:                   operatorMetrics,
-		lastProcessedBatchLogFile: lastProcessedBatchLogFile,
-		lastProcessedBatch: OperatorLastProcessedBatch{
-			BlockNumber:        0,
-			batchProcessedChan: make(chan uint32),
-		},
+	// Operators that didn't configure the versioned protocol keep using the legacy endpoint
+	aggregatorClientConfig := aggregatorrpc.ClientConfig{
+		CaCertFile:     configuration.Operator.AggregatorRpcCaCertFile,
+		ClientCertFile: configuration.Operator.AggregatorRpcClientCertFile,
+		ClientKeyFile:  configuration.Operator.AggregatorRpcClientKeyFile,
+		Timeout:        configuration.Operator.AggregatorRpcTimeout,
+	}
+	var aggregators []*aggregatorEndpoint
+	for _, endpoint := range AggregatorEndpoints(configuration.Operator.AggregatorEndpoints,
+		configuration.Operator.AggregatorRpcUrl, configuration.Operator.AggregatorServerIpPortAddress) {
+		aggregators = append(aggregators, &aggregatorEndpoint{
+			address: endpoint,
+			client:  newAggregatorClient(endpoint, aggregatorClientConfig, logger),
+		})
+	}
+	connectCtx, cancelConnect := context.WithTimeout(context.Background(), AggregatorConnectTimeout)
+	connected := connectAggregators(connectCtx, aggregators, logger)
+	cancelConnect()
+	if connected == 0 {
+		logger.Warn("Could not connect to any aggregator, starting in degraded mode. Signed responses are kept in the outbox until one is reachable")
+	}
+
+	operatorId := eigentypes.OperatorIdFromG1Pubkey(configuration.BlsConfig.Signer.PubKeyG1())
+	address := configuration.Operator.Address
+	batchJournalFile := BatchJournalPath(configuration.Operator.BatchJournalFilePath, configuration.Operator.LastProcessedBatchFilePath)
 
-		// Timeout
-		// Socket
+	if batchJournalFile == "" {
+		logger.Fatalf("Config file field: `batch_journal_filepath` not provided.")
 	}
 
-	err = operator.LoadLastProcessedBatch()
+	verifierRegistry, err := NewDefaultVerifierRegistry()
 	if err != nil {
-		logger.Fatalf("Error while loading last process batch: %v. This is probably related to the `last_processed_batch_filepath` field passed in the config file", err)
+		return nil, fmt.Errorf("could not create verifier registry: %w", err)
 	}
 
-	return operator, nil
-}
-
-func (o *Operator) SubscribeToNewTasksV2() (chan error, error) {
-	return o.avsSubscriber.SubscribeToNewTasksV2(o.NewTaskCreatedChanV2)
-}
+	provingSystemLimits, err := ParseProvingSystemLimits(configuration.Operator.MaxConcurrentVerificationsPerProvingSystem)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `max_concurrent_verifications_per_proving_system` config: %w", err)
+	}
+	verificationScheduler := NewVerificationScheduler(configuration.Operator.MaxConcurrentVerifications, provingSystemLimits, operatorMetrics)
 
-func (o *Operator) SubscribeToNewTasksV3() (chan error, error) {
-	return o.avsSubscriber.SubscribeToNewTasksV3(o.NewTaskCreatedChanV3)
-}
+	reportStore, err := NewReportStore(configuration.Operator.VerificationReportsDir, configuration.Operator.MaxVerificationReports)
+	if err != nil {
+		return nil, fmt.Errorf("could not create verification report store: %w", err)
+	}
 
-type OperatorLastProcessedBatch struct {
-	BlockNumber        uint32      `json:"block_number"`
-	batchProcessedChan chan uint32 `json:"-"`
-}
+	var batchCache *BatchCache
+	if configuration.Operator.BatchCacheDir != "" {
+		batchCache, err = NewBatchCache(configuration.Operator.BatchCacheDir, configuration.Operator.BatchCacheMaxSize, configuration.Operator.BatchCacheMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("could not create batch cache: %w", err)
+		}
+	}
 
-func (o *Operator) LoadLastProcessedBatch() error {
-	// check if the directory exist
-	folderPath := filepath.Dir(o.lastProcessedBatchLogFile)
-	_, err := os.Stat(folderPath)
+	operator := &Operator{
+		Config:                configuration,
+		Logger:                logger,
+		avsSubscriber:         *avsSubscriber,
+		avsReader:             *avsReader,
+		Address:               address,
+		NewBatchChan:          newBatchChan,
+		aggregators:           aggregators,
+		OperatorId:            operatorId,
+		metricsReg:            reg,
+		metrics:               operatorMetrics,
+		verifierRegistry:      verifierRegistry,
+		verificationScheduler: verificationScheduler,
+		reportStore:           reportStore,
+		batchCache:            batchCache,
+		batchDownloader: NewBatchDownloader(configuration.Operator.BatchMirrors, configuration.Operator.BatchUrlRewrites,
+			configuration.Operator.BatchDownloadHedgeDelay, configuration.Operator.MaxBatchSize, logger),
+
+		acceptingNewBatches: true,
+		inFlightBatches:     make(map[string]*InFlightBatch),
 
-	if os.IsNotExist(err) {
-		return err
+		// Timeout
+		// Socket
 	}
+	operator.batchCtx, operator.cancelBatches = context.WithCancel(context.Background())
+	operator.deliveryCtx, operator.cancelDelivery = context.WithCancel(context.Background())
 
-	file, err := os.ReadFile(o.lastProcessedBatchLogFile)
-
-	// if the file does not exist, we don't return an err, as it will get created later
-	// that is why we check of the directory exist in the first place
+	operator.batchJournal, err = OpenBatchJournal(batchJournalFile, 0)
 	if err != nil {
-		return nil
+		logger.Fatalf("Error while opening the batch journal: %v. This is probably related to the `batch_journal_filepath` field passed in the config file", err)
 	}
 
-	err = json.Unmarshal(file, &o.lastProcessedBatch)
-
+	err = MigrateLastProcessedBatch(operator.batchJournal, configuration.Operator.LastProcessedBatchFilePath)
 	if err != nil {
-		return err
+		logger.Fatalf("Error while migrating last process batch: %v. This is probably related to the `last_processed_batch_filepath` field passed in the config file", err)
 	}
 
-	return nil
-}
-
-func (o *Operator) UpdateLastProcessBatch(blockNumber uint32) error {
-	// we want to store the latest block number
-	if blockNumber < o.lastProcessedBatch.BlockNumber {
-		return nil
+	outboxPath := OutboxPath(configuration.Operator.OutboxDir, batchJournalFile)
+	outboxes := make([]*Outbox, 0, len(operator.aggregators))
+	for _, aggregator := range operator.aggregators {
+		aggregator.outbox, err = OpenOutbox(AggregatorOutboxPath(outboxPath, aggregator.address))
+		if err != nil {
+			logger.Fatalf("Error while opening the outbox of aggregator %s: %v. This is probably related to the `outbox_dir` field passed in the config file", aggregator.address, err)
+		}
+		outboxes = append(outboxes, aggregator.outbox)
+	}
+	if err := MigrateOutbox(outboxPath, outboxes); err != nil {
+		logger.Fatalf("Error while migrating the outbox: %v. This is probably related to the `outbox_dir` field passed in the config file", err)
 	}
 
-	o.lastProcessedBatch.BlockNumber = blockNumber
-
-	// write to a file so it can be recovered in case of operator outage
-	json, err := json.Marshal(o.lastProcessedBatch)
-
-	if err != nil {
-		return fmt.Errorf("failed to marshal batch: %v", err)
+	if configuration.Operator.AuditLogFilePath != "" {
+		operator.auditLog, err = OpenAuditLog(configuration.Operator.AuditLogFilePath)
+		if err != nil {
+			logger.Fatalf("Error while opening the audit log: %v. This is probably related to the `audit_log_filepath` field passed in the config file", err)
+		}
 	}
 
-	err = os.WriteFile(o.lastProcessedBatchLogFile, json, os.ModePerm)
-	if err != nil {
-		return fmt.Errorf("failed to write to file: %v", err)
+	operator.health = operator.newHealthChecker()
+
+	if configuration.Operator.AdminSocketPath != "" || configuration.Operator.AdminIpPortAddress != "" {
+		operator.admin, err = NewAdminServer(operator, configuration.Operator.AdminTokenFile, configuration.BaseConfig.LogLevel)
+		if err != nil {
+			return nil, fmt.Errorf("could not create admin server: %w", err)
+		}
 	}
 
-	o.Logger.Infof("Updated latest block json file, new block: %v", blockNumber)
+	return operator, nil
+}
 
-	return nil
+func (o *Operator) SubscribeToNewBatches() (chan error, error) {
+	return o.avsSubscriber.SubscribeToNewBatches(o.NewBatchChan)
 }
 
 func (o *Operator) Start(ctx context.Context) error {
-	subV2, err := o.SubscribeToNewTasksV2()
-	if err != nil {
-		log.Fatal("Could not subscribe to new tasks")
-	}
-
-	subV3, err := o.SubscribeToNewTasksV3()
+	sub, err := o.SubscribeToNewBatches()
 	if err != nil {
 		log.Fatal("Could not subscribe to new tasks")
 	}
@@ -222,64 +262,149 @@ func (o *Operator) Start(ctx context.Context) error {
 		metricsErrChan = make(chan error, 1)
 	}
 
+	var healthErrChan <-chan error
+	if o.Config.Operator.HealthIpPortAddress != "" {
+		o.Logger.Infof("Starting health server at %v", o.Config.Operator.HealthIpPortAddress)
+		healthErrChan = o.health.Start(ctx, o.Config.Operator.HealthIpPortAddress)
+	}
+
+	var adminErrChan <-chan error
+	if o.admin != nil {
+		adminErrChan, err = o.admin.Start(ctx, o.Config.Operator.AdminSocketPath, o.Config.Operator.AdminIpPortAddress)
+		if err != nil {
+			return fmt.Errorf("could not start admin server: %w", err)
+		}
+	}
+
+	o.startOutboxDelivery()
 	go o.ProcessMissedBatchesWhileOffline()
 
 	for {
 		select {
-		case <-context.Background().Done():
+		case <-ctx.Done():
 			o.Logger.Info("Operator shutting down...")
-			return nil
+			return o.Shutdown(o.Config.Operator.ShutdownTimeout)
 		case err := <-metricsErrChan:
 			o.Logger.Errorf("Metrics server failed", "err", err)
-		case err := <-subV2:
+		case err := <-healthErrChan:
+			o.Logger.Errorf("Health server failed", "err", err)
+		case err := <-adminErrChan:
+			o.Logger.Errorf("Admin server failed", "err", err)
+		case err := <-sub:
 			o.Logger.Infof("Error in websocket subscription", "err", err)
-			subV2, err = o.SubscribeToNewTasksV2()
+			sub, err = o.SubscribeToNewBatches()
 			if err != nil {
-				o.Logger.Fatal("Could not subscribe to new tasks V2")
-			}
-		case err := <-subV3:
-			o.Logger.Infof("Error in websocket subscription", "err", err)
-			subV2, err = o.SubscribeToNewTasksV3()
-			if err != nil {
-				o.Logger.Fatal("Could not subscribe to new tasks V3")
-			}
-		case newBatchLogV2 := <-o.NewTaskCreatedChanV2:
-			go o.handleNewBatchLogV2(newBatchLogV2)
-		case newBatchLogV3 := <-o.NewTaskCreatedChanV3:
-			go o.handleNewBatchLogV3(newBatchLogV3)
-		case blockNumber := <-o.lastProcessedBatch.batchProcessedChan:
-			err = o.UpdateLastProcessBatch(blockNumber)
-			if err != nil {
-				o.Logger.Errorf("Error while updating last process batch", "err", err)
+				o.Logger.Fatal("Could not subscribe to new tasks")
 			}
+		case newBatch := <-o.NewBatchChan:
+			o.dispatchNewBatch(newBatch)
 		}
 	}
 }
 
-// Here we query all the batches that have not yet been verified starting from
-// the latest verified batch by the operator. We also read from the previous
-// `UnverifiedBatchOffset` blocks, because as batches are processed in parallel, there could be
-// unverified batches slightly before the latest verified batch
+// goHandleBatch runs handler in a goroutine, unless the operator is shutting down.
+// Shutdown waits for the running handlers before closing the batch journal.
+func (o *Operator) goHandleBatch(handler func()) bool {
+	o.batchHandlersMutex.Lock()
+	defer o.batchHandlersMutex.Unlock()
+
+	if !o.acceptingNewBatches {
+		return false
+	}
+	o.batchHandlers.Add(1)
+	go func() {
+		defer o.batchHandlers.Done()
+		handler()
+	}()
+	return true
+}
+
+// Shutdown stops accepting new batches and waits up to timeout for the batches being verified
+// and their responses to be delivered. Batches still running after that are cancelled, and
+// resumed from the batch journal on the next start, while responses not delivered yet stay in
+// the outbox. Non-positive timeouts are replaced by DefaultShutdownTimeout.
+func (o *Operator) Shutdown(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	o.batchHandlersMutex.Lock()
+	o.acceptingNewBatches = false
+	o.batchHandlersMutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		o.batchHandlers.Wait()
+		close(done)
+	}()
+
+	o.Logger.Infof("Waiting up to %s for in-flight batches", timeout)
+	select {
+	case <-done:
+		o.Logger.Info("All in-flight batches finished")
+	case <-time.After(timeout):
+		o.Logger.Warnf("In-flight batches did not finish within %s, cancelling them. They will be resumed on the next start", timeout)
+		o.cancelBatches()
+		select {
+		case <-done:
+		case <-time.After(shutdownCancellationGracePeriod):
+			o.Logger.Warn("Some batch handlers did not return after being cancelled")
+		}
+	}
+	o.cancelBatches()
+
+	// Responses signed by the last batches are delivered within the rest of the timeout,
+	// and the ones not delivered by then stay in the outbox for the next start
+	if o.outboxDelivered != nil {
+		close(o.drainOutboxes)
+		select {
+		case <-o.outboxDelivered:
+			o.Logger.Info("All signed responses delivered")
+		case <-time.After(time.Until(deadline)):
+			o.Logger.Warnf("Signed responses were not delivered within %s, they will be delivered on the next start", timeout)
+		}
+		o.cancelDelivery()
+		select {
+		case <-o.outboxDelivered:
+		case <-time.After(shutdownCancellationGracePeriod):
+			o.Logger.Warn("Outbox delivery did not return after being cancelled")
+		}
+	}
+
+	if err := o.batchJournal.Close(); err != nil {
+		return fmt.Errorf("could not close the batch journal: %w", err)
+	}
+	o.Logger.Info("Batch journal flushed")
+
+	if o.auditLog != nil {
+		if err := o.auditLog.Close(); err != nil {
+			return fmt.Errorf("could not close the audit log: %w", err)
+		}
+	}
+	return nil
+}
+
+// ProcessMissedBatchesWhileOffline resumes the batches the batch journal has no delivered
+// response for, and then processes the batches created on chain since the last one the
+// operator saw, which were missed while it was offline.
 func (o *Operator) ProcessMissedBatchesWhileOffline() {
+	o.resumePendingBatches()
+
+	lastSeenBlock := o.batchJournal.LastSeenBlock()
 	// this is the default value
-	// and it means there was no file so no batches have been verified
-	if o.lastProcessedBatch.BlockNumber == 0 {
-		o.Logger.Info("Not continuing with missed batch processing, as operator hasn't verified anything yet...")
+	// and it means no batch has ever been seen
+	if lastSeenBlock == 0 {
+		o.Logger.Info("Not continuing with missed batch processing, as operator hasn't seen any batch yet...")
 		return
 	}
 
 	o.Logger.Info("Getting missed tasks")
 
-	// this check is necessary for overflows as go does not do saturating arithmetic
-	var fromBlock uint64
-	if o.lastProcessedBatch.BlockNumber < UnverifiedBatchOffset {
-		fromBlock = 0
-	} else {
-		fromBlock = uint64(o.lastProcessedBatch.BlockNumber - UnverifiedBatchOffset)
-	}
-
-	logs, err := o.avsReader.GetNotRespondedTasksFrom(fromBlock)
+	// Batches of the last seen block may not have been seen yet, so the scan starts there
+	logs, err := o.avsReader.GetNotRespondedTasksFrom(lastSeenBlock)
 	if err != nil {
+		o.Logger.Errorf("Could not get missed tasks: %v", err)
 		return
 	}
 	o.Logger.Infof(fmt.Sprintf("Missed tasks retrieved, total tasks to process: %v", len(logs)))
@@ -289,340 +414,383 @@ func (o *Operator) ProcessMissedBatchesWhileOffline() {
 	}
 
 	o.Logger.Infof("Starting to verify missed batches while offline")
-	for _, logEntry := range logs {
-		go o.handleNewBatchLogV3(&logEntry)
+	for _, newBatch := range logs {
+		if _, seen := o.batchJournal.Stage(BatchIdentifierHashHex(newBatch.BatchMerkleRoot, newBatch.SenderAddress)); seen {
+			continue
+		}
+		o.dispatchNewBatch(newBatch)
 	}
 	o.Logger.Info("Finished verifying all batches missed while offline")
 }
 
-// Currently, Operator can handle NewBatchV2 and NewBatchV3 events.
+// resumePendingBatches picks up every journaled batch from where it was left.
+// Batches that were already verified are only signed and delivered again.
+func (o *Operator) resumePendingBatches() {
+	pending := o.batchJournal.Pending()
+	if len(pending) == 0 {
+		return
+	}
 
-// The difference between these events do not affect the operator
-// So if you read below, handleNewBatchLogV2 and handleNewBatchLogV3
-// are identical.
+	o.Logger.Infof("Resuming %d batches whose response was not delivered", len(pending))
+	for _, pendingBatch := range pending {
+		batch := pendingBatch.Batch
+		responded, err := o.avsReader.IsBatchResponded(batchIdentifierHash(batch.BatchMerkleRoot, batch.SenderAddress))
+		if err != nil {
+			o.Logger.Errorf("Could not check if batch %s was responded: %v", batch.BatchIdentifierHash(), err)
+		} else if responded {
+			o.recordBatchStage(batch.BatchIdentifierHash(), BatchResponded)
+			continue
+		}
 
-// This structure may help for future upgrades. Having different logics under
-// different events enables the smooth operator upgradeability
+		switch {
+		case pendingBatch.Stage == BatchSigned && o.outboxesContain(batch.BatchIdentifierHash()):
+			// Delivered from the outbox
+			continue
+		case pendingBatch.Stage == BatchVerified || pendingBatch.Stage == BatchSigned:
+			o.goHandleBatch(func() {
+				untrack, tracked := o.trackInFlight(batch.ToNewBatch(), "")
+				if !tracked {
+					return
+				}
+				defer untrack()
+				o.signAndSendTaskResponse(batch.BatchMerkleRoot, batch.SenderAddress, batch.TaskCreatedBlock)
+			})
+		default:
+			o.dispatchNewBatch(batch.ToNewBatch())
+		}
+	}
+}
 
-// Process of handling batches from V2 events:
-func (o *Operator) handleNewBatchLogV2(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2) {
-	var err error
-	defer func() { o.afterHandlingBatchV2(newBatchLog, err == nil) }()
+// handleNewBatch processes a batch of any NewBatch event version, as the differences
+// between versions do not affect the operator, and sends the signed response if it verifies.
+func (o *Operator) handleNewBatch(newBatch *chainio.NewBatch) {
+	o.Logger.Infof("Received new batch log V%d", newBatch.EventVersion)
+	untrack, tracked := o.trackInFlight(newBatch, "")
+	if !tracked {
+		o.Logger.Infof("batch %x is already being handled, skipping it", newBatch.BatchMerkleRoot)
+		return
+	}
+	defer untrack()
 
-	o.Logger.Info("Received new batch log V2")
-	err = o.ProcessNewBatchLogV2(newBatchLog)
+	stage, err := o.batchJournal.RecordSeen(JournaledBatchFromNewBatch(newBatch))
 	if err != nil {
-		o.Logger.Infof("batch %x did not verify. Err: %v", newBatchLog.BatchMerkleRoot, err)
+		o.Logger.Errorf("Could not journal batch %x: %v", newBatch.BatchMerkleRoot, err)
+	}
+	if stage.IsFinal() {
+		o.Logger.Infof("batch %x was already handled, skipping it. Stage: %s", newBatch.BatchMerkleRoot, stage)
 		return
 	}
 
-	batchIdentifier := append(newBatchLog.BatchMerkleRoot[:], newBatchLog.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-	responseSignature := o.SignTaskResponse(batchIdentifierHash)
-	o.Logger.Debugf("responseSignature about to send: %x", responseSignature)
+	o.verifyAndSendTaskResponse(newBatch)
+}
 
-	signedTaskResponse := types.SignedTaskResponse{
-		BatchIdentifierHash: batchIdentifierHash,
-		BatchMerkleRoot:     newBatchLog.BatchMerkleRoot,
-		SenderAddress:       newBatchLog.SenderAddress,
-		BlsSignature:        *responseSignature,
-		OperatorId:          o.OperatorId,
+// verifyAndSendTaskResponse verifies the batch and, if every proof is valid, signs and delivers its response.
+func (o *Operator) verifyAndSendTaskResponse(newBatch *chainio.NewBatch) {
+	report, err := o.ProcessNewBatch(newBatch)
+	o.saveVerificationReport(report)
+	if err != nil {
+		o.Logger.Infof("batch %x did not verify. Err: %v", newBatch.BatchMerkleRoot, err)
+		if report.Rejected() {
+			o.recordBatchStage(report.BatchIdentifierHash, BatchRejected)
+		}
+		return
 	}
-	o.Logger.Infof("Signed Task Response to send: BatchIdentifierHash=%s, BatchMerkleRoot=%s, SenderAddress=%s",
-		hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
-		hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
-		hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
-	)
+	o.recordBatchStage(report.BatchIdentifierHash, BatchVerified)
 
-	o.aggRpcClient.SendSignedTaskResponseToAggregator(&signedTaskResponse)
+	o.signAndSendTaskResponse(newBatch.BatchMerkleRoot, newBatch.SenderAddress, newBatch.TaskCreatedBlock)
 }
-func (o *Operator) ProcessNewBatchLogV2(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2) error {
+
+// ProcessNewBatch downloads and verifies the batch. The returned report is never nil,
+// and holds the outcome of every proof of the batch even when an error is returned.
+func (o *Operator) ProcessNewBatch(newBatch *chainio.NewBatch) (*BatchVerificationReport, error) {
 
 	o.Logger.Info("Received new batch with proofs to verify",
-		"batch merkle root", "0x"+hex.EncodeToString(newBatchLog.BatchMerkleRoot[:]),
-		"sender address", "0x"+hex.EncodeToString(newBatchLog.SenderAddress[:]),
+		"batch merkle root", "0x"+hex.EncodeToString(newBatch.BatchMerkleRoot[:]),
+		"sender address", "0x"+hex.EncodeToString(newBatch.SenderAddress[:]),
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), BatchDownloadTimeout)
-	defer cancel()
-
-	verificationDataBatch, err := o.getBatchFromDataService(ctx, newBatchLog.BatchDataPointer, newBatchLog.BatchMerkleRoot, BatchDownloadMaxRetries, BatchDownloadRetryDelay)
-	if err != nil {
-		o.Logger.Errorf("Could not get proofs from S3 bucket: %v", err)
-		return err
-	}
-
-	verificationDataBatchLen := len(verificationDataBatch)
-	results := make(chan bool, verificationDataBatchLen)
-	var wg sync.WaitGroup
-	wg.Add(verificationDataBatchLen)
+	report := NewBatchVerificationReport(newBatch.BatchMerkleRoot, newBatch.SenderAddress, newBatch.Raw.BlockNumber)
+	err := o.processNewBatch(o.batchCtx, newBatch.BatchDataPointer, newBatch.BatchMerkleRoot, report)
+	return report, err
+}
 
+// processNewBatch downloads and verifies a batch created on chain, taking into account the
+// verifiers disabled at the moment.
+func (o *Operator) processNewBatch(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, report *BatchVerificationReport) error {
 	disabledVerifiersBitmap, err := o.avsReader.DisabledVerifiers()
 	if err != nil {
 		o.Logger.Errorf("Could not check verifiers status: %s", err)
-		results <- false
+		err = fmt.Errorf("%w: %v", ErrVerifierStatus, err)
+		report.Finish(err)
 		return err
 	}
 
-	for _, verificationData := range verificationDataBatch {
-		go func(data VerificationData) {
-			defer wg.Done()
-			o.verify(data, disabledVerifiersBitmap, results)
-			o.metrics.IncOperatorTaskResponses()
-		}(verificationData)
-	}
+	return o.processBatch(ctx, expectedMerkleRoot, disabledVerifiersBitmap, report, func(ctx context.Context, consume func(batch io.Reader) error) error {
+		if err := o.readBatch(ctx, batchURL, expectedMerkleRoot, consume); err != nil {
+			return err
+		}
+		o.recordBatchStage(report.BatchIdentifierHash, BatchDownloaded)
+		return nil
+	})
+}
 
-	go func() {
-		wg.Wait()
-		close(results)
+// processBatch reads the batch through read and verifies its proofs while it arrives,
+// recording the outcome in report. A rejected proof stops the verification of the proofs
+// still to come, but the batch is only rejected once it is completely read and matches its
+// merkle root, so a tampered copy of a batch never makes the operator reject the real one.
+func (o *Operator) processBatch(ctx context.Context, expectedMerkleRoot [32]byte, disabledVerifiersBitmap *big.Int, report *BatchVerificationReport, read batchReader) error {
+	// Every source the batch is read from starts a new verification, as a source may serve another batch
+	var verification *batchVerification
+	defer func() {
+		if verification != nil {
+			verification.Abort()
+		}
 	}()
+	consume := func(batch io.Reader) error {
+		if verification != nil {
+			verification.Abort()
+		}
+		verification = o.newBatchVerification(ctx, disabledVerifiersBitmap)
 
-	for result := range results {
-		if !result {
-			return fmt.Errorf("invalid proof")
+		merkleRoot, err := streamBatch(batch, o.Logger, verification.Expect, verification.Submit)
+		if err != nil {
+			return err
 		}
+		if merkleRoot != expectedMerkleRoot {
+			return fmt.Errorf("%w: got 0x%x", ErrMerkleRootMismatch, merkleRoot)
+		}
+		o.Logger.Infof("Batch merkle tree verified")
+		return nil
 	}
 
-	return nil
+	err := read(ctx, consume)
+	report.DownloadDuration = time.Since(report.ReceivedAt)
+	if err != nil {
+		o.Logger.Errorf("Could not get proofs from S3 bucket: %v", err)
+		report.Finish(err)
+		if report.ErrorCategory == ErrorCategoryVerificationFailed || report.ErrorCategory == ErrorCategoryCancelled {
+			report.ErrorCategory = ErrorCategoryBatchDownload
+		}
+		return err
+	}
+
+	err = verification.Wait(report)
+	report.Finish(err)
+	return err
 }
 
-// Process of handling batches from V3 events:
-func (o *Operator) handleNewBatchLogV3(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3) {
-	var err error
-	defer func() { o.afterHandlingBatchV3(newBatchLog, err == nil) }()
-	o.Logger.Infof("Received new batch log V3")
-	err = o.ProcessNewBatchLogV3(newBatchLog)
+// signAndSendTaskResponse signs the batch identifier hash and stores the signed response in the outbox
+// of every aggregator, which delivers it to them until the task expires.
+func (o *Operator) signAndSendTaskResponse(batchMerkleRoot [32]byte, senderAddress [20]byte, taskCreatedBlock uint32) {
+	batchIdentifierHash := batchIdentifierHash(batchMerkleRoot, senderAddress)
+	responseSignature, err := o.SignTaskResponse(batchIdentifierHash)
 	if err != nil {
-		o.Logger.Infof("batch %x did not verify. Err: %v", newBatchLog.BatchMerkleRoot, err)
+		o.Logger.Errorf("Could not sign task response of batch %x: %v", batchMerkleRoot, err)
 		return
 	}
-
-	batchIdentifier := append(newBatchLog.BatchMerkleRoot[:], newBatchLog.SenderAddress[:]...)
-	var batchIdentifierHash = *(*[32]byte)(crypto.Keccak256(batchIdentifier))
-	responseSignature := o.SignTaskResponse(batchIdentifierHash)
 	o.Logger.Debugf("responseSignature about to send: %x", responseSignature)
 
 	signedTaskResponse := types.SignedTaskResponse{
 		BatchIdentifierHash: batchIdentifierHash,
-		BatchMerkleRoot:     newBatchLog.BatchMerkleRoot,
-		SenderAddress:       newBatchLog.SenderAddress,
+		BatchMerkleRoot:     batchMerkleRoot,
+		SenderAddress:       senderAddress,
 		BlsSignature:        *responseSignature,
 		OperatorId:          o.OperatorId,
 	}
+	journalId := "0x" + hex.EncodeToString(batchIdentifierHash[:])
+	// A response that can't be audited is not sent
+	if err := o.auditSignedTaskResponse(journalId, &signedTaskResponse); err != nil {
+		o.Logger.Errorf("Could not record signed task response of batch %x in the audit log: %v", batchMerkleRoot, err)
+		return
+	}
+	deadline := o.taskResponseDeadline(taskCreatedBlock)
+	stored := 0
+	for _, aggregator := range o.aggregators {
+		if err := aggregator.outbox.Add(&signedTaskResponse, deadline); err != nil {
+			o.Logger.Errorf("Could not store signed task response of batch %x in the outbox of aggregator %s: %v", batchMerkleRoot, aggregator.address, err)
+			continue
+		}
+		stored++
+	}
+	if stored == 0 {
+		return
+	}
+	o.recordBatchStage(journalId, BatchSigned)
 	o.Logger.Infof("Signed Task Response to send: BatchIdentifierHash=%s, BatchMerkleRoot=%s, SenderAddress=%s",
 		hex.EncodeToString(signedTaskResponse.BatchIdentifierHash[:]),
 		hex.EncodeToString(signedTaskResponse.BatchMerkleRoot[:]),
 		hex.EncodeToString(signedTaskResponse.SenderAddress[:]),
 	)
-
-	o.aggRpcClient.SendSignedTaskResponseToAggregator(&signedTaskResponse)
 }
-func (o *Operator) ProcessNewBatchLogV3(newBatchLog *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3) error {
-
-	o.Logger.Info("Received new batch with proofs to verify",
-		"batch merkle root", "0x"+hex.EncodeToString(newBatchLog.BatchMerkleRoot[:]),
-		"sender address", "0x"+hex.EncodeToString(newBatchLog.SenderAddress[:]),
-	)
-
-	ctx, cancel := context.WithTimeout(context.Background(), BatchDownloadTimeout)
-	defer cancel()
 
-	verificationDataBatch, err := o.getBatchFromDataService(ctx, newBatchLog.BatchDataPointer, newBatchLog.BatchMerkleRoot, BatchDownloadMaxRetries, BatchDownloadRetryDelay)
-	if err != nil {
-		o.Logger.Errorf("Could not get proofs from S3 bucket: %v", err)
-		return err
-	}
-
-	verificationDataBatchLen := len(verificationDataBatch)
-	results := make(chan bool, verificationDataBatchLen)
-	var wg sync.WaitGroup
-	wg.Add(verificationDataBatchLen)
-	disabledVerifiersBitmap, err := o.avsReader.DisabledVerifiers()
-	if err != nil {
-		o.Logger.Errorf("Could not check verifiers status: %s", err)
-		results <- false
-		return err
-	}
-	for _, verificationData := range verificationDataBatch {
-		go func(data VerificationData) {
-			defer wg.Done()
-			o.verify(data, disabledVerifiersBitmap, results)
-			o.metrics.IncOperatorTaskResponses()
-		}(verificationData)
+// startOutboxDelivery delivers the responses of the outboxes to every aggregator concurrently,
+// until every outbox is empty on shutdown or the shutdown timeout runs out.
+func (o *Operator) startOutboxDelivery() {
+	var deliveries sync.WaitGroup
+	o.drainOutboxes = make(chan struct{})
+	for _, aggregator := range o.aggregators {
+		delivery := &outboxDelivery{
+			aggregator: aggregator.address,
+			outbox:     aggregator.outbox,
+			client:     aggregator.client,
+			logger:     o.Logger,
+			metrics:    o.metrics,
+			finished:   o.recordAggregatorFinished,
+		}
+		deliveries.Add(1)
+		go func() {
+			defer deliveries.Done()
+			delivery.Run(o.deliveryCtx, o.drainOutboxes)
+		}()
 	}
-
+	o.outboxDelivered = make(chan struct{})
 	go func() {
-		wg.Wait()
-		close(results)
+		deliveries.Wait()
+		close(o.outboxDelivered)
 	}()
-
-	for result := range results {
-		if !result {
-			return fmt.Errorf("invalid proof")
-		}
-	}
-
-	return nil
 }
 
-func (o *Operator) afterHandlingBatchV2(log *servicemanager.ContractAlignedLayerServiceManagerNewBatchV2, succeeded bool) {
-	if succeeded {
-		o.lastProcessedBatch.batchProcessedChan <- uint32(log.Raw.BlockNumber)
-	}
-}
+// recordAggregatorFinished journals the stage a batch reached once an aggregator is done with its
+// response. Any aggregator can reach quorum, so the batch is delivered as soon as one of them
+// acknowledges the response, and only abandoned once every aggregator gave up on it.
+func (o *Operator) recordAggregatorFinished(batchIdentifierHash string, stage BatchStage) {
+	o.deliveryMutex.Lock()
+	defer o.deliveryMutex.Unlock()
 
-func (o *Operator) afterHandlingBatchV3(log *servicemanager.ContractAlignedLayerServiceManagerNewBatchV3, succeeded bool) {
-	if succeeded {
-		o.lastProcessedBatch.batchProcessedChan <- uint32(log.Raw.BlockNumber)
+	if current, ok := o.batchJournal.Stage(batchIdentifierHash); ok && current.IsFinal() {
+		return
 	}
-}
-
-func (o *Operator) verify(verificationData VerificationData, disabledVerifiersBitmap *big.Int, results chan bool) {
-	IsVerifierDisabled := IsVerifierDisabled(disabledVerifiersBitmap, verificationData.ProvingSystemId)
-	if IsVerifierDisabled {
-		o.Logger.Infof("Verifier %s is disabled. Returning false", verificationData.ProvingSystemId.String())
-		results <- false
+	if stage == BatchAbandoned && o.outboxesContain(batchIdentifierHash) {
 		return
 	}
-	switch verificationData.ProvingSystemId {
-	case common.GnarkPlonkBls12_381:
-		verificationResult := o.verifyPlonkProofBLS12_381(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
-		o.Logger.Infof("PLONK BLS12-381 proof verification result: %t", verificationResult)
-
-		results <- verificationResult
-
-	case common.GnarkPlonkBn254:
-		verificationResult := o.verifyPlonkProofBN254(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
-		o.Logger.Infof("PLONK BN254 proof verification result: %t", verificationResult)
-
-		results <- verificationResult
-
-	case common.Groth16Bn254:
-		verificationResult := o.verifyGroth16ProofBN254(verificationData.Proof, verificationData.PubInput, verificationData.VerificationKey)
-		o.Logger.Infof("GROTH16 BN254 proof verification result: %t", verificationResult)
-
-		results <- verificationResult
+	o.recordBatchStage(batchIdentifierHash, stage)
+}
 
-	case common.SP1:
-		verificationResult, err := sp1.VerifySp1Proof(verificationData.Proof, verificationData.VmProgramCode)
-		if !verificationResult {
-			o.Logger.Infof("SP1 proof verification failed. Trying old SP1 version...")
-			verificationResult, err = sp1_old.VerifySp1ProofOld(verificationData.Proof, verificationData.VmProgramCode)
-			if !verificationResult {
-				o.Logger.Errorf("Old SP1 proof verification failed")
-			}
-		}
-		o.Logger.Infof("SP1 proof verification result: %t", verificationResult)
-		o.handleVerificationResult(results, verificationResult, err, "SP1 proof verification")
-
-	case common.Risc0:
-		verificationResult, err := risc_zero.VerifyRiscZeroReceipt(verificationData.Proof,
-			verificationData.VmProgramCode, verificationData.PubInput)
-		if !verificationResult {
-			o.Logger.Infof("Risc0 proof verification failed. Trying old Risc0 version...")
-			verificationResult, err = risc_zero_old.VerifyRiscZeroReceiptOld(verificationData.Proof, verificationData.VmProgramCode, verificationData.PubInput)
-			if !verificationResult {
-				o.Logger.Errorf("Old Risc0 proof verification failed")
-			}
+// outboxesContain reports whether the response of the batch is waiting to be delivered to any aggregator.
+func (o *Operator) outboxesContain(batchIdentifierHash string) bool {
+	for _, aggregator := range o.aggregators {
+		if aggregator.outbox.Contains(batchIdentifierHash) {
+			return true
 		}
-		o.Logger.Infof("Risc0 proof verification result: %t", verificationResult)
-		o.handleVerificationResult(results, verificationResult, err, "Risc0 proof verification")
-	default:
-		o.Logger.Error("Unrecognized proving system ID")
-		results <- false
 	}
+	return false
 }
 
-func (o *Operator) handleVerificationResult(results chan bool, isVerified bool, err error, name string) {
+// taskResponseDeadline returns until when the aggregator accepts responses to the task created at taskCreatedBlock.
+func (o *Operator) taskResponseDeadline(taskCreatedBlock uint32) time.Time {
+	taskTimeout := o.Config.Operator.AggregatorTaskTimeout
+	if taskTimeout <= 0 {
+		taskTimeout = DefaultAggregatorTaskTimeout
+	}
+	baseConfig := o.Config.BaseConfig
+	ctx, cancel := context.WithTimeout(o.batchCtx, taskCreatedBlockTimeout)
+	defer cancel()
+	deadline, err := TaskResponseDeadline(ctx, []blockHeaderClient{&baseConfig.EthRpcClient, &baseConfig.EthRpcClientFallback}, taskCreatedBlock, taskTimeout)
 	if err != nil {
-		o.Logger.Errorf("%v failed %v", name, err)
-		results <- false
-	} else {
-		o.Logger.Infof("%v result: %t", name, isVerified)
-		results <- isVerified
+		// The task was created before now, so it expires by then at the latest
+		o.Logger.Warn("Could not get when the task was created, its response is delivered for the whole task timeout from now",
+			"taskCreatedBlock", taskCreatedBlock, "err", err)
+		return time.Now().Add(taskTimeout)
 	}
+	return deadline
 }
 
-// VerifyPlonkProofBLS12_381 verifies a PLONK proof using BLS12-381 curve.
-func (o *Operator) verifyPlonkProofBLS12_381(proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte) bool {
-	return o.verifyPlonkProof(proofBytes, pubInputBytes, verificationKeyBytes, ecc.BLS12_381)
-}
-
-// VerifyPlonkProofBN254 verifies a PLONK proof using BN254 curve.
-func (o *Operator) verifyPlonkProofBN254(proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte) bool {
-	return o.verifyPlonkProof(proofBytes, pubInputBytes, verificationKeyBytes, ecc.BN254)
+// auditSignedTaskResponse appends the response to the audit log, along with the digest of the
+// verification report of the batch. Nothing is recorded if the audit log is not configured.
+func (o *Operator) auditSignedTaskResponse(batchIdentifierHash string, signedTaskResponse *types.SignedTaskResponse) error {
+	if o.auditLog == nil {
+		return nil
+	}
+	report, _ := o.reportStore.Get(batchIdentifierHash)
+	reportDigest, err := ReportDigest(report)
+	if err != nil {
+		return err
+	}
+	_, err = o.auditLog.Append(signedTaskResponse, reportDigest)
+	return err
 }
 
-// VerifyGroth16ProofBN254 verifies a GROTH16 proof using BN254 curve.
-func (o *Operator) verifyGroth16ProofBN254(proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte) bool {
-	return o.verifyGroth16Proof(proofBytes, pubInputBytes, verificationKeyBytes, ecc.BN254)
+func (o *Operator) recordBatchStage(batchIdentifierHash string, stage BatchStage) {
+	if err := o.batchJournal.Record(batchIdentifierHash, stage); err != nil {
+		o.Logger.Errorf("Could not journal batch %s as %s: %v", batchIdentifierHash, stage, err)
+	}
 }
 
-// verifyPlonkProof contains the common proof verification logic.
-func (o *Operator) verifyPlonkProof(proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte, curve ecc.ID) bool {
-	proofReader := bytes.NewReader(proofBytes)
-	proof := plonk.NewProof(curve)
-	if _, err := proof.ReadFrom(proofReader); err != nil {
-		o.Logger.Infof("Could not deserialize proof: %v", err)
-		return false
-	}
+// batchReader hands a batch over to consume, calling it again with another copy of the batch
+// while it fails.
+type batchReader func(ctx context.Context, consume func(batch io.Reader) error) error
 
-	pubInputReader := bytes.NewReader(pubInputBytes)
-	pubInput, err := witness.New(curve.ScalarField())
-	if err != nil {
-		o.Logger.Infof("Error instantiating witness: %v", err)
-		return false
+// verify returns the outcome of verifying the proof at position index of the batch.
+// If the verification was cancelled through ctx, the outcome has the cancelled error category.
+func (o *Operator) verify(ctx context.Context, index int, verificationData VerificationData, disabledVerifiersBitmap *big.Int) ProofVerificationReport {
+	proofReport := ProofVerificationReport{
+		Index:         index,
+		ProvingSystem: provingSystemName(verificationData.ProvingSystemId),
 	}
-	if _, err = pubInput.ReadFrom(pubInputReader); err != nil {
-		o.Logger.Infof("Could not read PLONK public input: %v", err)
-		return false
+	if err := ctx.Err(); err != nil {
+		proofReport.ErrorCategory = ErrorCategoryCancelled
+		proofReport.Error = err.Error()
+		return proofReport
 	}
 
-	verificationKeyReader := bytes.NewReader(verificationKeyBytes)
-	verificationKey := plonk.NewVerifyingKey(curve)
-	if _, err = verificationKey.ReadFrom(verificationKeyReader); err != nil {
-		o.Logger.Infof("Could not read PLONK verifying key from bytes: %v", err)
-		return false
+	IsVerifierDisabled := IsVerifierDisabled(disabledVerifiersBitmap, verificationData.ProvingSystemId)
+	if IsVerifierDisabled {
+		o.Logger.Infof("Verifier %s is disabled. Returning false", verificationData.ProvingSystemId.String())
+		proofReport.ErrorCategory = ErrorCategoryDisabledVerifier
+		return proofReport
+	}
+
+	start := time.Now()
+	result := o.verifierRegistry.Verify(ctx, verificationData)
+	proofReport.Duration = time.Since(start)
+	if ctx.Err() != nil && errors.Is(result.Err, ctx.Err()) {
+		o.Logger.Debugf("%s proof verification cancelled", verificationData.ProvingSystemId.String())
+		proofReport.ErrorCategory = ErrorCategoryCancelled
+		proofReport.Error = result.Err.Error()
+		return proofReport
+	}
+	if result.Err != nil {
+		o.Logger.Errorf("%s proof verification failed: %v", verificationData.ProvingSystemId.String(), result.Err)
+	}
+	o.Logger.Infof("%s proof verification result: %t. Verifier version: %s", verificationData.ProvingSystemId.String(), result.Verified,
+		result.Version)
+	o.metrics.IncOperatorTaskResponses()
+
+	proofReport.Verified = result.Verified
+	proofReport.VerifierVersion = result.Version
+	if !result.Verified {
+		proofReport.ErrorCategory = categorizeError(result.Err)
+		if result.Err != nil {
+			proofReport.Error = result.Err.Error()
+		}
 	}
-
-	err = plonk.Verify(proof, verificationKey, pubInput)
-	return err == nil
+	return proofReport
 }
 
-// verifyGroth16Proof contains the common proof verification logic.
-func (o *Operator) verifyGroth16Proof(proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte, curve ecc.ID) bool {
-	proofReader := bytes.NewReader(proofBytes)
-	proof := groth16.NewProof(curve)
-	if _, err := proof.ReadFrom(proofReader); err != nil {
-		o.Logger.Infof("Could not deserialize proof: %v", err)
-		return false
+// saveVerificationReport stores the report so it can be queried later,
+// and logs which proof made the batch fail, if any.
+func (o *Operator) saveVerificationReport(report *BatchVerificationReport) {
+	if proof, ok := report.FirstRejectedProof(); ok {
+		o.Logger.Infof("Batch %s rejected because of proof %d. Proving system: %s, error category: %s, error: %s",
+			report.BatchMerkleRoot, proof.Index, proof.ProvingSystem, proof.ErrorCategory, proof.Error)
 	}
-
-	pubInputReader := bytes.NewReader(pubInputBytes)
-	pubInput, err := witness.New(curve.ScalarField())
-	if err != nil {
-		o.Logger.Infof("Error instantiating witness: %v", err)
-		return false
-	}
-	if _, err = pubInput.ReadFrom(pubInputReader); err != nil {
-		o.Logger.Infof("Could not read Groth16 public input: %v", err)
-		return false
+	if err := o.reportStore.Save(report); err != nil {
+		o.Logger.Errorf("Could not save verification report of batch %s: %v", report.BatchMerkleRoot, err)
 	}
+}
 
-	verificationKeyReader := bytes.NewReader(verificationKeyBytes)
-	verificationKey := groth16.NewVerifyingKey(curve)
-	if _, err = verificationKey.ReadFrom(verificationKeyReader); err != nil {
-		o.Logger.Infof("Could not read Groth16 verifying key from bytes: %v", err)
-		return false
-	}
+// VerificationReport returns the report of a recent batch given its merkle root or identifier hash.
+func (o *Operator) VerificationReport(id string) (*BatchVerificationReport, bool) {
+	return o.reportStore.Get(id)
+}
 
-	err = groth16.Verify(proof, verificationKey, pubInput)
-	return err == nil
+// RecentVerificationReports returns the reports of up to n recent batches, most recent first.
+func (o *Operator) RecentVerificationReports(n int) []*BatchVerificationReport {
+	return o.reportStore.Recent(n)
 }
 
-func (o *Operator) SignTaskResponse(batchIdentifierHash [32]byte) *bls.Signature {
-	responseSignature := *o.Config.BlsConfig.KeyPair.SignMessage(batchIdentifierHash)
-	return &responseSignature
+func (o *Operator) SignTaskResponse(batchIdentifierHash [32]byte) (*bls.Signature, error) {
+	return o.Config.BlsConfig.Signer.SignMessage(o.batchCtx, tasksigner.MessageTypeTaskResponse, batchIdentifierHash)
 }
 
 func (o *Operator) SendTelemetryData(ctx *cli.Context) error {
@@ -635,8 +803,11 @@ func (o *Operator) SendTelemetryData(ctx *cli.Context) error {
 	copy(version[:], hash.Sum(nil))
 
 	// sign version
-	signature := o.Config.BlsConfig.KeyPair.SignMessage(version)
-	public_key_g2 := o.Config.BlsConfig.KeyPair.GetPubKeyG2()
+	signature, err := o.Config.BlsConfig.Signer.SignMessage(ctx.Context, tasksigner.MessageTypeOperatorVersion, version)
+	if err != nil {
+		return err
+	}
+	public_key_g2 := o.Config.BlsConfig.Signer.PubKeyG2()
 	ethRpcUrl, err := BaseUrlOnly(o.Config.BaseConfig.EthRpcUrl)
 	if err != nil {
 		return err
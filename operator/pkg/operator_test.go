@@ -0,0 +1,73 @@
+package operator
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func newTestOperator(t *testing.T) *Operator {
+	journal, err := OpenBatchJournal(filepath.Join(t.TempDir(), "operator.batch_journal"), 0)
+	if err != nil {
+		t.Fatalf("could not open journal: %v", err)
+	}
+	operator := &Operator{
+		Logger:              logging.NewTextSLogger(io.Discard, nil),
+		batchJournal:        journal,
+		acceptingNewBatches: true,
+		inFlightBatches:     make(map[string]*InFlightBatch),
+	}
+	operator.batchCtx, operator.cancelBatches = context.WithCancel(context.Background())
+	operator.deliveryCtx, operator.cancelDelivery = context.WithCancel(context.Background())
+	return operator
+}
+
+func TestShutdownDrainsInFlightBatches(t *testing.T) {
+	operator := newTestOperator(t)
+	batch := journaledBatch(1, 10)
+	operator.batchJournal.RecordSeen(batch)
+
+	release := make(chan struct{})
+	operator.goHandleBatch(func() {
+		<-release
+		operator.recordBatchStage(batch.BatchIdentifierHash(), BatchDelivered)
+	})
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+
+	if err := operator.Shutdown(5 * time.Second); err != nil {
+		t.Fatalf("could not shut down: %v", err)
+	}
+	if stage, _ := operator.batchJournal.Stage(batch.BatchIdentifierHash()); stage != BatchDelivered {
+		t.Errorf("expected in-flight batch to be delivered before shutting down, got %s", stage)
+	}
+	if operator.goHandleBatch(func() {}) {
+		t.Errorf("expected no new batch to be accepted after shutting down")
+	}
+}
+
+func TestShutdownCancelsBatchesAfterTimeout(t *testing.T) {
+	operator := newTestOperator(t)
+
+	cancelled := make(chan struct{})
+	operator.goHandleBatch(func() {
+		<-operator.batchCtx.Done()
+		close(cancelled)
+	})
+
+	start := time.Now()
+	if err := operator.Shutdown(20 * time.Millisecond); err != nil {
+		t.Fatalf("could not shut down: %v", err)
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Errorf("expected in-flight batch to be cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > shutdownCancellationGracePeriod {
+		t.Errorf("expected shutdown to finish once the batch was cancelled, took %s", elapsed)
+	}
+}
@@ -0,0 +1,423 @@
+package operator
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/yetanotherco/aligned_layer/core/aggregatorrpc"
+	"github.com/yetanotherco/aligned_layer/core/types"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// DefaultAggregatorTaskTimeout is how long after a task is created the aggregator accepts responses
+// to it, if not configured. It is the bls_service_task_timeout suggested for aggregators.
+const DefaultAggregatorTaskTimeout = 168 * time.Hour
+
+const (
+	// Responses the aggregator could not process are sent again after OutboxInitialRetryInterval,
+	// doubling the interval on every attempt up to OutboxMaxRetryInterval. The same goes for the
+	// whole outbox while the aggregator can't be reached.
+	OutboxInitialRetryInterval = 2 * time.Second
+	OutboxMaxRetryInterval     = time.Minute
+)
+
+const outboxEntryExtension = ".json"
+
+// OutboxEntry is a signed task response waiting for the aggregator to acknowledge it.
+type OutboxEntry struct {
+	Response aggregatorrpc.TaskResponseV1 `json:"response"`
+	SignedAt time.Time                    `json:"signed_at"`
+	// The response is given up on once its deadline passes
+	Deadline    time.Time `json:"deadline"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// BatchIdentifierHash returns the hex encoded batch identifier hash of the response, as the journal identifies batches.
+func (e OutboxEntry) BatchIdentifierHash() string {
+	return "0x" + hex.EncodeToString(e.Response.BatchIdentifierHash[:])
+}
+
+// Outbox keeps the signed task responses of the operator until the aggregator acknowledges them,
+// so they survive aggregator outages and operator restarts. Each response is a file of the outbox
+// directory named after its batch identifier hash, written atomically and removed once the
+// response is acknowledged or given up on.
+type Outbox struct {
+	dir     string
+	mutex   sync.Mutex
+	entries map[string]OutboxEntry
+	// Signalled when a response is added, so it is delivered right away
+	added chan struct{}
+}
+
+// OpenOutbox opens the outbox at dir, creating it if it doesn't exist.
+func OpenOutbox(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create outbox: %w", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read outbox: %w", err)
+	}
+
+	outbox := &Outbox{
+		dir:     dir,
+		entries: make(map[string]OutboxEntry),
+		added:   make(chan struct{}, 1),
+	}
+	for _, file := range files {
+		// Temporary files are left by writes interrupted by a crash
+		if file.IsDir() || filepath.Ext(file.Name()) != outboxEntryExtension {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read outbox entry %s: %w", file.Name(), err)
+		}
+		var entry OutboxEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("corrupted outbox entry %s: %w", file.Name(), err)
+		}
+		outbox.entries[entry.BatchIdentifierHash()] = entry
+	}
+	return outbox, nil
+}
+
+// blockHeaderClient is the part of an eth client used to read when a task was created.
+type blockHeaderClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+}
+
+// TaskResponseDeadline returns until when the aggregator accepts responses to the task created at
+// taskCreatedBlock, which is taskTimeout after the block. The time of the block is read from the
+// first of clients that has it.
+func TaskResponseDeadline(ctx context.Context, clients []blockHeaderClient, taskCreatedBlock uint32, taskTimeout time.Duration) (time.Time, error) {
+	var errs []error
+	for _, client := range clients {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(uint64(taskCreatedBlock)))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return time.Unix(int64(header.Time), 0).Add(taskTimeout), nil
+	}
+	return time.Time{}, fmt.Errorf("could not get block %d: %w", taskCreatedBlock, errors.Join(errs...))
+}
+
+// OutboxPath returns where the outbox is kept given the `outbox_dir` config field.
+// If it is not set, the outbox is kept next to the batch journal.
+func OutboxPath(outboxDir string, batchJournalFile string) string {
+	if outboxDir == "" {
+		return strings.TrimSuffix(batchJournalFile, filepath.Ext(batchJournalFile)) + ".outbox"
+	}
+	return outboxDir
+}
+
+// Add stores the response, to be delivered until deadline. A response of the same batch is replaced.
+func (b *Outbox) Add(signedTaskResponse *types.SignedTaskResponse, deadline time.Time) error {
+	now := time.Now()
+	entry := OutboxEntry{
+		Response:    aggregatorrpc.NewTaskResponseV1(signedTaskResponse),
+		SignedAt:    now,
+		Deadline:    deadline,
+		NextAttempt: now,
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if err := b.write(entry); err != nil {
+		return err
+	}
+	b.entries[entry.BatchIdentifierHash()] = entry
+
+	select {
+	case b.added <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// restore stores the entry as it is, unless a response of the same batch is already kept.
+func (b *Outbox) restore(entry OutboxEntry) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.entries[entry.BatchIdentifierHash()]; ok {
+		return nil
+	}
+	if err := b.write(entry); err != nil {
+		return err
+	}
+	b.entries[entry.BatchIdentifierHash()] = entry
+	return nil
+}
+
+// Update stores the delivery state of the entry. Entries that were removed are ignored.
+func (b *Outbox) Update(entry OutboxEntry) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.entries[entry.BatchIdentifierHash()]; !ok {
+		return nil
+	}
+	if err := b.write(entry); err != nil {
+		return err
+	}
+	b.entries[entry.BatchIdentifierHash()] = entry
+	return nil
+}
+
+// Remove deletes the response of the batch.
+func (b *Outbox) Remove(batchIdentifierHash string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.entries, batchIdentifierHash)
+	if err := os.Remove(b.path(batchIdentifierHash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove outbox entry: %w", err)
+	}
+	return nil
+}
+
+// Contains reports whether a response of the batch is waiting to be delivered.
+func (b *Outbox) Contains(batchIdentifierHash string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	_, ok := b.entries[batchIdentifierHash]
+	return ok
+}
+
+// Entries returns the responses waiting to be delivered, the first due first.
+func (b *Outbox) Entries() []OutboxEntry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	entries := make([]OutboxEntry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].NextAttempt.Before(entries[j].NextAttempt)
+	})
+	return entries
+}
+
+// Len returns the number of responses waiting to be delivered.
+func (b *Outbox) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return len(b.entries)
+}
+
+// write stores the entry on disk. Must be called with the mutex held.
+func (b *Outbox) write(entry OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not marshal outbox entry: %w", err)
+	}
+	if err := writeFileAtomic(b.path(entry.BatchIdentifierHash()), data, 0o644); err != nil {
+		return fmt.Errorf("could not write outbox entry: %w", err)
+	}
+	return nil
+}
+
+func (b *Outbox) path(batchIdentifierHash string) string {
+	return filepath.Join(b.dir, batchIdentifierHash+outboxEntryExtension)
+}
+
+// outboxDelivery sends the responses of the outbox to an aggregator until each of them is
+// acknowledged, rejected, or its deadline passes.
+type outboxDelivery struct {
+	// Address of the aggregator, which labels its logs and metrics
+	aggregator string
+	outbox     *Outbox
+	client     AggregatorClient
+	logger     logging.Logger
+	metrics    *metrics.Metrics
+	// Called with the stage the batch of a response reached once it is removed from the outbox
+	finished func(batchIdentifierHash string, stage BatchStage)
+	// Consecutive rounds the aggregator could not be reached in
+	unreachableRounds int
+}
+
+// Run delivers the responses of the outbox until ctx is done or, once drain is closed, until
+// the outbox is empty.
+func (d *outboxDelivery) Run(ctx context.Context, drain <-chan struct{}) {
+	draining := false
+	for {
+		wait := d.deliverDue(ctx)
+		if draining && d.outbox.Len() == 0 {
+			return
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-drain:
+			timer.Stop()
+			draining, drain = true, nil
+		case <-d.outbox.added:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// deliverDue sends the responses that are due and returns how long to wait for the next one.
+// A round stops at the first response the aggregator can't be reached for, as the rest would
+// fail the same way, and the next one waits longer the more rounds failed in a row.
+func (d *outboxDelivery) deliverDue(ctx context.Context) time.Duration {
+	d.abandonExpired()
+	entries := d.outbox.Entries()
+	if d.metrics != nil {
+		d.metrics.SetOperatorOutboxPendingResponses(d.aggregator, float64(len(entries)))
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return 0
+		}
+		if time.Now().Before(entry.NextAttempt) {
+			continue
+		}
+		if err := d.deliver(ctx, entry); err != nil {
+			if ctx.Err() != nil {
+				return 0
+			}
+			d.setReachable(false)
+			wait := backoffInterval(d.unreachableRounds - 1)
+			d.logger.Warn("Aggregator is not reachable, signed responses are kept in the outbox",
+				"aggregator", d.aggregator, "pending", len(entries), "err", err, "retryIn", wait)
+			return min(wait, d.untilNextDeadline())
+		}
+		d.setReachable(true)
+	}
+
+	// Responses sent again later were rescheduled in the round
+	wait := min(OutboxMaxRetryInterval, d.untilNextDeadline())
+	if entries := d.outbox.Entries(); len(entries) > 0 {
+		wait = min(wait, max(time.Until(entries[0].NextAttempt), 0))
+	}
+	return wait
+}
+
+// abandonExpired gives up on the responses the aggregator no longer accepts, whether it can be
+// reached or not.
+func (d *outboxDelivery) abandonExpired() {
+	for _, entry := range d.outbox.Entries() {
+		if time.Now().Before(entry.Deadline) {
+			continue
+		}
+		d.logger.Warn("Task expired before the aggregator acknowledged the signed response, giving up on it",
+			"aggregator", d.aggregator, "batchIdentifierHash", entry.BatchIdentifierHash(), "attempts", entry.Attempts, "lastError", entry.LastError)
+		d.finish(entry.BatchIdentifierHash(), BatchAbandoned)
+	}
+}
+
+// untilNextDeadline returns how long until the first response of the outbox expires.
+func (d *outboxDelivery) untilNextDeadline() time.Duration {
+	wait := OutboxMaxRetryInterval
+	for _, entry := range d.outbox.Entries() {
+		wait = min(wait, max(time.Until(entry.Deadline), 0))
+	}
+	return wait
+}
+
+// deliver sends the response once. It only returns an error if the aggregator could not be reached.
+func (d *outboxDelivery) deliver(ctx context.Context, entry OutboxEntry) error {
+	batchIdentifierHash := entry.BatchIdentifierHash()
+	signedTaskResponse, err := entry.Response.ToSignedTaskResponse()
+	if err != nil {
+		d.logger.Error("Malformed signed response in the outbox, giving up on it", "aggregator", d.aggregator, "batchIdentifierHash", batchIdentifierHash, "err", err)
+		d.finish(batchIdentifierHash, BatchAbandoned)
+		return nil
+	}
+
+	reply, err := d.client.SubmitTaskResponse(ctx, signedTaskResponse)
+	if err != nil {
+		if aggregatorrpc.IsInvalidParams(err) {
+			d.logger.Error("Aggregator rejected the signed response as malformed", "aggregator", d.aggregator, "batchIdentifierHash", batchIdentifierHash, "err", err)
+			d.finish(batchIdentifierHash, BatchAbandoned)
+			return nil
+		}
+		return err
+	}
+
+	if d.metrics != nil {
+		d.metrics.IncOperatorTaskResponseOutcomes(d.aggregator, reply.Outcome.String())
+	}
+	switch reply.Outcome {
+	case types.TaskResponseAccepted:
+		d.logger.Info("Signed task response accepted by aggregator.", "aggregator", d.aggregator, "batchIdentifierHash", batchIdentifierHash)
+		d.finish(batchIdentifierHash, BatchDelivered)
+	case types.TaskResponseDuplicate, types.TaskResponseQuorumReached:
+		d.logger.Info("Signed task response no longer needed by aggregator", "aggregator", d.aggregator, "batchIdentifierHash", batchIdentifierHash,
+			"outcome", reply.Outcome, "message", reply.Message)
+		d.finish(batchIdentifierHash, BatchDelivered)
+	case types.TaskResponseTaskExpired:
+		d.logger.Warn("Signed task response sent after the task expired", "aggregator", d.aggregator, "batchIdentifierHash", batchIdentifierHash,
+			"message", reply.Message)
+		d.finish(batchIdentifierHash, BatchAbandoned)
+	case types.TaskResponseInvalidSignature:
+		d.logger.Error("Aggregator rejected the signature of the task response, check the BLS key is the one the operator registered",
+			"aggregator", d.aggregator, "batchIdentifierHash", batchIdentifierHash, "message", reply.Message)
+		d.finish(batchIdentifierHash, BatchAbandoned)
+	default:
+		entry.Attempts++
+		entry.LastError = fmt.Sprintf("%s: %s", reply.Outcome, reply.Message)
+		entry.NextAttempt = time.Now().Add(backoffInterval(entry.Attempts - 1))
+		d.logger.Warn("Aggregator could not process the signed task response yet", "aggregator", d.aggregator, "batchIdentifierHash", batchIdentifierHash,
+			"outcome", reply.Outcome, "message", reply.Message, "nextAttempt", entry.NextAttempt)
+		if err := d.outbox.Update(entry); err != nil {
+			d.logger.Errorf("Could not update outbox entry of batch %s for aggregator %s: %v", batchIdentifierHash, d.aggregator, err)
+		}
+	}
+	return nil
+}
+
+// setReachable tracks whether the last response sent reached the aggregator.
+func (d *outboxDelivery) setReachable(reachable bool) {
+	if reachable {
+		d.unreachableRounds = 0
+	} else {
+		d.unreachableRounds++
+	}
+	if d.metrics != nil {
+		d.metrics.SetOperatorAggregatorReachable(d.aggregator, reachable)
+	}
+}
+
+// finish removes the response from the outbox, once the batch reached stage.
+func (d *outboxDelivery) finish(batchIdentifierHash string, stage BatchStage) {
+	if err := d.outbox.Remove(batchIdentifierHash); err != nil {
+		d.logger.Errorf("Could not remove outbox entry of batch %s for aggregator %s: %v", batchIdentifierHash, d.aggregator, err)
+	}
+	if d.finished != nil {
+		d.finished(batchIdentifierHash, stage)
+	}
+}
+
+// backoffInterval returns how long to wait before the attempt after the given one, counting from zero.
+func backoffInterval(attempt int) time.Duration {
+	interval := OutboxInitialRetryInterval
+	for i := 0; i < attempt && interval < OutboxMaxRetryInterval; i++ {
+		interval *= 2
+	}
+	return min(interval, OutboxMaxRetryInterval)
+}
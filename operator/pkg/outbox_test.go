@@ -0,0 +1,252 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// testAggregatorClient replies to every response with reply, or fails with err.
+type testAggregatorClient struct {
+	mutex sync.Mutex
+	reply types.SignedTaskResponseReply
+	err   error
+	calls int
+}
+
+func (c *testAggregatorClient) Connect(_ context.Context) error {
+	return c.err
+}
+
+func (c *testAggregatorClient) SubmitTaskResponse(_ context.Context, _ *types.SignedTaskResponse) (types.SignedTaskResponseReply, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.calls++
+	return c.reply, c.err
+}
+
+func newTestOutbox(t *testing.T) (*Outbox, string) {
+	dir := filepath.Join(t.TempDir(), "operator.outbox")
+	outbox, err := OpenOutbox(dir)
+	if err != nil {
+		t.Fatalf("could not open outbox: %v", err)
+	}
+	return outbox, dir
+}
+
+func TestOutboxPersistsResponses(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	outbox, dir := newTestOutbox(t)
+	deadline := time.Now().Add(time.Hour)
+	for i := byte(1); i <= 3; i++ {
+		if err := outbox.Add(signedTaskResponse(keyPair, i), deadline); err != nil {
+			t.Fatalf("could not add response: %v", err)
+		}
+	}
+	delivered := BatchIdentifierHashHex([32]byte{1}, [20]byte{1})
+	if err := outbox.Remove(delivered); err != nil {
+		t.Fatalf("could not remove response: %v", err)
+	}
+	retried := outbox.Entries()[0]
+	retried.Attempts = 2
+	if err := outbox.Update(retried); err != nil {
+		t.Fatalf("could not update response: %v", err)
+	}
+	// Left by a write interrupted by a crash
+	os.WriteFile(filepath.Join(dir, "0x01.json.tmp-123"), []byte("{"), 0o644)
+
+	reopened, err := OpenOutbox(dir)
+	if err != nil {
+		t.Fatalf("could not reopen outbox: %v", err)
+	}
+	if reopened.Len() != 2 || reopened.Contains(delivered) {
+		t.Fatalf("expected the two responses not removed, got %d", reopened.Len())
+	}
+	for _, entry := range reopened.Entries() {
+		if entry.BatchIdentifierHash() == retried.BatchIdentifierHash() && entry.Attempts != 2 {
+			t.Errorf("expected delivery state to be kept, got %d attempts", entry.Attempts)
+		}
+		if _, err := entry.Response.ToSignedTaskResponse(); err != nil {
+			t.Errorf("expected response to be kept intact, got %v", err)
+		}
+	}
+}
+
+func TestOutboxPath(t *testing.T) {
+	if path := OutboxPath("", "config-files/operator.journal"); path != "config-files/operator.outbox" {
+		t.Errorf("expected outbox next to the batch journal, got %s", path)
+	}
+	if path := OutboxPath("/var/lib/outbox", "config-files/operator.journal"); path != "/var/lib/outbox" {
+		t.Errorf("expected configured outbox, got %s", path)
+	}
+}
+
+func TestOutboxDeliveryOutcomes(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	cases := []struct {
+		outcome types.TaskResponseOutcome
+		stage   BatchStage
+	}{
+		{types.TaskResponseAccepted, BatchDelivered},
+		{types.TaskResponseDuplicate, BatchDelivered},
+		{types.TaskResponseQuorumReached, BatchDelivered},
+		{types.TaskResponseTaskExpired, BatchAbandoned},
+		{types.TaskResponseInvalidSignature, BatchAbandoned},
+	}
+	for _, c := range cases {
+		outbox, _ := newTestOutbox(t)
+		outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+		var stage BatchStage
+		delivery := &outboxDelivery{
+			outbox:   outbox,
+			client:   &testAggregatorClient{reply: types.SignedTaskResponseReply{Outcome: c.outcome}},
+			logger:   logging.NewTextSLogger(io.Discard, nil),
+			finished: func(_ string, finishedStage BatchStage) { stage = finishedStage },
+		}
+		delivery.deliverDue(context.Background())
+		if stage != c.stage || outbox.Len() != 0 {
+			t.Errorf("expected %s to leave the batch %s and the outbox empty, got %s with %d responses", c.outcome, c.stage, stage, outbox.Len())
+		}
+	}
+
+	// Responses the aggregator can't process yet are sent again later
+	outbox, _ := newTestOutbox(t)
+	outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+	delivery := &outboxDelivery{
+		outbox: outbox,
+		client: &testAggregatorClient{reply: types.SignedTaskResponseReply{Outcome: types.TaskResponseUnknownTask}},
+		logger: logging.NewTextSLogger(io.Discard, nil),
+	}
+	if wait := delivery.deliverDue(context.Background()); wait > OutboxInitialRetryInterval {
+		t.Errorf("expected next attempt within %s, got %s", OutboxInitialRetryInterval, wait)
+	}
+	entries := outbox.Entries()
+	if len(entries) != 1 || entries[0].Attempts != 1 || !entries[0].NextAttempt.After(time.Now()) {
+		t.Errorf("expected response to be kept for another attempt, got %+v", entries)
+	}
+}
+
+func TestOutboxDeliveryWhileAggregatorIsDown(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	outbox, _ := newTestOutbox(t)
+	outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+	outbox.Add(signedTaskResponse(keyPair, 2), time.Now().Add(time.Hour))
+	client := &testAggregatorClient{err: errors.New("connection refused")}
+	delivery := &outboxDelivery{outbox: outbox, client: client, logger: logging.NewTextSLogger(io.Discard, nil)}
+
+	// Rounds stop at the first response that can't be sent, and wait longer every time
+	for round, expected := range []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		if wait := delivery.deliverDue(context.Background()); wait != expected {
+			t.Errorf("expected round %d to wait %s, got %s", round, expected, wait)
+		}
+	}
+	if client.calls != 3 || outbox.Len() != 2 {
+		t.Errorf("expected one attempt per round and the responses kept, got %d attempts and %d responses", client.calls, outbox.Len())
+	}
+
+	// Once the aggregator is back, every response is delivered
+	client.err = nil
+	client.reply = types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted}
+	delivery.deliverDue(context.Background())
+	if outbox.Len() != 0 || delivery.unreachableRounds != 0 {
+		t.Errorf("expected every response to be delivered, %d left", outbox.Len())
+	}
+}
+
+func TestOutboxDeliveryGivesUpAfterDeadline(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	outbox, _ := newTestOutbox(t)
+	outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(-time.Second))
+	client := &testAggregatorClient{reply: types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted}}
+	var stage BatchStage
+	delivery := &outboxDelivery{
+		outbox:   outbox,
+		client:   client,
+		logger:   logging.NewTextSLogger(io.Discard, nil),
+		finished: func(_ string, finishedStage BatchStage) { stage = finishedStage },
+	}
+	delivery.deliverDue(context.Background())
+	if stage != BatchAbandoned || outbox.Len() != 0 || client.calls != 0 {
+		t.Errorf("expected response to be given up on without sending it, got %s after %d attempts", stage, client.calls)
+	}
+}
+
+func TestOutboxDeliveryGivesUpOnExpiredTasksWhileAggregatorIsDown(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	outbox, _ := newTestOutbox(t)
+	outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(-time.Second))
+	outbox.Add(signedTaskResponse(keyPair, 2), time.Now().Add(500*time.Millisecond))
+	delivery := &outboxDelivery{
+		outbox: outbox,
+		client: &testAggregatorClient{err: errors.New("connection refused")},
+		logger: logging.NewTextSLogger(io.Discard, nil),
+	}
+
+	// The next round is when the other task expires, not after the whole backoff
+	if wait := delivery.deliverDue(context.Background()); wait > 500*time.Millisecond {
+		t.Errorf("expected next round by the deadline of the pending response, got %s", wait)
+	}
+	if outbox.Len() != 1 || outbox.Contains(BatchIdentifierHashHex([32]byte{1}, [20]byte{1})) {
+		t.Errorf("expected the response of the expired task to be given up on, %d left", outbox.Len())
+	}
+}
+
+// testHeaderClient returns headers of blocks created at time, or fails with err.
+type testHeaderClient struct {
+	time uint64
+	err  error
+}
+
+func (c *testHeaderClient) HeaderByNumber(_ context.Context, _ *big.Int) (*gethtypes.Header, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &gethtypes.Header{Time: c.time}, nil
+}
+
+func TestTaskResponseDeadline(t *testing.T) {
+	clients := []blockHeaderClient{&testHeaderClient{err: errors.New("connection refused")}, &testHeaderClient{time: 1_700_000_000}}
+	deadline, err := TaskResponseDeadline(context.Background(), clients, 100, time.Hour)
+	if err != nil || !deadline.Equal(time.Unix(1_700_000_000, 0).Add(time.Hour)) {
+		t.Errorf("expected deadline to be the task timeout after the block, read from the fallback, got %s, %v", deadline, err)
+	}
+	if _, err := TaskResponseDeadline(context.Background(), clients[:1], 100, time.Hour); err == nil {
+		t.Errorf("expected an error if no client has the block")
+	}
+}
+
+func TestOutboxDeliveryRunsOnNewResponses(t *testing.T) {
+	keyPair, _ := bls.NewKeyPairFromString("12345")
+	outbox, _ := newTestOutbox(t)
+	delivered := make(chan string, 1)
+	delivery := &outboxDelivery{
+		outbox:   outbox,
+		client:   &testAggregatorClient{reply: types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted}},
+		logger:   logging.NewTextSLogger(io.Discard, nil),
+		finished: func(batchIdentifierHash string, _ BatchStage) { delivered <- batchIdentifierHash },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go delivery.Run(ctx, nil)
+
+	outbox.Add(signedTaskResponse(keyPair, 1), time.Now().Add(time.Hour))
+	select {
+	case batchIdentifierHash := <-delivered:
+		if batchIdentifierHash != BatchIdentifierHashHex([32]byte{1}, [20]byte{1}) {
+			t.Errorf("expected the added response to be delivered, got %s", batchIdentifierHash)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the added response to be delivered right away")
+	}
+}
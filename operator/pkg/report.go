@@ -0,0 +1,287 @@
+package operator
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+const DefaultMaxVerificationReports = 1000
+
+// VerificationErrorCategory classifies why a batch or one of its proofs was not verified.
+type VerificationErrorCategory string
+
+const (
+	ErrorCategoryNone                 VerificationErrorCategory = ""
+	ErrorCategoryBatchDownload        VerificationErrorCategory = "batch_download"
+	ErrorCategoryMerkleRootMismatch   VerificationErrorCategory = "merkle_root_mismatch"
+	ErrorCategoryBatchDecoding        VerificationErrorCategory = "batch_decoding"
+	ErrorCategoryVerifierStatus       VerificationErrorCategory = "verifier_status"
+	ErrorCategoryDisabledVerifier     VerificationErrorCategory = "disabled_verifier"
+	ErrorCategoryUnknownProvingSystem VerificationErrorCategory = "unknown_proving_system"
+	ErrorCategoryMissingInput         VerificationErrorCategory = "missing_input"
+	ErrorCategoryDeserialization      VerificationErrorCategory = "deserialization"
+	ErrorCategoryVerifierPanic        VerificationErrorCategory = "verifier_panic"
+	ErrorCategoryVerificationFailed   VerificationErrorCategory = "verification_failed"
+	ErrorCategoryCancelled            VerificationErrorCategory = "cancelled"
+)
+
+var (
+	ErrInvalidProof   = errors.New("invalid proof")
+	ErrVerifierStatus = errors.New("could not check verifiers status")
+)
+
+// categorizeError maps the errors returned while downloading and verifying a batch to their category.
+// A nil error is categorized as a verification failure, since it's only categorized for rejected proofs.
+func categorizeError(err error) VerificationErrorCategory {
+	switch {
+	case err == nil:
+		return ErrorCategoryVerificationFailed
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ErrorCategoryCancelled
+	case errors.Is(err, ErrMerkleRootMismatch):
+		return ErrorCategoryMerkleRootMismatch
+	case errors.Is(err, ErrBatchDecoding):
+		return ErrorCategoryBatchDecoding
+	case errors.Is(err, ErrVerifierStatus):
+		return ErrorCategoryVerifierStatus
+	case errors.Is(err, ErrUnknownProvingSystem):
+		return ErrorCategoryUnknownProvingSystem
+	case errors.Is(err, ErrMissingInput):
+		return ErrorCategoryMissingInput
+	case errors.Is(err, ErrProofDeserialization):
+		return ErrorCategoryDeserialization
+	case errors.Is(err, ErrVerifierPanic):
+		return ErrorCategoryVerifierPanic
+	default:
+		return ErrorCategoryVerificationFailed
+	}
+}
+
+// ProofVerificationReport is the outcome of verifying a single proof of a batch.
+type ProofVerificationReport struct {
+	// Index of the proof in the batch
+	Index         int    `json:"index"`
+	ProvingSystem string `json:"proving_system"`
+	Verified      bool   `json:"verified"`
+	// Version of the verifier that accepted the proof
+	VerifierVersion string                    `json:"verifier_version,omitempty"`
+	ErrorCategory   VerificationErrorCategory `json:"error_category,omitempty"`
+	Error           string                    `json:"error,omitempty"`
+	Duration        time.Duration             `json:"duration_ns"`
+}
+
+// BatchVerificationReport records why the operator signed a batch or refused to.
+type BatchVerificationReport struct {
+	BatchMerkleRoot      string                    `json:"batch_merkle_root"`
+	SenderAddress        string                    `json:"sender_address"`
+	BatchIdentifierHash  string                    `json:"batch_identifier_hash"`
+	BlockNumber          uint64                    `json:"block_number"`
+	ReceivedAt           time.Time                 `json:"received_at"`
+	DownloadDuration     time.Duration             `json:"download_duration_ns"`
+	VerificationDuration time.Duration             `json:"verification_duration_ns"`
+	Verified             bool                      `json:"verified"`
+	ErrorCategory        VerificationErrorCategory `json:"error_category,omitempty"`
+	Error                string                    `json:"error,omitempty"`
+	Proofs               []ProofVerificationReport `json:"proofs"`
+}
+
+func NewBatchVerificationReport(batchMerkleRoot [32]byte, senderAddress [20]byte, blockNumber uint64) *BatchVerificationReport {
+	return &BatchVerificationReport{
+		BatchMerkleRoot:     "0x" + hex.EncodeToString(batchMerkleRoot[:]),
+		SenderAddress:       "0x" + hex.EncodeToString(senderAddress[:]),
+		BatchIdentifierHash: BatchIdentifierHashHex(batchMerkleRoot, senderAddress),
+		BlockNumber:         blockNumber,
+		ReceivedAt:          time.Now(),
+	}
+}
+
+// Finish sets the verdict of the batch. If the batch was rejected because of one of its
+// proofs, the report takes the error category of the first rejected proof.
+func (r *BatchVerificationReport) Finish(err error) {
+	r.Verified = err == nil
+	if err == nil {
+		return
+	}
+	r.Error = err.Error()
+	r.ErrorCategory = categorizeError(err)
+	if proof, ok := r.FirstRejectedProof(); ok && errors.Is(err, ErrInvalidProof) {
+		r.ErrorCategory = proof.ErrorCategory
+	}
+}
+
+// Rejected reports whether the batch was found invalid, as opposed to not being
+// verified because of an error that may not happen if the batch is processed again.
+func (r *BatchVerificationReport) Rejected() bool {
+	if r.Verified {
+		return false
+	}
+	switch r.ErrorCategory {
+	case ErrorCategoryBatchDownload, ErrorCategoryVerifierStatus, ErrorCategoryCancelled:
+		return false
+	default:
+		return true
+	}
+}
+
+// FirstRejectedProof returns the rejected proof with the lowest index, ignoring cancelled ones.
+func (r *BatchVerificationReport) FirstRejectedProof() (ProofVerificationReport, bool) {
+	for _, proof := range r.Proofs {
+		if !proof.Verified && proof.ErrorCategory != ErrorCategoryCancelled {
+			return proof, true
+		}
+	}
+	return ProofVerificationReport{}, false
+}
+
+// Matches reports whether id is the merkle root or the identifier hash of the batch.
+func (r *BatchVerificationReport) Matches(id string) bool {
+	id = strings.ToLower(id)
+	if !strings.HasPrefix(id, "0x") {
+		id = "0x" + id
+	}
+	return id == r.BatchIdentifierHash || id == r.BatchMerkleRoot
+}
+
+func provingSystemName(provingSystemId common.ProvingSystemId) string {
+	name, err := common.ProvingSystemIdToString(provingSystemId)
+	if err != nil {
+		return fmt.Sprintf("Unknown(%d)", provingSystemId)
+	}
+	return name
+}
+
+// ReportStore keeps the verification reports of the most recent batches.
+// If it has a directory, every report is also written there, one JSON file per batch
+// named after its identifier hash, so reports survive restarts and can be inspected
+// while the operator is running.
+type ReportStore struct {
+	dir        string
+	maxReports int
+	mutex      sync.RWMutex
+	// Oldest report first
+	reports []*BatchVerificationReport
+}
+
+// NewReportStore creates a store keeping up to maxReports reports, loading the ones already in dir.
+// Reports are only kept in memory if dir is empty.
+func NewReportStore(dir string, maxReports int) (*ReportStore, error) {
+	if maxReports <= 0 {
+		maxReports = DefaultMaxVerificationReports
+	}
+	store := &ReportStore{dir: dir, maxReports: maxReports}
+	if dir == "" {
+		return store, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create verification reports directory: %w", err)
+	}
+	reports, err := ReadReports(dir)
+	if err != nil {
+		return nil, err
+	}
+	store.reports = reports
+	store.evict()
+	return store, nil
+}
+
+// Save adds the report to the store, replacing a previous report of the same batch.
+func (s *ReportStore) Save(report *BatchVerificationReport) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, stored := range s.reports {
+		if stored.BatchIdentifierHash == report.BatchIdentifierHash {
+			s.reports = append(s.reports[:i], s.reports[i+1:]...)
+			break
+		}
+	}
+	s.reports = append(s.reports, report)
+	defer s.evict()
+
+	if s.dir == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal verification report: %w", err)
+	}
+	return writeFileAtomic(s.reportPath(report), data, 0o644)
+}
+
+// Get returns the report of the batch with the given merkle root or identifier hash.
+// If the same merkle root was sent by several senders, the most recent report is returned.
+func (s *ReportStore) Get(id string) (*BatchVerificationReport, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := len(s.reports) - 1; i >= 0; i-- {
+		if s.reports[i].Matches(id) {
+			return s.reports[i], true
+		}
+	}
+	return nil, false
+}
+
+// Recent returns up to n reports, most recent first.
+func (s *ReportStore) Recent(n int) []*BatchVerificationReport {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var reports []*BatchVerificationReport
+	for i := len(s.reports) - 1; i >= 0 && len(reports) < n; i-- {
+		reports = append(reports, s.reports[i])
+	}
+	return reports
+}
+
+// evict drops the oldest reports over the limit. Must be called with the mutex held.
+func (s *ReportStore) evict() {
+	for len(s.reports) > s.maxReports {
+		if s.dir != "" {
+			_ = os.Remove(s.reportPath(s.reports[0]))
+		}
+		s.reports = s.reports[1:]
+	}
+}
+
+func (s *ReportStore) reportPath(report *BatchVerificationReport) string {
+	return filepath.Join(s.dir, report.BatchIdentifierHash+".json")
+}
+
+// ReadReports reads the reports written to dir by a ReportStore, oldest first.
+func ReadReports(dir string) ([]*BatchVerificationReport, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*BatchVerificationReport
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read verification report %s: %w", path, err)
+		}
+		var report BatchVerificationReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("could not parse verification report %s: %w", path, err)
+		}
+		reports = append(reports, &report)
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].ReceivedAt.Before(reports[j].ReceivedAt)
+	})
+	return reports, nil
+}
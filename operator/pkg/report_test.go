@@ -0,0 +1,80 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCategorizeError(t *testing.T) {
+	cases := []struct {
+		err      error
+		category VerificationErrorCategory
+	}{
+		{nil, ErrorCategoryVerificationFailed},
+		{fmt.Errorf("sp1: %w", fmt.Errorf("%w: rust panic", ErrVerifierPanic)), ErrorCategoryVerifierPanic},
+		{fmt.Errorf("gnark: %w: PLONK proof: EOF", ErrProofDeserialization), ErrorCategoryDeserialization},
+		{fmt.Errorf("%w: proof", ErrMissingInput), ErrorCategoryMissingInput},
+		{fmt.Errorf("%w: 9", ErrUnknownProvingSystem), ErrorCategoryUnknownProvingSystem},
+		{fmt.Errorf("Error while verifying merkle tree batch: %w", ErrMerkleRootMismatch), ErrorCategoryMerkleRootMismatch},
+		{context.Canceled, ErrorCategoryCancelled},
+		{errors.New("unexpected"), ErrorCategoryVerificationFailed},
+	}
+	for _, c := range cases {
+		if category := categorizeError(c.err); category != c.category {
+			t.Errorf("expected %v to be categorized as %q, got %q", c.err, c.category, category)
+		}
+	}
+}
+
+func TestBatchVerificationReportFinish(t *testing.T) {
+	report := NewBatchVerificationReport([32]byte{1}, [20]byte{2}, 10)
+	report.Proofs = []ProofVerificationReport{
+		{Index: 0, Verified: true},
+		{Index: 1, ErrorCategory: ErrorCategoryCancelled},
+		{Index: 2, ErrorCategory: ErrorCategoryDisabledVerifier},
+	}
+	report.Finish(fmt.Errorf("%w: proof 2", ErrInvalidProof))
+
+	if report.Verified || report.ErrorCategory != ErrorCategoryDisabledVerifier {
+		t.Errorf("expected batch rejected with the category of proof 2, got %+v", report)
+	}
+	if proof, ok := report.FirstRejectedProof(); !ok || proof.Index != 2 {
+		t.Errorf("expected proof 2 to be the first rejected proof, got %+v", proof)
+	}
+}
+
+func TestReportStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewReportStore(dir, 2)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	var reports []*BatchVerificationReport
+	for i := byte(0); i < 3; i++ {
+		report := NewBatchVerificationReport([32]byte{i}, [20]byte{i}, uint64(i))
+		report.Finish(nil)
+		if err := store.Save(report); err != nil {
+			t.Fatalf("could not save report: %v", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if _, ok := store.Get(reports[0].BatchMerkleRoot); ok {
+		t.Errorf("oldest report should have been evicted")
+	}
+	if report, ok := store.Get(reports[2].BatchIdentifierHash); !ok || report.BlockNumber != 2 {
+		t.Errorf("expected report of block 2 by identifier hash, got %+v", report)
+	}
+
+	reloaded, err := NewReportStore(dir, 2)
+	if err != nil {
+		t.Fatalf("could not reload store: %v", err)
+	}
+	recent := reloaded.Recent(5)
+	if len(recent) != 2 || recent[0].BlockNumber != 2 || recent[1].BlockNumber != 1 {
+		t.Errorf("expected reports of blocks 2 and 1 after reload, got %d reports", len(recent))
+	}
+}
@@ -1,64 +1,168 @@
 package operator
 
 import (
+	"context"
 	"errors"
 	"net/rpc"
-	"time"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/aggregatorrpc"
 	"github.com/yetanotherco/aligned_layer/core/types"
 )
 
-// AggregatorRpcClient is the client to communicate with the aggregator via RPC
+// AggregatorClient sends the signed task responses of the operator to the aggregator.
+// Clients connect on first use, so the operator can start while the aggregator is down.
+type AggregatorClient interface {
+	// Connect connects to the aggregator, if the client is not connected yet.
+	Connect(ctx context.Context) error
+	// SubmitTaskResponse sends the response once and returns the reply of the aggregator.
+	// An error means no reply was received.
+	SubmitTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) (types.SignedTaskResponseReply, error)
+}
+
+// AggregatorRpcClient is the client to communicate with the aggregator via the legacy net/rpc endpoint
 type AggregatorRpcClient struct {
-	rpcClient            *rpc.Client
 	aggregatorIpPortAddr string
-	logger               logging.Logger
+	mutex                sync.Mutex
+	rpcClient            *rpc.Client
+	// Set once the aggregator is found not to serve ProcessOperatorSignedTaskResponseV3
+	legacyReply atomic.Bool
+	logger      logging.Logger
 }
 
-const (
-	MaxRetries    = 10
-	RetryInterval = 10 * time.Second
-)
+func NewAggregatorRpcClient(aggregatorIpPortAddr string, logger logging.Logger) *AggregatorRpcClient {
+	return &AggregatorRpcClient{
+		aggregatorIpPortAddr: aggregatorIpPortAddr,
+		logger:               logger,
+	}
+}
+
+func (c *AggregatorRpcClient) Connect(_ context.Context) error {
+	_, err := c.connection()
+	return err
+}
+
+// connection returns the connection to the aggregator, dialing it if there is none.
+func (c *AggregatorRpcClient) connection() (*rpc.Client, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.rpcClient == nil {
+		client, err := rpc.DialHTTP("tcp", c.aggregatorIpPortAddr)
+		if err != nil {
+			return nil, err
+		}
+		c.rpcClient = client
+		c.logger.Info("Connected to aggregator", "address", c.aggregatorIpPortAddr)
+	}
+	return c.rpcClient, nil
+}
+
+// SubmitTaskResponse calls ProcessOperatorSignedTaskResponseV3, or ProcessOperatorSignedTaskResponseV2
+// on aggregators that don't serve it yet, which only tell whether to send the response again.
+func (c *AggregatorRpcClient) SubmitTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) (types.SignedTaskResponseReply, error) {
+	var reply types.SignedTaskResponseReply
+	if !c.legacyReply.Load() {
+		err := c.call(ctx, "Aggregator.ProcessOperatorSignedTaskResponseV3", signedTaskResponse, &reply)
+		if !isMethodNotFound(err) {
+			return reply, err
+		}
+		c.logger.Warn("Aggregator doesn't serve ProcessOperatorSignedTaskResponseV3, using ProcessOperatorSignedTaskResponseV2")
+		c.legacyReply.Store(true)
+	}
+
+	var legacyReply uint8
+	if err := c.call(ctx, "Aggregator.ProcessOperatorSignedTaskResponseV2", signedTaskResponse, &legacyReply); err != nil {
+		return reply, err
+	}
+	if legacyReply == 0 {
+		return types.SignedTaskResponseReply{Outcome: types.TaskResponseAccepted}, nil
+	}
+	return types.SignedTaskResponseReply{Outcome: types.TaskResponseInternalError, Message: "aggregator could not process the response"}, nil
+}
 
-func NewAggregatorRpcClient(aggregatorIpPortAddr string, logger logging.Logger) (*AggregatorRpcClient, error) {
-	client, err := rpc.DialHTTP("tcp", aggregatorIpPortAddr)
+// call calls method until ctx is done. The connection is dropped on errors other than those
+// returned by the aggregator, so the next call dials it again.
+func (c *AggregatorRpcClient) call(ctx context.Context, method string, args any, reply any) error {
+	client, err := c.connection()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	select {
+	case call := <-client.Go(method, args, reply, make(chan *rpc.Call, 1)).Done:
+		var serverErr rpc.ServerError
+		if call.Error != nil && !errors.As(call.Error, &serverErr) {
+			c.dropConnection(client)
+		}
+		return call.Error
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	return &AggregatorRpcClient{
-		rpcClient:            client,
-		aggregatorIpPortAddr: aggregatorIpPortAddr,
-		logger:               logger,
-	}, nil
+func (c *AggregatorRpcClient) dropConnection(client *rpc.Client) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.rpcClient == client {
+		c.logger.Warn("Lost connection to aggregator", "address", c.aggregatorIpPortAddr)
+		c.rpcClient.Close()
+		c.rpcClient = nil
+	}
+}
+
+// isMethodNotFound reports whether the net/rpc call failed because the server doesn't serve the method.
+func isMethodNotFound(err error) bool {
+	var serverErr rpc.ServerError
+	return errors.As(err, &serverErr) && strings.HasPrefix(string(serverErr), "rpc: can't find method")
+}
+
+// AggregatorJsonRpcClient is the client to communicate with the aggregator via the versioned JSON-RPC protocol
+type AggregatorJsonRpcClient struct {
+	config aggregatorrpc.ClientConfig
+	mutex  sync.Mutex
+	client *aggregatorrpc.Client
+	logger logging.Logger
+}
+
+func NewAggregatorJsonRpcClient(config aggregatorrpc.ClientConfig, logger logging.Logger) *AggregatorJsonRpcClient {
+	return &AggregatorJsonRpcClient{config: config, logger: logger}
 }
 
-// SendSignedTaskResponseToAggregator is the method called by operators via RPC to send
-// their signed task response.
-func (c *AggregatorRpcClient) SendSignedTaskResponseToAggregator(signedTaskResponse *types.SignedTaskResponse) {
-	var reply uint8
-	for retries := 0; retries < MaxRetries; retries++ {
-		err := c.rpcClient.Call("Aggregator.ProcessOperatorSignedTaskResponseV2", signedTaskResponse, &reply)
+func (c *AggregatorJsonRpcClient) Connect(ctx context.Context) error {
+	_, err := c.connection(ctx)
+	return err
+}
+
+// connection returns the client of the aggregator, connecting to it if it is not connected yet.
+func (c *AggregatorJsonRpcClient) connection(ctx context.Context) (*aggregatorrpc.Client, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.client == nil {
+		client, err := aggregatorrpc.NewClient(ctx, c.config)
 		if err != nil {
-			c.logger.Error("Received error from aggregator", "err", err)
-			if errors.Is(err, rpc.ErrShutdown) {
-				c.logger.Error("Aggregator is shutdown. Reconnecting...")
-				client, err := rpc.DialHTTP("tcp", c.aggregatorIpPortAddr)
-				if err != nil {
-					c.logger.Error("Could not reconnect to aggregator", "err", err)
-					time.Sleep(RetryInterval)
-				} else {
-					c.rpcClient = client
-					c.logger.Info("Reconnected to aggregator")
-				}
-			} else {
-				c.logger.Infof("Received error from aggregator: %s. Retrying ProcessOperatorSignedTaskResponseV2 RPC call...", err)
-				time.Sleep(RetryInterval)
-			}
-		} else {
-			c.logger.Info("Signed task response header accepted by aggregator.", "reply", reply)
-			return
+			return nil, err
 		}
+		c.client = client
+		c.logger.Info("Connected to aggregator", "url", c.config.Url)
+	}
+	return c.client, nil
+}
+
+// SubmitTaskResponse sends the response with aligned_submitTaskResponseV1. The HTTP client
+// reconnects by itself, so the connection is kept on errors.
+func (c *AggregatorJsonRpcClient) SubmitTaskResponse(ctx context.Context, signedTaskResponse *types.SignedTaskResponse) (types.SignedTaskResponseReply, error) {
+	client, err := c.connection(ctx)
+	if err != nil {
+		return types.SignedTaskResponseReply{}, err
+	}
+	result, err := client.SubmitTaskResponse(ctx, signedTaskResponse)
+	if err != nil {
+		return types.SignedTaskResponseReply{}, err
 	}
+	return types.SignedTaskResponseReply{Outcome: result.TaskResponseOutcome(), Message: result.Message}, nil
 }
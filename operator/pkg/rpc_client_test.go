@@ -0,0 +1,96 @@
+package operator
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/yetanotherco/aligned_layer/core/types"
+)
+
+// legacyAggregator serves the net/rpc methods of aggregators that reply with a uint8.
+type legacyAggregator struct{}
+
+func (legacyAggregator) ProcessOperatorSignedTaskResponseV2(_ *types.SignedTaskResponse, reply *uint8) error {
+	*reply = 0
+	return nil
+}
+
+// outcomeAggregator serves the net/rpc methods of aggregators that reply with an outcome.
+type outcomeAggregator struct {
+	legacyAggregator
+}
+
+func (outcomeAggregator) ProcessOperatorSignedTaskResponseV3(_ *types.SignedTaskResponse, reply *types.SignedTaskResponseReply) error {
+	*reply = types.SignedTaskResponseReply{Outcome: types.TaskResponseDuplicate}
+	return nil
+}
+
+// startLegacyAggregator serves aggregator at address, a random local port if empty, and returns its address.
+func startLegacyAggregator(t *testing.T, aggregator any, address string) string {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Aggregator", aggregator); err != nil {
+		t.Fatalf("could not register aggregator: %v", err)
+	}
+	if address == "" {
+		address = "127.0.0.1:0"
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	go http.Serve(listener, server)
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().String()
+}
+
+func TestAggregatorRpcClientReplies(t *testing.T) {
+	logger := logging.NewTextSLogger(io.Discard, nil)
+	response := &types.SignedTaskResponse{}
+
+	client := NewAggregatorRpcClient(startLegacyAggregator(t, &outcomeAggregator{}, ""), logger)
+	reply, err := client.SubmitTaskResponse(context.Background(), response)
+	if err != nil || reply.Outcome != types.TaskResponseDuplicate {
+		t.Errorf("expected outcome of the aggregator, got %+v, %v", reply, err)
+	}
+
+	// Aggregators that don't reply with outcomes only tell whether the response was accepted
+	client = NewAggregatorRpcClient(startLegacyAggregator(t, &legacyAggregator{}, ""), logger)
+	for i := 0; i < 2; i++ {
+		reply, err = client.SubmitTaskResponse(context.Background(), response)
+		if err != nil || reply.Outcome != types.TaskResponseAccepted {
+			t.Errorf("expected legacy reply to be accepted, got %+v, %v", reply, err)
+		}
+	}
+	if !client.legacyReply.Load() {
+		t.Errorf("expected client to remember the aggregator replies with a uint8")
+	}
+}
+
+func TestAggregatorRpcClientConnectsOnceAggregatorIsUp(t *testing.T) {
+	// A free local port, which nothing listens on yet
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	client := NewAggregatorRpcClient(address, logging.NewTextSLogger(io.Discard, nil))
+	if err := client.Connect(context.Background()); err == nil {
+		t.Fatalf("expected connecting to a down aggregator to fail")
+	}
+	if _, err := client.SubmitTaskResponse(context.Background(), &types.SignedTaskResponse{}); err == nil {
+		t.Fatalf("expected sending to a down aggregator to fail")
+	}
+
+	startLegacyAggregator(t, &outcomeAggregator{}, address)
+	reply, err := client.SubmitTaskResponse(context.Background(), &types.SignedTaskResponse{})
+	if err != nil || reply.Outcome != types.TaskResponseDuplicate {
+		t.Errorf("expected client to connect once the aggregator is up, got %+v, %v", reply, err)
+	}
+}
@@ -2,100 +2,56 @@ package operator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"time"
 
+	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/ugorji/go/codec"
-	"github.com/yetanotherco/aligned_layer/operator/merkle_tree"
 )
 
-func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, maxRetries int, retryDelay time.Duration) ([]VerificationData, error) {
-	o.Logger.Infof("Getting batch from data service, batchURL: %s", batchURL)
-
-	var resp *http.Response
-	var err error
-	var req *http.Request
+var (
+	ErrMerkleRootMismatch = errors.New("batch does not match the expected merkle root")
+	ErrBatchDecoding      = errors.New("could not decode batch")
+)
 
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		if attempt > 0 {
-			o.Logger.Infof("Waiting for %s before retrying data fetch (attempt %d of %d)", retryDelay, attempt+1, maxRetries)
-			select {
-			case <-time.After(retryDelay):
-				// Wait before retrying
-			case <-ctx.Done():
-				return nil, ctx.Err()
+// readBatch hands the batch over to consume, reading it from the batch cache if it is there,
+// or downloading it from batchURL otherwise, in which case it is also stored in the cache once
+// consume accepts it. consume must check the batch matches expectedMerkleRoot.
+func (o *Operator) readBatch(ctx context.Context, batchURL string, expectedMerkleRoot [32]byte, consume func(batch io.Reader) error) error {
+	if o.batchCache != nil {
+		if cachedBatch, ok := o.batchCache.Open(expectedMerkleRoot); ok {
+			o.Logger.Infof("Batch %x found in the batch cache", expectedMerkleRoot)
+			err := consume(cachedBatch)
+			cachedBatch.Close()
+			if err == nil {
+				return nil
 			}
-			retryDelay *= 2 // Exponential backoff. Ex: 5s, 10s, 20s
+			o.Logger.Warnf("Could not read batch %x from the batch cache, downloading it: %v", expectedMerkleRoot, err)
+			o.batchCache.Remove(expectedMerkleRoot)
 		}
+	}
 
-		req, err = http.NewRequestWithContext(ctx, "GET", batchURL, nil)
-		if err != nil {
-			return nil, err
-		}
+	o.Logger.Infof("Getting batch from data service, batchURL: %s", batchURL)
 
-		resp, err = http.DefaultClient.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			break // Successful request, exit retry loop
+	return o.batchDownloader.Stream(ctx, batchURL, BatchDownloadMaxRetries, BatchDownloadRetryDelay, func(batch io.Reader) error {
+		if o.batchCache == nil {
+			return consume(batch)
 		}
 
-		if resp != nil {
-			err := resp.Body.Close()
-			if err != nil {
-				return nil, err
-			}
+		cacheWriter := o.batchCache.Create(expectedMerkleRoot)
+		if err := consume(io.TeeReader(batch, cacheWriter)); err != nil {
+			cacheWriter.Abort()
+			return err
 		}
-
-		o.Logger.Warnf("Error fetching batch from data service - (attempt %d): %v", attempt+1, err)
-	}
-
-	if err != nil {
-		return nil, err
-	}
-
-	// At this point, the HTTP request was successfull.
-
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			fmt.Println("error closing body: ", err)
+		if err := cacheWriter.Commit(); err != nil {
+			o.Logger.Warnf("Could not store batch %x in the batch cache: %v", expectedMerkleRoot, err)
 		}
-	}(resp.Body)
-
-	// Check if the response is OK
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error getting batch from data service: %s", resp.Status)
-	}
-
-	contentLength := resp.ContentLength
-	if contentLength > o.Config.Operator.MaxBatchSize {
-		return nil, fmt.Errorf("proof size %d exceeds max batch size %d",
-			contentLength, o.Config.Operator.MaxBatchSize)
-	}
-
-	// Use io.LimitReader to limit the size of the response body
-	// This is to prevent the operator from downloading a larger than expected file
-	// + 1 is added to the contentLength to check if the response body is larger than expected
-	reader := io.LimitedReader{R: resp.Body, N: contentLength + 1}
-	batchBytes, err := io.ReadAll(&reader)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if the response body is larger than expected
-	if reader.N <= 0 {
-		return nil, fmt.Errorf("batch size exceeds max batch size %d", o.Config.Operator.MaxBatchSize)
-	}
-
-	// Checks if downloaded merkle root is the same as the expected one
-	o.Logger.Infof("Verifying batch merkle tree...")
-	merkle_root_check, err := merkle_tree.VerifyMerkleTreeBatch(batchBytes, expectedMerkleRoot)
-	if err != nil || !merkle_root_check {
-		return nil, fmt.Errorf("Error while verifying merkle tree batch")
-	}
-	o.Logger.Infof("Batch merkle tree verified")
+		return nil
+	})
+}
 
+func decodeBatch(batchBytes []byte, logger logging.Logger) ([]VerificationData, error) {
 	var batch []VerificationData
 
 	decoder, err := createDecoderMode()
@@ -105,12 +61,12 @@ func (o *Operator) getBatchFromDataService(ctx context.Context, batchURL string,
 	err = decoder.Unmarshal(batchBytes, &batch)
 
 	if err != nil {
-		o.Logger.Infof("Error decoding batch as CBOR: %s. Trying JSON decoding...", err)
+		logger.Infof("Error decoding batch as CBOR: %s. Trying JSON decoding...", err)
 		// try json
 		decoder := codec.NewDecoderBytes(batchBytes, new(codec.JsonHandle))
 		err = decoder.Decode(&batch)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %v", ErrBatchDecoding, err)
 		}
 	}
 
@@ -0,0 +1,149 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/metrics"
+)
+
+// VerificationScheduler is shared by all the batches the operator processes.
+// It bounds how many proofs are verified at the same time, both globally and
+// per proving system, so that several large batches arriving together can't
+// exhaust the resources of the operator host. It also bounds how many proofs
+// wait for a worker, so batches are decoded no faster than they are verified.
+type VerificationScheduler struct {
+	// Held by every task from when its proving system slot is taken until it finishes
+	tasks              chan struct{}
+	workers            chan struct{}
+	provingSystemSlots map[common.ProvingSystemId]chan struct{}
+	queueDepth         atomic.Int64
+	metrics            *metrics.Metrics
+	// Proofs of the batches being verified that are not verified yet, including the ones still to be read
+	backlog atomic.Int64
+}
+
+// NewVerificationScheduler creates a scheduler running at most maxWorkers verifications at once,
+// with as many waiting for a worker. If maxWorkers is not positive, the number of CPUs is used.
+// Proving systems without a positive limit in provingSystemLimits are only bounded by the global limit.
+func NewVerificationScheduler(maxWorkers int, provingSystemLimits map[common.ProvingSystemId]int, metrics *metrics.Metrics) *VerificationScheduler {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+
+	provingSystemSlots := make(map[common.ProvingSystemId]chan struct{})
+	for provingSystemId, limit := range provingSystemLimits {
+		if limit > 0 {
+			provingSystemSlots[provingSystemId] = make(chan struct{}, limit)
+		}
+	}
+
+	return &VerificationScheduler{
+		tasks:              make(chan struct{}, 2*maxWorkers),
+		workers:            make(chan struct{}, maxWorkers),
+		provingSystemSlots: provingSystemSlots,
+		metrics:            metrics,
+	}
+}
+
+// Submit queues task, blocking until a slot for its proving system is available and the
+// queue has room, and returns once it is queued. The proving system slot is taken first, so
+// proofs waiting on the limit of their proving system don't fill the queue shared by the
+// others. The task is run in its own goroutine once a global worker is available.
+// If ctx is cancelled while the task is still queued, the task is run right away without
+// taking a worker, only so it can account for the cancellation: tasks must check ctx before
+// doing any work.
+func (s *VerificationScheduler) Submit(ctx context.Context, provingSystemId common.ProvingSystemId, task func(ctx context.Context)) {
+	s.updateQueueDepth(1)
+	releaseProvingSystem, err := s.acquireProvingSystem(ctx, provingSystemId)
+	if err == nil {
+		select {
+		case s.tasks <- struct{}{}:
+		case <-ctx.Done():
+			releaseProvingSystem()
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		s.updateQueueDepth(-1)
+		task(ctx)
+		return
+	}
+
+	go func() {
+		defer func() { <-s.tasks }()
+		defer releaseProvingSystem()
+		err := s.acquireWorker(ctx)
+		s.updateQueueDepth(-1)
+		if err == nil {
+			defer func() { <-s.workers }()
+		}
+		task(ctx)
+	}()
+}
+
+// QueueDepth returns the number of submitted tasks waiting for a worker.
+func (s *VerificationScheduler) QueueDepth() int64 {
+	return s.queueDepth.Load()
+}
+
+// Backlog returns the number of proofs of the batches being verified that are not verified yet.
+// Unlike the queue depth, which can't grow past the size of the queue, it also counts the proofs
+// of the batches still to be read.
+func (s *VerificationScheduler) Backlog() int64 {
+	return s.backlog.Load()
+}
+
+// acquireProvingSystem blocks until a slot for the proving system is available, or ctx is
+// cancelled, and returns the function that frees it. Proving systems without a limit don't
+// wait. The proving system slot is taken before the global worker, so a task waiting on its
+// proving system limit doesn't hold a global worker that other proving systems could use.
+func (s *VerificationScheduler) acquireProvingSystem(ctx context.Context, provingSystemId common.ProvingSystemId) (func(), error) {
+	provingSystemSlot, limited := s.provingSystemSlots[provingSystemId]
+	if !limited {
+		return func() {}, nil
+	}
+	select {
+	case provingSystemSlot <- struct{}{}:
+		return func() { <-provingSystemSlot }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// acquireWorker blocks until a global worker is available, or ctx is cancelled.
+func (s *VerificationScheduler) acquireWorker(ctx context.Context) error {
+	select {
+	case s.workers <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *VerificationScheduler) updateQueueDepth(delta int64) {
+	depth := s.queueDepth.Add(delta)
+	if s.metrics != nil {
+		s.metrics.SetOperatorVerificationQueueDepth(float64(depth))
+	}
+}
+
+// ParseProvingSystemLimits converts the per proving system limits of the config file,
+// keyed by proving system name, into limits keyed by proving system id.
+func ParseProvingSystemLimits(limits map[string]int) (map[common.ProvingSystemId]int, error) {
+	provingSystemLimits := make(map[common.ProvingSystemId]int)
+	for provingSystem, limit := range limits {
+		provingSystemId, err := common.ProvingSystemIdFromString(provingSystem)
+		if err != nil {
+			return nil, err
+		}
+		if limit < 0 {
+			return nil, fmt.Errorf("invalid verification limit %d for proving system %s", limit, provingSystem)
+		}
+		provingSystemLimits[provingSystemId] = limit
+	}
+	return provingSystemLimits, nil
+}
@@ -0,0 +1,176 @@
+package operator
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+// concurrencyTracker records the highest number of tasks running at the same time.
+type concurrencyTracker struct {
+	running atomic.Int64
+	max     atomic.Int64
+}
+
+func (c *concurrencyTracker) enter() {
+	running := c.running.Add(1)
+	for {
+		max := c.max.Load()
+		if running <= max || c.max.CompareAndSwap(max, running) {
+			return
+		}
+	}
+}
+
+func (c *concurrencyTracker) exit() {
+	c.running.Add(-1)
+}
+
+func TestVerificationSchedulerLimits(t *testing.T) {
+	scheduler := NewVerificationScheduler(3, map[common.ProvingSystemId]int{common.SP1: 1}, nil)
+
+	var global, sp1 concurrencyTracker
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		provingSystemId := common.Groth16Bn254
+		if i%2 == 0 {
+			provingSystemId = common.SP1
+		}
+		wg.Add(1)
+		scheduler.Submit(context.Background(), provingSystemId, func(context.Context) {
+			defer wg.Done()
+			global.enter()
+			defer global.exit()
+			if provingSystemId == common.SP1 {
+				sp1.enter()
+				defer sp1.exit()
+			}
+			time.Sleep(10 * time.Millisecond)
+		})
+	}
+	wg.Wait()
+
+	if max := global.max.Load(); max > 3 {
+		t.Errorf("global worker limit exceeded: %d tasks ran concurrently", max)
+	}
+	if max := sp1.max.Load(); max > 1 {
+		t.Errorf("SP1 limit exceeded: %d tasks ran concurrently", max)
+	}
+	if depth := scheduler.QueueDepth(); depth != 0 {
+		t.Errorf("expected an empty queue after all tasks finished, got %d", depth)
+	}
+}
+
+func TestVerificationSchedulerCancelledWhileQueued(t *testing.T) {
+	scheduler := NewVerificationScheduler(1, nil, nil)
+
+	release := make(chan struct{})
+	scheduler.Submit(context.Background(), common.SP1, func(context.Context) { <-release })
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	scheduler.Submit(ctx, common.SP1, func(ctx context.Context) { done <- ctx.Err() })
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("queued task should have seen its context cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("cancelled task was not released from the queue")
+	}
+}
+
+func TestVerificationSchedulerBlocksSubmitWhenQueueIsFull(t *testing.T) {
+	scheduler := NewVerificationScheduler(1, nil, nil)
+
+	// One task running and one waiting for the worker fill the queue
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		scheduler.Submit(context.Background(), common.SP1, func(context.Context) { <-release })
+	}
+
+	submitted := make(chan struct{})
+	ran := make(chan struct{})
+	go func() {
+		scheduler.Submit(context.Background(), common.SP1, func(context.Context) { close(ran) })
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+		t.Fatalf("expected submit to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		t.Fatalf("expected submit to return once the queue has room")
+	}
+	<-ran
+
+	// A submit blocked on a full queue returns once its context is cancelled
+	block := make(chan struct{})
+	defer close(block)
+	for i := 0; i < 2; i++ {
+		scheduler.Submit(context.Background(), common.SP1, func(context.Context) { <-block })
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	var taskErr error
+	scheduler.Submit(ctx, common.SP1, func(ctx context.Context) { taskErr = ctx.Err() })
+	if taskErr == nil {
+		t.Errorf("expected the task to see its context cancelled")
+	}
+}
+
+func TestVerificationSchedulerLimitedProvingSystemDoesNotFillQueue(t *testing.T) {
+	scheduler := NewVerificationScheduler(2, map[common.ProvingSystemId]int{common.SP1: 1}, nil)
+
+	// A batch full of SP1 proofs waits on the SP1 limit
+	release := make(chan struct{})
+	defer close(release)
+	go func() {
+		for i := 0; i < 10; i++ {
+			scheduler.Submit(context.Background(), common.SP1, func(context.Context) { <-release })
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ran := make(chan struct{})
+	submitted := make(chan struct{})
+	go func() {
+		scheduler.Submit(context.Background(), common.Groth16Bn254, func(context.Context) { close(ran) })
+		close(submitted)
+	}()
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatalf("expected proofs of other proving systems to be verified while SP1 proofs wait on their limit")
+	}
+	<-submitted
+}
+
+func TestParseProvingSystemLimits(t *testing.T) {
+	limits, err := ParseProvingSystemLimits(map[string]int{"SP1": 2, "Risc0": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limits[common.SP1] != 2 || limits[common.Risc0] != 1 {
+		t.Errorf("unexpected limits %v", limits)
+	}
+
+	if _, err := ParseProvingSystemLimits(map[string]int{"Halo2": 1}); err == nil {
+		t.Errorf("expected error for unknown proving system")
+	}
+	if _, err := ParseProvingSystemLimits(map[string]int{"SP1": -1}); err == nil {
+		t.Errorf("expected error for negative limit")
+	}
+}
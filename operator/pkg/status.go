@@ -0,0 +1,171 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+// StatusChainReader is the part of the AvsReader the status of an operator is read from.
+type StatusChainReader interface {
+	IsOperatorRegistered(address ethcommon.Address) (bool, error)
+	GetOperatorId(opts *bind.CallOpts, operatorAddress ethcommon.Address) ([32]byte, error)
+	GetOperatorStakeInQuorumsOfOperatorAtCurrentBlock(opts *bind.CallOpts, operatorId eigentypes.OperatorId) (map[eigentypes.QuorumNum]eigentypes.StakeAmount, error)
+	DisabledVerifiers() (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	GetNotRespondedTasksFrom(fromBlock uint64) ([]*chainio.NewBatch, error)
+}
+
+// OperatorStatusConfig holds what the status of an operator is read for.
+type OperatorStatusConfig struct {
+	Address           ethcommon.Address
+	AggregatorAddress string
+	// Path of the batch journal, the last processed block is not reported if empty
+	BatchJournalPath string
+	// Number of blocks before the chain head to look for unresponded tasks in
+	UnrespondedTasksBlocks uint64
+}
+
+// OperatorStatus is the on-chain and local state of an operator. Parts that could not be
+// read are left empty, with the reason in Errors.
+type OperatorStatus struct {
+	Address                 ethcommon.Address   `json:"address"`
+	Registered              bool                `json:"registered"`
+	OperatorId              string              `json:"operator_id,omitempty"`
+	StakePerQuorum          []QuorumStake       `json:"stake_per_quorum"`
+	DisabledVerifiersBitmap string              `json:"disabled_verifiers_bitmap,omitempty"`
+	DisabledVerifiers       []string            `json:"disabled_verifiers"`
+	ChainHead               uint64              `json:"chain_head"`
+	LastProcessedBlock      uint64              `json:"last_processed_block"`
+	BlocksBehindHead        uint64              `json:"blocks_behind_head"`
+	PendingBatches          int                 `json:"pending_batches"`
+	UnrespondedTasks        []UnrespondedTask   `json:"unresponded_tasks"`
+	UnrespondedTasksFrom    uint64              `json:"unresponded_tasks_from_block"`
+	Aggregator              AggregatorReachable `json:"aggregator"`
+	Errors                  []string            `json:"errors,omitempty"`
+}
+
+type QuorumStake struct {
+	Quorum uint8    `json:"quorum"`
+	Stake  *big.Int `json:"stake"`
+}
+
+// UnrespondedTask is a batch created on chain whose task was not responded yet.
+type UnrespondedTask struct {
+	BatchMerkleRoot  ethcommon.Hash    `json:"batch_merkle_root"`
+	SenderAddress    ethcommon.Address `json:"sender_address"`
+	BlockNumber      uint64            `json:"block_number"`
+	TaskCreatedBlock uint32            `json:"task_created_block"`
+	EventVersion     uint8             `json:"event_version"`
+	// Stage the batch reached in the batch journal, empty if the operator never saw it
+	LocalStage BatchStage `json:"local_stage,omitempty"`
+}
+
+type AggregatorReachable struct {
+	Address   string `json:"address"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ReadOperatorStatus reads the status of the operator, carrying on with the rest of it when a part can't be read.
+func ReadOperatorStatus(ctx context.Context, reader StatusChainReader, statusConfig OperatorStatusConfig) *OperatorStatus {
+	status := &OperatorStatus{
+		Address:           statusConfig.Address,
+		StakePerQuorum:    []QuorumStake{},
+		DisabledVerifiers: []string{},
+		UnrespondedTasks:  []UnrespondedTask{},
+	}
+	fail := func(format string, args ...interface{}) {
+		status.Errors = append(status.Errors, fmt.Sprintf(format, args...))
+	}
+
+	registered, err := reader.IsOperatorRegistered(statusConfig.Address)
+	if err != nil {
+		fail("could not check registration: %v", err)
+	}
+	status.Registered = registered
+
+	if registered {
+		operatorId, err := reader.GetOperatorId(&bind.CallOpts{Context: ctx}, statusConfig.Address)
+		if err != nil {
+			fail("could not get operator id: %v", err)
+		} else {
+			status.OperatorId = "0x" + ethcommon.Bytes2Hex(operatorId[:])
+			stakes, err := reader.GetOperatorStakeInQuorumsOfOperatorAtCurrentBlock(&bind.CallOpts{Context: ctx}, operatorId)
+			if err != nil {
+				fail("could not get stake per quorum: %v", err)
+			}
+			for quorum, stake := range stakes {
+				status.StakePerQuorum = append(status.StakePerQuorum, QuorumStake{Quorum: uint8(quorum), Stake: stake})
+			}
+			sort.Slice(status.StakePerQuorum, func(a, b int) bool {
+				return status.StakePerQuorum[a].Quorum < status.StakePerQuorum[b].Quorum
+			})
+		}
+	}
+
+	disabledVerifiersBitmap, err := reader.DisabledVerifiers()
+	if err != nil {
+		fail("could not get disabled verifiers: %v", err)
+	} else {
+		status.DisabledVerifiersBitmap = "0x" + disabledVerifiersBitmap.Text(16)
+		status.DisabledVerifiers = DisabledVerifierNames(disabledVerifiersBitmap)
+	}
+
+	var journal *BatchJournal
+	if statusConfig.BatchJournalPath != "" {
+		journal, err = ReadBatchJournal(statusConfig.BatchJournalPath)
+		if err != nil {
+			fail("could not read batch journal: %v", err)
+		} else {
+			status.LastProcessedBlock = journal.LastSeenBlock()
+			status.PendingBatches = len(journal.Pending())
+		}
+	}
+
+	status.ChainHead, err = reader.BlockNumber(ctx)
+	if err != nil {
+		fail("could not get chain head: %v", err)
+	} else {
+		if status.LastProcessedBlock != 0 && status.ChainHead > status.LastProcessedBlock {
+			status.BlocksBehindHead = status.ChainHead - status.LastProcessedBlock
+		}
+		if status.ChainHead > statusConfig.UnrespondedTasksBlocks {
+			status.UnrespondedTasksFrom = status.ChainHead - statusConfig.UnrespondedTasksBlocks
+		}
+		tasks, err := reader.GetNotRespondedTasksFrom(status.UnrespondedTasksFrom)
+		if err != nil {
+			fail("could not get unresponded tasks: %v", err)
+		}
+		for _, task := range tasks {
+			unrespondedTask := UnrespondedTask{
+				BatchMerkleRoot:  task.BatchMerkleRoot,
+				SenderAddress:    task.SenderAddress,
+				BlockNumber:      task.Raw.BlockNumber,
+				TaskCreatedBlock: task.TaskCreatedBlock,
+				EventVersion:     task.EventVersion,
+			}
+			if journal != nil {
+				unrespondedTask.LocalStage, _ = journal.Stage(BatchIdentifierHashHex(task.BatchMerkleRoot, task.SenderAddress))
+			}
+			status.UnrespondedTasks = append(status.UnrespondedTasks, unrespondedTask)
+		}
+	}
+
+	status.Aggregator.Address = statusConfig.AggregatorAddress
+	probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+	if err := probeAggregator(probeCtx, statusConfig.AggregatorAddress); err != nil {
+		status.Aggregator.Error = err.Error()
+	} else {
+		status.Aggregator.Reachable = true
+	}
+
+	return status
+}
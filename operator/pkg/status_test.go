@@ -0,0 +1,159 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+
+	eigentypes "github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yetanotherco/aligned_layer/core/chainio"
+)
+
+type fakeStatusChainReader struct {
+	registered        bool
+	stakes            map[eigentypes.QuorumNum]eigentypes.StakeAmount
+	disabledVerifiers *big.Int
+	head              uint64
+	unrespondedTasks  []*chainio.NewBatch
+	tasksFrom         uint64
+}
+
+func (r *fakeStatusChainReader) IsOperatorRegistered(address ethcommon.Address) (bool, error) {
+	return r.registered, nil
+}
+
+func (r *fakeStatusChainReader) GetOperatorId(opts *bind.CallOpts, operatorAddress ethcommon.Address) ([32]byte, error) {
+	return [32]byte{0xab}, nil
+}
+
+func (r *fakeStatusChainReader) GetOperatorStakeInQuorumsOfOperatorAtCurrentBlock(opts *bind.CallOpts, operatorId eigentypes.OperatorId) (map[eigentypes.QuorumNum]eigentypes.StakeAmount, error) {
+	return r.stakes, nil
+}
+
+func (r *fakeStatusChainReader) DisabledVerifiers() (*big.Int, error) {
+	if r.disabledVerifiers == nil {
+		return nil, errors.New("execution reverted")
+	}
+	return r.disabledVerifiers, nil
+}
+
+func (r *fakeStatusChainReader) BlockNumber(ctx context.Context) (uint64, error) {
+	return r.head, nil
+}
+
+func (r *fakeStatusChainReader) GetNotRespondedTasksFrom(fromBlock uint64) ([]*chainio.NewBatch, error) {
+	r.tasksFrom = fromBlock
+	return r.unrespondedTasks, nil
+}
+
+func TestReadOperatorStatus(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "operator.batch_journal")
+	journal, err := OpenBatchJournal(journalPath, 0)
+	if err != nil {
+		t.Fatalf("could not open journal: %v", err)
+	}
+	seen := journaledBatch(1, 90)
+	journal.RecordSeen(seen)
+	journal.Record(seen.BatchIdentifierHash(), BatchVerified)
+
+	aggregator, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	defer aggregator.Close()
+
+	notSeen := journaledBatch(2, 95).ToNewBatch()
+	notSeen.Raw = types.Log{BlockNumber: 95}
+	reader := &fakeStatusChainReader{
+		registered: true,
+		stakes: map[eigentypes.QuorumNum]eigentypes.StakeAmount{
+			1: big.NewInt(20),
+			0: big.NewInt(10),
+		},
+		disabledVerifiers: big.NewInt(0b1010),
+		head:              100,
+		unrespondedTasks:  []*chainio.NewBatch{seen.ToNewBatch(), notSeen},
+	}
+
+	// The operator keeps the journal open while the status is read
+	status := ReadOperatorStatus(context.Background(), reader, OperatorStatusConfig{
+		AggregatorAddress:      aggregator.Addr().String(),
+		BatchJournalPath:       journalPath,
+		UnrespondedTasksBlocks: 50,
+	})
+	if len(status.Errors) != 0 {
+		t.Fatalf("unexpected errors reading status: %v", status.Errors)
+	}
+
+	if !status.Registered || status.OperatorId != "0xab00000000000000000000000000000000000000000000000000000000000000" {
+		t.Errorf("unexpected registration %t %s", status.Registered, status.OperatorId)
+	}
+	if len(status.StakePerQuorum) != 2 || status.StakePerQuorum[0].Quorum != 0 || status.StakePerQuorum[1].Stake.Int64() != 20 {
+		t.Errorf("expected stakes sorted by quorum, got %v", status.StakePerQuorum)
+	}
+	if status.DisabledVerifiersBitmap != "0xa" || len(status.DisabledVerifiers) != 2 ||
+		status.DisabledVerifiers[0] != "GnarkPlonkBn254" || status.DisabledVerifiers[1] != "SP1" {
+		t.Errorf("unexpected disabled verifiers %s %v", status.DisabledVerifiersBitmap, status.DisabledVerifiers)
+	}
+	if status.LastProcessedBlock != 90 || status.BlocksBehindHead != 10 || status.PendingBatches != 1 {
+		t.Errorf("unexpected progress: last processed %d, behind %d, pending %d",
+			status.LastProcessedBlock, status.BlocksBehindHead, status.PendingBatches)
+	}
+	if reader.tasksFrom != 50 || status.UnrespondedTasksFrom != 50 {
+		t.Errorf("expected unresponded tasks from block 50, got %d", reader.tasksFrom)
+	}
+	if len(status.UnrespondedTasks) != 2 || status.UnrespondedTasks[0].LocalStage != BatchVerified ||
+		status.UnrespondedTasks[1].LocalStage != "" || status.UnrespondedTasks[1].BlockNumber != 95 {
+		t.Errorf("unexpected unresponded tasks %v", status.UnrespondedTasks)
+	}
+	if !status.Aggregator.Reachable {
+		t.Errorf("expected aggregator to be reachable: %s", status.Aggregator.Error)
+	}
+
+	// Reading the status must leave the journal of the operator untouched
+	journal.Record(seen.BatchIdentifierHash(), BatchSigned)
+	journal.Close()
+	reopened, err := OpenBatchJournal(journalPath, 0)
+	if err != nil {
+		t.Fatalf("could not reopen journal: %v", err)
+	}
+	defer reopened.Close()
+	if stage, _ := reopened.Stage(seen.BatchIdentifierHash()); stage != BatchSigned {
+		t.Errorf("expected journal to keep the stages recorded after reading the status, got %s", stage)
+	}
+}
+
+func TestReadOperatorStatusReportsPartialFailures(t *testing.T) {
+	aggregator, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	aggregatorAddress := aggregator.Addr().String()
+	aggregator.Close()
+
+	status := ReadOperatorStatus(context.Background(), &fakeStatusChainReader{head: 10}, OperatorStatusConfig{
+		AggregatorAddress:      aggregatorAddress,
+		BatchJournalPath:       filepath.Join(t.TempDir(), "missing", "operator.batch_journal"),
+		UnrespondedTasksBlocks: 50,
+	})
+
+	if status.Registered || status.OperatorId != "" || len(status.StakePerQuorum) != 0 {
+		t.Errorf("expected unregistered operator, got %v", status)
+	}
+	if status.UnrespondedTasksFrom != 0 {
+		t.Errorf("expected unresponded tasks from genesis when the chain is short, got %d", status.UnrespondedTasksFrom)
+	}
+	if status.Aggregator.Reachable || status.Aggregator.Error == "" {
+		t.Errorf("expected aggregator not to be reachable")
+	}
+	// A missing journal is an operator that never ran, only the disabled verifiers could not be read
+	if status.LastProcessedBlock != 0 || len(status.Errors) != 1 {
+		t.Errorf("expected a single error, got %v", status.Errors)
+	}
+}
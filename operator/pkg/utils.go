@@ -1,9 +1,12 @@
 package operator
 
 import (
-	"github.com/yetanotherco/aligned_layer/common"
 	"math/big"
 	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/yetanotherco/aligned_layer/common"
 )
 
 func IsVerifierDisabled(disabledVerifiersBitmap *big.Int, verifierId common.ProvingSystemId) bool {
@@ -15,6 +18,18 @@ func IsVerifierDisabled(disabledVerifiersBitmap *big.Int, verifierId common.Prov
 	return bit != 0
 }
 
+// DisabledVerifierNames decodes the disabled verifiers bitmap into the names of the proving
+// systems it disables, in order of their ids.
+func DisabledVerifierNames(disabledVerifiersBitmap *big.Int) []string {
+	names := []string{}
+	for verifierId := 0; verifierId < disabledVerifiersBitmap.BitLen(); verifierId++ {
+		if disabledVerifiersBitmap.Bit(verifierId) == 1 {
+			names = append(names, provingSystemName(common.ProvingSystemId(verifierId)))
+		}
+	}
+	return names
+}
+
 func BaseUrlOnly(input string) (string, error) {
 	// https://gobyexample.com/url-parsing
 	u, err := url.Parse(input)
@@ -24,3 +39,30 @@ func BaseUrlOnly(input string) (string, error) {
 
 	return u.Host, nil
 }
+
+// writeFileAtomic writes data to a temporary file next to path and renames it over path,
+// so readers, or the operator after a crash, never find a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
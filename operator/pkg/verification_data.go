@@ -1,13 +1,90 @@
 package operator
 
 import (
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/operator/merkle"
+	"golang.org/x/crypto/sha3"
 )
 
 type VerificationData struct {
-	ProvingSystemId common.ProvingSystemId `json:"proving_system"`
-	Proof           []byte                 `json:"proof"`
-	PubInput        []byte                 `json:"pub_input"`
-	VerificationKey []byte                 `json:"verification_key"`
-	VmProgramCode   []byte                 `json:"vm_program_code"`
+	ProvingSystemId    common.ProvingSystemId `json:"proving_system"`
+	Proof              []byte                 `json:"proof"`
+	PubInput           []byte                 `json:"pub_input"`
+	VerificationKey    []byte                 `json:"verification_key"`
+	VmProgramCode      []byte                 `json:"vm_program_code"`
+	ProofGeneratorAddr string                 `json:"proof_generator_addr"`
+}
+
+// VerificationDataCommitment is what the batch merkle tree commits to for every verification
+// data, matching `VerificationDataCommitment` of the aligned sdk. These are the values expected
+// by VerifyBatchInclusion of the service manager along with the merkle proof.
+type VerificationDataCommitment struct {
+	ProofCommitment                [32]byte
+	PubInputCommitment             [32]byte
+	ProvingSystemAuxDataCommitment [32]byte
+	ProofGeneratorAddr             ethcommon.Address
+}
+
+// Commitment returns the commitment of the verification data. A missing public input or
+// auxiliary data is committed as zeroes, while an empty one is committed as the hash of no bytes.
+func (v VerificationData) Commitment() (VerificationDataCommitment, error) {
+	var commitment VerificationDataCommitment
+	if !ethcommon.IsHexAddress(v.ProofGeneratorAddr) {
+		return commitment, fmt.Errorf("invalid proof generator address %q", v.ProofGeneratorAddr)
+	}
+	commitment.ProofGeneratorAddr = ethcommon.HexToAddress(v.ProofGeneratorAddr)
+
+	commitment.ProofCommitment = keccak256(v.Proof)
+	if v.PubInput != nil {
+		commitment.PubInputCommitment = keccak256(v.PubInput)
+	}
+
+	// The auxiliary data is the program code for zkVMs, and the verification key for the rest of proving systems
+	provingSystemByte := []byte{byte(v.ProvingSystemId)}
+	if v.VmProgramCode != nil {
+		commitment.ProvingSystemAuxDataCommitment = keccak256(v.VmProgramCode, provingSystemByte)
+	} else if v.VerificationKey != nil {
+		commitment.ProvingSystemAuxDataCommitment = keccak256(v.VerificationKey, provingSystemByte)
+	}
+	return commitment, nil
+}
+
+// LeafHash returns the leaf of the commitment in the batch merkle tree.
+func (c VerificationDataCommitment) LeafHash() [32]byte {
+	return keccak256(c.ProofCommitment[:], c.PubInputCommitment[:], c.ProvingSystemAuxDataCommitment[:], c.ProofGeneratorAddr[:])
+}
+
+// LeafHash returns the leaf of the verification data in the batch merkle tree.
+func (v VerificationData) LeafHash() ([32]byte, error) {
+	commitment, err := v.Commitment()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return commitment.LeafHash(), nil
+}
+
+// NewBatchMerkleTree builds the merkle tree of a batch, whose root is the batch merkle root.
+func NewBatchMerkleTree(verificationDataBatch []VerificationData) (*merkle.Tree, error) {
+	leaves := make([][32]byte, len(verificationDataBatch))
+	for i, verificationData := range verificationDataBatch {
+		leaf, err := verificationData.LeafHash()
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		leaves[i] = leaf
+	}
+	return merkle.NewTree(leaves)
+}
+
+func keccak256(data ...[]byte) [32]byte {
+	var hash [32]byte
+	hasher := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		hasher.Write(d)
+	}
+	hasher.Sum(hash[:0])
+	return hash
 }
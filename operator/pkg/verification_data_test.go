@@ -0,0 +1,64 @@
+package operator
+
+import (
+	"io"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+func TestNewBatchMerkleTree(t *testing.T) {
+	batch, expectedMerkleRoot := readTestBatch(t)
+	verificationDataBatch, err := decodeBatch(batch, logging.NewTextSLogger(io.Discard, nil))
+	if err != nil {
+		t.Fatalf("could not decode test batch: %v", err)
+	}
+
+	tree, err := NewBatchMerkleTree(verificationDataBatch)
+	if err != nil {
+		t.Fatalf("could not build batch merkle tree: %v", err)
+	}
+	if tree.Root() != expectedMerkleRoot {
+		t.Fatalf("expected merkle root %x, got %x", expectedMerkleRoot, tree.Root())
+	}
+
+	for i, verificationData := range verificationDataBatch {
+		commitment, err := verificationData.Commitment()
+		if err != nil {
+			t.Fatalf("could not commit to entry %d: %v", i, err)
+		}
+		proof, err := tree.Proof(i)
+		if err != nil {
+			t.Fatalf("could not get proof of entry %d: %v", i, err)
+		}
+		if !proof.Verify(expectedMerkleRoot, commitment.LeafHash(), uint64(i)) {
+			t.Errorf("proof of entry %d does not verify", i)
+		}
+	}
+}
+
+func TestVerificationDataCommitment(t *testing.T) {
+	verificationData := VerificationData{
+		Proof:              []byte{1, 2, 3},
+		ProofGeneratorAddr: "0x66f9664f97f2b50f62d13ea064982f936de76657",
+	}
+	missing, err := verificationData.Commitment()
+	if err != nil {
+		t.Fatalf("could not commit to verification data: %v", err)
+	}
+	if missing.PubInputCommitment != [32]byte{} || missing.ProvingSystemAuxDataCommitment != [32]byte{} {
+		t.Errorf("expected missing public input and auxiliary data to be committed as zeroes")
+	}
+
+	verificationData.PubInput = []byte{}
+	verificationData.VerificationKey = []byte{}
+	empty, _ := verificationData.Commitment()
+	if empty.PubInputCommitment == [32]byte{} || empty.ProvingSystemAuxDataCommitment == [32]byte{} {
+		t.Errorf("expected empty public input and auxiliary data to be committed as their hash")
+	}
+
+	verificationData.ProofGeneratorAddr = "not an address"
+	if _, err := verificationData.LeafHash(); err == nil {
+		t.Errorf("expected invalid proof generator address to be rejected")
+	}
+}
@@ -0,0 +1,165 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+var (
+	ErrUnknownProvingSystem = errors.New("unrecognized proving system")
+	ErrMissingInput         = errors.New("missing verification input")
+	ErrProofDeserialization = errors.New("could not deserialize verification data")
+	ErrVerifierPanic        = errors.New("verifier panicked")
+)
+
+// Verifier verifies proofs for a single version of a proving system.
+type Verifier interface {
+	// Version identifies the verifier implementation, e.g. "sp1" or "sp1_old".
+	Version() string
+	// Verify returns whether the proof is valid. Implementations should stop
+	// working and return ctx.Err() as soon as ctx is cancelled, when possible.
+	Verify(ctx context.Context, verificationData VerificationData) (bool, error)
+}
+
+type verifierFunc struct {
+	version string
+	verify  func(ctx context.Context, verificationData VerificationData) (bool, error)
+}
+
+// NewVerifier wraps a verification function so it can be registered as a Verifier.
+func NewVerifier(version string, verify func(ctx context.Context, verificationData VerificationData) (bool, error)) Verifier {
+	return &verifierFunc{version: version, verify: verify}
+}
+
+func (v *verifierFunc) Version() string {
+	return v.version
+}
+
+func (v *verifierFunc) Verify(ctx context.Context, verificationData VerificationData) (bool, error) {
+	return v.verify(ctx, verificationData)
+}
+
+// InputRequirements declares which VerificationData fields a proving system needs
+// besides the proof itself, which is always required.
+type InputRequirements struct {
+	PubInput        bool
+	VerificationKey bool
+	VmProgramCode   bool
+}
+
+func (r InputRequirements) Check(verificationData VerificationData) error {
+	if len(verificationData.Proof) == 0 {
+		return fmt.Errorf("%w: proof", ErrMissingInput)
+	}
+	if r.PubInput && len(verificationData.PubInput) == 0 {
+		return fmt.Errorf("%w: public input", ErrMissingInput)
+	}
+	if r.VerificationKey && len(verificationData.VerificationKey) == 0 {
+		return fmt.Errorf("%w: verification key", ErrMissingInput)
+	}
+	if r.VmProgramCode && len(verificationData.VmProgramCode) == 0 {
+		return fmt.Errorf("%w: vm program code", ErrMissingInput)
+	}
+	return nil
+}
+
+// VerifierEntry describes how the proofs of a proving system are verified.
+// Versions are tried in order: the first one is the current verifier and the
+// remaining ones are its fallback chain, used when the previous version rejects the proof.
+type VerifierEntry struct {
+	ProvingSystemId common.ProvingSystemId
+	Versions        []Verifier
+	Requirements    InputRequirements
+}
+
+// FallbackChain returns the versions that are tried, in order, after the current one.
+func (e VerifierEntry) FallbackChain() []string {
+	var chain []string
+	if len(e.Versions) < 2 {
+		return chain
+	}
+	for _, verifier := range e.Versions[1:] {
+		chain = append(chain, verifier.Version())
+	}
+	return chain
+}
+
+// VerificationResult is the outcome of verifying a proof through the registry.
+type VerificationResult struct {
+	Verified bool
+	// Version of the verifier that accepted the proof. Empty if the proof was rejected.
+	Version string
+	// Err holds the last error returned while verifying, if any.
+	Err error
+}
+
+// VerifierRegistry maps each proving system to the verifiers able to check its proofs.
+type VerifierRegistry struct {
+	mutex   sync.RWMutex
+	entries map[common.ProvingSystemId]VerifierEntry
+}
+
+func NewVerifierRegistry() *VerifierRegistry {
+	return &VerifierRegistry{
+		entries: make(map[common.ProvingSystemId]VerifierEntry),
+	}
+}
+
+// Register adds the verifiers of a proving system to the registry.
+// Registering the same proving system twice is an error.
+func (r *VerifierRegistry) Register(entry VerifierEntry) error {
+	if len(entry.Versions) == 0 {
+		return fmt.Errorf("no verifier versions provided for proving system %d", entry.ProvingSystemId)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.entries[entry.ProvingSystemId]; ok {
+		return fmt.Errorf("proving system %d already has registered verifiers", entry.ProvingSystemId)
+	}
+	r.entries[entry.ProvingSystemId] = entry
+	return nil
+}
+
+func (r *VerifierRegistry) Entry(provingSystemId common.ProvingSystemId) (VerifierEntry, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, ok := r.entries[provingSystemId]
+	return entry, ok
+}
+
+// Verify checks the input requirements of the proof and runs it through the
+// registered versions until one of them accepts it. No further version is tried
+// once ctx is cancelled, in which case the result holds ctx.Err().
+func (r *VerifierRegistry) Verify(ctx context.Context, verificationData VerificationData) VerificationResult {
+	entry, ok := r.Entry(verificationData.ProvingSystemId)
+	if !ok {
+		return VerificationResult{Err: fmt.Errorf("%w: %d", ErrUnknownProvingSystem, verificationData.ProvingSystemId)}
+	}
+
+	if err := entry.Requirements.Check(verificationData); err != nil {
+		return VerificationResult{Err: err}
+	}
+
+	var lastErr error
+	for _, verifier := range entry.Versions {
+		if err := ctx.Err(); err != nil {
+			return VerificationResult{Err: err}
+		}
+		verified, err := verifier.Verify(ctx, verificationData)
+		if verified {
+			return VerificationResult{Verified: true, Version: verifier.Version()}
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", verifier.Version(), err)
+		}
+	}
+
+	return VerificationResult{Err: lastErr}
+}
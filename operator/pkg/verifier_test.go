@@ -0,0 +1,142 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/yetanotherco/aligned_layer/common"
+)
+
+const Groth16ProofFilePath = "../../scripts/test_files/gnark_groth16_bn254_script/groth16.proof"
+const Groth16PubInputFilePath = "../../scripts/test_files/gnark_groth16_bn254_script/groth16.pub"
+const Groth16VerificationKeyFilePath = "../../scripts/test_files/gnark_groth16_bn254_script/groth16.vk"
+
+func TestVerifierRegistryFallbackChain(t *testing.T) {
+	var calls []string
+	fakeVerifier := func(version string, verified bool, err error) Verifier {
+		return NewVerifier(version, func(context.Context, VerificationData) (bool, error) {
+			calls = append(calls, version)
+			return verified, err
+		})
+	}
+
+	registry := NewVerifierRegistry()
+	err := registry.Register(VerifierEntry{
+		ProvingSystemId: common.SP1,
+		Versions: []Verifier{
+			fakeVerifier("current", false, errors.New("unsupported proof")),
+			fakeVerifier("old", true, nil),
+			fakeVerifier("older", true, nil),
+		},
+		Requirements: InputRequirements{VmProgramCode: true},
+	})
+	if err != nil {
+		t.Fatalf("could not register verifier: %v", err)
+	}
+
+	result := registry.Verify(context.Background(), VerificationData{ProvingSystemId: common.SP1, Proof: []byte{1}, VmProgramCode: []byte{1}})
+	if !result.Verified || result.Version != "old" {
+		t.Errorf("expected proof to be accepted by version old, got %+v", result)
+	}
+	if len(calls) != 2 {
+		t.Errorf("expected fallback chain to stop after the accepting version, called %v", calls)
+	}
+
+	entry, _ := registry.Entry(common.SP1)
+	if chain := entry.FallbackChain(); len(chain) != 2 || chain[0] != "old" || chain[1] != "older" {
+		t.Errorf("unexpected fallback chain %v", chain)
+	}
+}
+
+func TestVerifierRegistryCancelled(t *testing.T) {
+	called := false
+	registry := NewVerifierRegistry()
+	err := registry.Register(VerifierEntry{
+		ProvingSystemId: common.SP1,
+		Versions: []Verifier{NewVerifier("current", func(context.Context, VerificationData) (bool, error) {
+			called = true
+			return true, nil
+		})},
+	})
+	if err != nil {
+		t.Fatalf("could not register verifier: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := registry.Verify(ctx, VerificationData{ProvingSystemId: common.SP1, Proof: []byte{1}})
+	if result.Verified || !errors.Is(result.Err, context.Canceled) || called {
+		t.Errorf("expected verification to be skipped, got %+v (verifier called: %t)", result, called)
+	}
+}
+
+func TestVerifierRegistryRejections(t *testing.T) {
+	registry := NewVerifierRegistry()
+	rejectAll := NewVerifier("current", func(context.Context, VerificationData) (bool, error) { return false, nil })
+	err := registry.Register(VerifierEntry{
+		ProvingSystemId: common.Groth16Bn254,
+		Versions:        []Verifier{rejectAll},
+		Requirements:    InputRequirements{PubInput: true, VerificationKey: true},
+	})
+	if err != nil {
+		t.Fatalf("could not register verifier: %v", err)
+	}
+
+	if err := registry.Register(VerifierEntry{ProvingSystemId: common.Groth16Bn254, Versions: []Verifier{rejectAll}}); err == nil {
+		t.Errorf("registering a proving system twice should fail")
+	}
+
+	result := registry.Verify(context.Background(), VerificationData{ProvingSystemId: common.Risc0, Proof: []byte{1}})
+	if result.Verified || !errors.Is(result.Err, ErrUnknownProvingSystem) {
+		t.Errorf("expected unknown proving system error, got %+v", result)
+	}
+
+	result = registry.Verify(context.Background(), VerificationData{ProvingSystemId: common.Groth16Bn254, Proof: []byte{1}, PubInput: []byte{1}})
+	if result.Verified || !errors.Is(result.Err, ErrMissingInput) {
+		t.Errorf("expected missing input error, got %+v", result)
+	}
+
+	result = registry.Verify(context.Background(), VerificationData{ProvingSystemId: common.Groth16Bn254, Proof: []byte{1}, PubInput: []byte{1}, VerificationKey: []byte{1}})
+	if result.Verified || result.Err != nil || result.Version != "" {
+		t.Errorf("expected plain rejection, got %+v", result)
+	}
+}
+
+func TestDefaultVerifierRegistryVerifiesGroth16Proof(t *testing.T) {
+	proof, err := os.ReadFile(Groth16ProofFilePath)
+	if err != nil {
+		t.Fatalf("could not open proof file: %s", err)
+	}
+	pubInput, err := os.ReadFile(Groth16PubInputFilePath)
+	if err != nil {
+		t.Fatalf("could not open public input file: %s", err)
+	}
+	verificationKey, err := os.ReadFile(Groth16VerificationKeyFilePath)
+	if err != nil {
+		t.Fatalf("could not open verification key file: %s", err)
+	}
+
+	registry, err := NewDefaultVerifierRegistry()
+	if err != nil {
+		t.Fatalf("could not create default registry: %v", err)
+	}
+
+	data := VerificationData{
+		ProvingSystemId: common.Groth16Bn254,
+		Proof:           proof,
+		PubInput:        pubInput,
+		VerificationKey: verificationKey,
+	}
+	result := registry.Verify(context.Background(), data)
+	if !result.Verified || result.Version != GnarkVerifierVersion {
+		t.Errorf("expected proof to verify with version %s, got %+v", GnarkVerifierVersion, result)
+	}
+
+	data.Proof = proof[:len(proof)/2]
+	result = registry.Verify(context.Background(), data)
+	if result.Verified || result.Err == nil {
+		t.Errorf("expected truncated proof to fail deserialization, got %+v", result)
+	}
+}
@@ -0,0 +1,174 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/yetanotherco/aligned_layer/common"
+	"github.com/yetanotherco/aligned_layer/operator/risc_zero"
+	"github.com/yetanotherco/aligned_layer/operator/risc_zero_old"
+	"github.com/yetanotherco/aligned_layer/operator/sp1"
+	"github.com/yetanotherco/aligned_layer/operator/sp1_old"
+)
+
+// Versions of the built-in verifiers. They match the package that implements each of them.
+const (
+	GnarkVerifierVersion       = "gnark"
+	Sp1VerifierVersion         = "sp1"
+	Sp1OldVerifierVersion      = "sp1_old"
+	RiscZeroVerifierVersion    = "risc_zero"
+	RiscZeroOldVerifierVersion = "risc_zero_old"
+)
+
+// NewDefaultVerifierRegistry returns a registry with the verifiers of every proving system supported by Aligned.
+func NewDefaultVerifierRegistry() (*VerifierRegistry, error) {
+	gnarkRequirements := InputRequirements{PubInput: true, VerificationKey: true}
+	// Risc0 allows an empty public input
+	vmRequirements := InputRequirements{VmProgramCode: true}
+
+	entries := []VerifierEntry{
+		{
+			ProvingSystemId: common.GnarkPlonkBls12_381,
+			Versions:        []Verifier{NewVerifier(GnarkVerifierVersion, plonkVerifier(ecc.BLS12_381))},
+			Requirements:    gnarkRequirements,
+		},
+		{
+			ProvingSystemId: common.GnarkPlonkBn254,
+			Versions:        []Verifier{NewVerifier(GnarkVerifierVersion, plonkVerifier(ecc.BN254))},
+			Requirements:    gnarkRequirements,
+		},
+		{
+			ProvingSystemId: common.Groth16Bn254,
+			Versions:        []Verifier{NewVerifier(GnarkVerifierVersion, groth16Verifier(ecc.BN254))},
+			Requirements:    gnarkRequirements,
+		},
+		{
+			ProvingSystemId: common.SP1,
+			Versions: []Verifier{
+				NewVerifier(Sp1VerifierVersion, func(_ context.Context, data VerificationData) (bool, error) {
+					return ffiVerificationResult(sp1.VerifySp1Proof(data.Proof, data.VmProgramCode))
+				}),
+				NewVerifier(Sp1OldVerifierVersion, func(_ context.Context, data VerificationData) (bool, error) {
+					return ffiVerificationResult(sp1_old.VerifySp1ProofOld(data.Proof, data.VmProgramCode))
+				}),
+			},
+			Requirements: vmRequirements,
+		},
+		{
+			ProvingSystemId: common.Risc0,
+			Versions: []Verifier{
+				NewVerifier(RiscZeroVerifierVersion, func(_ context.Context, data VerificationData) (bool, error) {
+					return ffiVerificationResult(risc_zero.VerifyRiscZeroReceipt(data.Proof, data.VmProgramCode, data.PubInput))
+				}),
+				NewVerifier(RiscZeroOldVerifierVersion, func(_ context.Context, data VerificationData) (bool, error) {
+					return ffiVerificationResult(risc_zero_old.VerifyRiscZeroReceiptOld(data.Proof, data.VmProgramCode, data.PubInput))
+				}),
+			},
+			Requirements: vmRequirements,
+		},
+	}
+
+	registry := NewVerifierRegistry()
+	for _, entry := range entries {
+		if err := registry.Register(entry); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// ffiVerificationResult tags the errors of the FFI verifiers, which are only returned
+// when a panic was caught either on the Go side or on the Rust side of the FFI.
+func ffiVerificationResult(verified bool, err error) (bool, error) {
+	if err != nil {
+		return verified, fmt.Errorf("%w: %v", ErrVerifierPanic, err)
+	}
+	return verified, nil
+}
+
+func plonkVerifier(curve ecc.ID) func(context.Context, VerificationData) (bool, error) {
+	return func(ctx context.Context, data VerificationData) (bool, error) {
+		return verifyPlonkProof(ctx, data.Proof, data.PubInput, data.VerificationKey, curve)
+	}
+}
+
+func groth16Verifier(curve ecc.ID) func(context.Context, VerificationData) (bool, error) {
+	return func(ctx context.Context, data VerificationData) (bool, error) {
+		return verifyGroth16Proof(ctx, data.Proof, data.PubInput, data.VerificationKey, curve)
+	}
+}
+
+// verifyPlonkProof contains the common PLONK proof verification logic.
+// Deserialization failures are returned as errors, a proof that does not verify is not.
+// Nothing is deserialized nor verified if ctx has already been cancelled.
+func verifyPlonkProof(ctx context.Context, proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte, curve ecc.ID) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	proofReader := bytes.NewReader(proofBytes)
+	proof := plonk.NewProof(curve)
+	if _, err := proof.ReadFrom(proofReader); err != nil {
+		return false, fmt.Errorf("%w: PLONK proof: %v", ErrProofDeserialization, err)
+	}
+
+	pubInputReader := bytes.NewReader(pubInputBytes)
+	pubInput, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return false, fmt.Errorf("error instantiating witness: %w", err)
+	}
+	if _, err = pubInput.ReadFrom(pubInputReader); err != nil {
+		return false, fmt.Errorf("%w: PLONK public input: %v", ErrProofDeserialization, err)
+	}
+
+	verificationKeyReader := bytes.NewReader(verificationKeyBytes)
+	verificationKey := plonk.NewVerifyingKey(curve)
+	if _, err = verificationKey.ReadFrom(verificationKeyReader); err != nil {
+		return false, fmt.Errorf("%w: PLONK verifying key: %v", ErrProofDeserialization, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	err = plonk.Verify(proof, verificationKey, pubInput)
+	return err == nil, nil
+}
+
+// verifyGroth16Proof contains the common Groth16 proof verification logic.
+// Deserialization failures are returned as errors, a proof that does not verify is not.
+// Nothing is deserialized nor verified if ctx has already been cancelled.
+func verifyGroth16Proof(ctx context.Context, proofBytes []byte, pubInputBytes []byte, verificationKeyBytes []byte, curve ecc.ID) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	proofReader := bytes.NewReader(proofBytes)
+	proof := groth16.NewProof(curve)
+	if _, err := proof.ReadFrom(proofReader); err != nil {
+		return false, fmt.Errorf("%w: Groth16 proof: %v", ErrProofDeserialization, err)
+	}
+
+	pubInputReader := bytes.NewReader(pubInputBytes)
+	pubInput, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return false, fmt.Errorf("error instantiating witness: %w", err)
+	}
+	if _, err = pubInput.ReadFrom(pubInputReader); err != nil {
+		return false, fmt.Errorf("%w: Groth16 public input: %v", ErrProofDeserialization, err)
+	}
+
+	verificationKeyReader := bytes.NewReader(verificationKeyBytes)
+	verificationKey := groth16.NewVerifyingKey(curve)
+	if _, err = verificationKey.ReadFrom(verificationKeyReader); err != nil {
+		return false, fmt.Errorf("%w: Groth16 verifying key: %v", ErrProofDeserialization, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	err = groth16.Verify(proof, verificationKey, pubInput)
+	return err == nil, nil
+}